@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package arg
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+var (
+	weeksAndDaysPattern = regexp.MustCompile(`^(?:(\d+(?:\.\d+)?)w)?(?:(\d+(?:\.\d+)?)d)?(.*)$`)
+	iso8601Pattern      = regexp.MustCompile(`^P(?:(\d+(?:\.\d+)?)W)?(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+)
+
+// ParseDuration parses a non-negative duration given as a number of hours, such as 336 or 1.5, as a Go duration
+// which may also use days and weeks, such as 36h, 7d, or 2w3d12h, or as an ISO 8601 duration without years or
+// months, such as P7DT12H.
+func ParseDuration(duration string) (time.Duration, error) {
+	if hours, err := strconv.ParseFloat(duration, 64); err == nil {
+		if hours < 0 {
+			return 0, fmt.Errorf("negative duration: %s", duration)
+		}
+		return scale(hours, time.Hour), nil
+	}
+
+	if match := iso8601Pattern.FindStringSubmatch(duration); match != nil && duration != "P" && duration != "PT" {
+		return sum(match[1:], week, day, time.Hour, time.Minute, time.Second), nil
+	}
+
+	match := weeksAndDaysPattern.FindStringSubmatch(duration)
+	if match == nil || duration == "" {
+		return 0, fmt.Errorf("invalid duration: %s", duration)
+	}
+
+	parsed := sum(match[1:3], week, day)
+	if rest := match[3]; rest != "" {
+		remainder, err := time.ParseDuration(rest)
+		if err != nil || (rest[0] == '-' || rest[0] == '+') {
+			return 0, fmt.Errorf("invalid duration: %s", duration)
+		}
+		parsed += remainder
+	}
+	return parsed, nil
+}
+
+// sum adds up the given numbers of the corresponding units, ignoring empty numbers.
+func sum(numbers []string, units ...time.Duration) (total time.Duration) {
+	for i, number := range numbers {
+		if number == "" {
+			continue
+		}
+		value, _ := strconv.ParseFloat(number, 64)
+		total += scale(value, units[i])
+	}
+	return
+}
+
+func scale(value float64, unit time.Duration) time.Duration {
+	return time.Duration(value * float64(unit))
+}
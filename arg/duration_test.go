@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package arg_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/service-instance-reaper/arg"
+	"time"
+)
+
+var _ = Describe("ParseDuration", func() {
+	expectDuration := func(duration string, expected time.Duration) {
+		parsed, err := arg.ParseDuration(duration)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed).To(Equal(expected))
+	}
+
+	It("parses a number of hours", func() {
+		expectDuration("336", 336*time.Hour)
+		expectDuration("1.5", 90*time.Minute)
+		expectDuration("0", 0)
+	})
+
+	It("keeps the precision of large numbers of hours", func() {
+		expectDuration("87600.25", 87600*time.Hour+15*time.Minute)
+	})
+
+	It("parses Go durations", func() {
+		expectDuration("36h", 36*time.Hour)
+		expectDuration("1h30m", 90*time.Minute)
+	})
+
+	It("parses days and weeks", func() {
+		expectDuration("7d", 7*24*time.Hour)
+		expectDuration("2w", 14*24*time.Hour)
+		expectDuration("1w2d12h", 9*24*time.Hour+12*time.Hour)
+	})
+
+	It("parses ISO 8601 durations", func() {
+		expectDuration("P7DT12H", 7*24*time.Hour+12*time.Hour)
+		expectDuration("P2W", 14*24*time.Hour)
+		expectDuration("PT90M", 90*time.Minute)
+	})
+
+	It("rejects invalid durations", func() {
+		for _, duration := range []string{"", "-1", "-5h", "1w-2h", "seven days", "P", "PT", "P1M", "P1Y", "7x"} {
+			_, err := arg.ParseDuration(duration)
+			Expect(err).To(HaveOccurred(), duration)
+		}
+	})
+})
@@ -0,0 +1,37 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package arg
+
+// Exit codes distinguish the ways in which a run can fail, so that pipelines can react to them differently.
+const (
+	// ExitSuccess indicates that everything eligible was reaped, or would have been in a dry run.
+	ExitSuccess = 0
+
+	// ExitFailure indicates that some resources could not be deleted or that some other error occurred.
+	ExitFailure = 1
+
+	// ExitConfigurationError indicates invalid arguments, failed authentication, or an unsuitable environment.
+	// Nothing was reaped.
+	ExitConfigurationError = 2
+
+	// ExitListingError indicates that some resources could not be listed or inspected, so may not have been reaped.
+	ExitListingError = 3
+
+	// ExitSafetyCapExceeded indicates that more service instances would have been deleted than -max-deletions
+	// allows. Nothing was deleted.
+	ExitSafetyCapExceeded = 4
+)
@@ -17,61 +17,739 @@
 package arg
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/calendar"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"github.com/pivotal-cf/service-instance-reaper/inventory"
+	"github.com/pivotal-cf/service-instance-reaper/logging"
+	"github.com/pivotal-cf/service-instance-reaper/reaper"
 	"io"
 	"net/url"
-	"strconv"
+	"regexp"
+	"strings"
 	"time"
 )
 
-func Parse(args []string, output io.Writer, exit func(int)) (username string, password string, skipSslValidation bool, reap, recursive bool, apiUrl string, serviceName string, planName string, expiryInterval time.Duration) {
+const (
+	ReapCommand    = "reap"
+	RestoreCommand = "restore"
+	HistoryCommand = "history"
+	ReportCommand  = "report"
+)
+
+type Arguments struct {
+	Command                  string
+	Username                 string
+	Password                 string
+	SkipSslValidation        bool
+	ApiUrl                   string
+	ServiceName              string
+	PlanName                 string
+	ExpiryInterval           time.Duration
+	Cutoff                   time.Time
+	BusinessDays             bool
+	HolidaysFile             string
+	Timezone                 *time.Location
+	MaxClockSkew             time.Duration
+	Windows                  []calendar.Window
+	Blackouts                []calendar.Blackout
+	AgeBasis                 reaper.AgeBasis
+	Reap                     bool
+	MaxDeletions             int
+	FailFast                 bool
+	Interactive              bool
+	InteractiveBatch         bool
+	Recursive                bool
+	CascadeAttempts          int
+	UnboundOnly              bool
+	WithoutServiceKeys       bool
+	UserProvided             bool
+	Apps                     bool
+	AppStates                []string
+	DeleteRoutes             bool
+	NamePattern              *regexp.Regexp
+	SpaceGuid                string
+	ProtectionTag            string
+	QuotaThreshold           int
+	QuotaTarget              int
+	KeepNewest               int
+	KeepGrouping             reaper.Grouping
+	ServiceKeyExpiryInterval time.Duration
+	ServiceKeyNamePattern    *regexp.Regexp
+	EmptySpaceNamePattern    *regexp.Regexp
+	EmptySpaceExpiryInterval time.Duration
+	LastOperationStates      []string
+	Purge                    bool
+	Force                    bool
+	PurgeOrphans             bool
+	AuditLog                 string
+	SnapshotDirectory        string
+	StateDirectory           string
+	ResumeRunID              string
+	HistoryFile              string
+	HistoryWeeks             int
+	HistoryTop               int
+	ReportFormat             inventory.Format
+	PricesFile               string
+	SnapshotFile             string
+	LogLevel                 logging.Level
+	LogFormat                logging.Format
+	Trace                    bool
+}
+
+func Parse(args []string, output io.Writer, exit func(int)) (arguments Arguments) {
+	if len(args) > 1 && args[1] == RestoreCommand {
+		return parseRestore(args, output, exit)
+	}
+	if len(args) > 1 && args[1] == HistoryCommand {
+		return parseHistory(args, output, exit)
+	}
+	if len(args) > 1 && args[1] == ReportCommand {
+		return parseReport(args, output, exit)
+	}
+
+	arguments.Command = ReapCommand
+
 	commandLine := flag.NewFlagSet(args[0], flag.ExitOnError)
 	commandLine.SetOutput(output)
-	commandLine.StringVar(&username, "u", "", "username")
-	commandLine.StringVar(&password, "p", "", "password")
-	commandLine.BoolVar(&skipSslValidation, "skip-ssl-validation", false, "Skip verification of the API endpoint. Not recommended!")
-	commandLine.BoolVar(&reap, "reap", false, "Reap service instances. Otherwise perform a dry run only.")
-	commandLine.BoolVar(&recursive, "recursive", false, "Also deletes any service bindings, service keys, and routes associated with reaped service instances.")
+	addConnectionFlags(commandLine, &arguments)
+	logLevel, logFormat := addLoggingFlags(commandLine, &arguments)
+	commandLine.BoolVar(&arguments.Reap, "reap", false, "Reap service instances. Otherwise perform a dry run only.")
+	commandLine.IntVar(&arguments.MaxDeletions, "max-deletions", 0, "Delete no service instances, or apps, if more than the given number would be deleted, and likewise no service keys or empty spaces.")
+	commandLine.BoolVar(&arguments.FailFast, "fail-fast", false, "Stop reaping at the first error listing or inspecting resources. Otherwise such errors are reported and reaping continues.")
+	commandLine.BoolVar(&arguments.Interactive, "interactive", false, "Ask before deleting each service instance, app, service key, or empty space, showing its age, space, and number of service bindings, on stderr. Requires a terminal.")
+	commandLine.BoolVar(&arguments.InteractiveBatch, "interactive-batch", false, "With -interactive, list all the service instances or apps to be deleted and ask only once.")
+	commandLine.BoolVar(&arguments.Recursive, "recursive", false, "Also deletes any service bindings, service keys, and route bindings associated with reaped service instances, one at a time, before deleting the service instances.")
+	commandLine.IntVar(&arguments.CascadeAttempts, "cascade-attempts", 3, "Number of times to attempt each deletion made by -recursive.")
+	commandLine.BoolVar(&arguments.UnboundOnly, "unbound-only", false, "Only reap service instances with no service bindings, regardless of -recursive.")
+	commandLine.BoolVar(&arguments.WithoutServiceKeys, "without-service-keys", false, "Only reap service instances with no service keys.")
+	commandLine.BoolVar(&arguments.UserProvided, "user-provided", false, "Reap user-provided service instances. SERVICE_NAME and PLAN_NAME must then be omitted.")
+	commandLine.BoolVar(&arguments.Apps, "apps", false, "Reap apps rather than service instances. SERVICE_NAME and PLAN_NAME must then be omitted, and -name-pattern or -space-guid given.")
+	appStates := commandLine.String("app-state", "", "With -apps, only reap apps in one of the given comma-separated states: STARTED or STOPPED.")
+	commandLine.BoolVar(&arguments.DeleteRoutes, "delete-routes", false, "With -apps, also delete the routes of reaped apps which are not mapped to any other app.")
+	namePattern := commandLine.String("name-pattern", "", "Only reap service instances or apps whose name matches the given regular expression.")
+	commandLine.StringVar(&arguments.SpaceGuid, "space-guid", "", "Only reap service instances or apps in the space with the given guid.")
+	commandLine.StringVar(&arguments.ProtectionTag, "protect-tag", "do-not-reap", "Never reap service instances with the given tag. Set to the empty string to disable.")
+	commandLine.IntVar(&arguments.QuotaThreshold, "quota-threshold", 0, "Only reap in spaces whose service instances exceed the given percentage of their quota, and then only the oldest eligible service instances needed to reach -quota-target.")
+	commandLine.IntVar(&arguments.QuotaTarget, "quota-target", 0, "Percentage of its quota to which -quota-threshold reduces the service instances in a space.")
+	commandLine.IntVar(&arguments.KeepNewest, "keep-newest", 0, "Never reap the given number of newest service instances in each group. Use with an age of 0 to reap the rest regardless of age.")
+	keepGrouping := commandLine.String("keep-group-by", string(reaper.BySpace), "How service instances are grouped by -keep-newest within each space: space, name_prefix (the name up to its last hyphen, so perf-db-1 and perf-db-2 share perf-db), tags (the set of service tags), or label:KEY (the value of the metadata label KEY).")
+	serviceKeyAge := commandLine.String("service-key-age", "", "Also reap service keys older than the given duration from service instances in scope, without deleting the service instances.")
+	serviceKeyNamePattern := commandLine.String("service-key-name-pattern", "", "With -service-key-age, only reap service keys whose name matches the given regular expression.")
+	emptySpaceNamePattern := commandLine.String("empty-space-name-pattern", "", "After reaping, also delete spaces whose name matches the given regular expression and which contain no apps, service instances, or routes.")
+	emptySpaceAge := commandLine.String("empty-space-age", "", "Only delete empty spaces older than the given duration.")
+	lastOperationStates := commandLine.String("last-operation-state", "", "Only reap service instances whose last operation is in one of the given comma-separated states: failed, in_progress, or succeeded.")
+	commandLine.BoolVar(&arguments.Purge, "purge", false, "Purge service instances which their broker fails to delete. Requires -last-operation-state limited to failed and in_progress.")
+	commandLine.BoolVar(&arguments.PurgeOrphans, "purge-orphans", false, "Purge service instances whose broker cannot be reached when deleting them. Requires -confirm-purge.")
+	confirmPurge := commandLine.Bool("confirm-purge", false, "Confirm that service instances may be purged, leaving any resources they hold in their service unreclaimed.")
+	commandLine.StringVar(&arguments.AuditLog, "audit-log", "", "File to which deletions and purges are appended as JSON lines.")
+	commandLine.StringVar(&arguments.SnapshotDirectory, "snapshot-dir", "", "Directory in which to save a snapshot of each service instance before it is reaped, for use with the restore command.")
+	commandLine.StringVar(&arguments.StateDirectory, "state-dir", "", "Directory in which to checkpoint the progress of each run, in a state file named after its run ID, so that it can be resumed if interrupted.")
+	commandLine.StringVar(&arguments.HistoryFile, "history", "", "File to which the results of each run are appended, one JSON object per line, for use with the history command.")
+	addPricesFlag(commandLine, &arguments)
+	commandLine.StringVar(&arguments.ResumeRunID, "resume", "", "Resume the interrupted run with the given run ID from its checkpoint in -state-dir, skipping the service instances or apps it deleted. Give the same arguments as the interrupted run: the resumed run is refused if its service, plan, age, filters, -max-deletions, or -reap differ. Its remaining candidates are checked again, except against -keep-newest and -quota-threshold.")
+	commandLine.BoolVar(&arguments.BusinessDays, "business-days", false, "Measure age in business days only, excluding weekends and holidays.")
+	commandLine.StringVar(&arguments.HolidaysFile, "holidays", "", "File listing holidays for -business-days, either as an iCalendar file or one 2006-01-02 date per line.")
+	timezone := commandLine.String("timezone", "UTC", "Time zone in which -business-days determines weekends and holidays, and in which -window and -blackout are interpreted, such as Europe/London.")
+	var windows, blackouts repeatedFlag
+	commandLine.Var(&windows, "window", "Only delete during the given maintenance window, such as \"Mon-Fri 09:00-17:00\", and otherwise perform a dry run. Deletion stops if the window closes during the run. May be repeated.")
+	commandLine.Var(&blackouts, "blackout", "Never delete on the given date, such as 2026-12-25, or during the given range of dates, such as 2026-12-20/2027-01-03, and instead perform a dry run. May be repeated.")
+	maxClockSkew := commandLine.String("max-clock-skew", "", "Abort if the local clock differs from the Cloud Controller's by more than the given duration. Ages are always measured by the Cloud Controller's clock.")
+	createdBefore := commandLine.String("created-before", "", "Reap resources whose age is measured from a time before the given RFC 3339 timestamp, such as 2026-01-01T00:00:00Z. AGE must then be omitted.")
+	ageBasis := commandLine.String("age-basis", string(reaper.CreatedAt), "Time from which the age of a service instance is measured: created_at, updated_at, last_operation, or last_bound.")
 	commandLine.Parse(args[1:])
 
 	positionalArgs := commandLine.Args()
-	if len(positionalArgs) != 4 || positionalArgs[0] == "help" {
+	expectedPositionalArgs := 4
+	if arguments.UserProvided || arguments.Apps {
+		expectedPositionalArgs = 2
+	}
+	if *createdBefore != "" {
+		expectedPositionalArgs--
+	}
+	if !checkPositionalArgs(positionalArgs, expectedPositionalArgs, output, func() { printUsage(output, commandLine) }, exit) {
+		return
+	}
+
+	apiUrl, ok := parseApiUrl(positionalArgs[0], output, func() { printUsage(output, commandLine) }, exit)
+	if !ok {
+		return
+	}
+	if !parseLogging(*logLevel, *logFormat, &arguments, output, func() { printUsage(output, commandLine) }, exit) {
+		return
+	}
+	arguments.ApiUrl = apiUrl
+
+	if !arguments.UserProvided && !arguments.Apps {
+		arguments.ServiceName = positionalArgs[1]
+		arguments.PlanName = positionalArgs[2]
+	}
+
+	var err error
+	if *createdBefore != "" {
+		arguments.Cutoff, err = time.Parse(time.RFC3339, *createdBefore)
+		if err != nil {
+			fmt.Fprintf(output, "Invalid cutoff time: %s\n", *createdBefore)
+			printUsage(output, commandLine)
+			exit(ExitConfigurationError)
+			return
+		}
+	} else {
+		expiryIntervalArg := positionalArgs[len(positionalArgs)-1]
+		arguments.ExpiryInterval, err = ParseDuration(expiryIntervalArg)
+		if err != nil {
+			fmt.Fprintf(output, "Invalid expiry interval: %s\n", expiryIntervalArg)
+			printUsage(output, commandLine)
+			exit(ExitConfigurationError)
+			return
+		}
+	}
+
+	arguments.Timezone, err = time.LoadLocation(*timezone)
+	if err != nil {
+		fmt.Fprintf(output, "Invalid timezone: %s\n", *timezone)
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	for _, window := range windows {
+		parsed, err := calendar.ParseWindow(window)
+		if err != nil {
+			fmt.Fprintf(output, "Invalid maintenance window: %s\n", window)
+			printUsage(output, commandLine)
+			exit(ExitConfigurationError)
+			return
+		}
+		arguments.Windows = append(arguments.Windows, parsed)
+	}
+
+	for _, blackout := range blackouts {
+		parsed, err := calendar.ParseBlackout(blackout)
+		if err != nil {
+			fmt.Fprintf(output, "Invalid blackout: %s\n", blackout)
+			printUsage(output, commandLine)
+			exit(ExitConfigurationError)
+			return
+		}
+		arguments.Blackouts = append(arguments.Blackouts, parsed)
+	}
+
+	if arguments.HolidaysFile != "" && !arguments.BusinessDays {
+		fmt.Fprintln(output, "-holidays requires -business-days")
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	if arguments.InteractiveBatch && !arguments.Interactive {
+		fmt.Fprintln(output, "-interactive-batch requires -interactive")
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	if arguments.CascadeAttempts < 1 {
+		fmt.Fprintf(output, "Invalid cascade attempts: %d\n", arguments.CascadeAttempts)
 		printUsage(output, commandLine)
-		exit(0)
+		exit(ExitConfigurationError)
 		return
 	}
 
-	urlArg, err := url.Parse(positionalArgs[0])
+	arguments.AgeBasis, err = reaper.ParseAgeBasis(*ageBasis)
 	if err != nil {
-		fmt.Fprintf(output, "Invalid api url: %s\n", positionalArgs[0])
+		fmt.Fprintf(output, "Invalid age basis: %s\n", *ageBasis)
 		printUsage(output, commandLine)
-		exit(1)
+		exit(ExitConfigurationError)
 		return
 	}
-	urlArg.Scheme = "https"
-	apiUrl = urlArg.String()
 
-	serviceName = positionalArgs[1]
-	planName = positionalArgs[2]
+	if arguments.Apps {
+		if arguments.UserProvided {
+			fmt.Fprintln(output, "-apps cannot be combined with -user-provided")
+			printUsage(output, commandLine)
+			exit(ExitConfigurationError)
+			return
+		}
+
+		if arguments.AgeBasis != reaper.CreatedAt && arguments.AgeBasis != reaper.UpdatedAt {
+			fmt.Fprintf(output, "Invalid age basis for apps: %s\n", arguments.AgeBasis)
+			printUsage(output, commandLine)
+			exit(ExitConfigurationError)
+			return
+		}
+	}
+
+	if !arguments.Apps && (arguments.DeleteRoutes || *appStates != "") {
+		fmt.Fprintln(output, "-delete-routes and -app-state require -apps")
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	arguments.AppStates, err = parseAppStates(*appStates)
+	if err != nil {
+		fmt.Fprintf(output, "Invalid app state: %s\n", err)
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	if *namePattern != "" {
+		arguments.NamePattern, err = regexp.Compile(*namePattern)
+		if err != nil {
+			fmt.Fprintf(output, "Invalid name pattern: %s\n", err)
+			printUsage(output, commandLine)
+			exit(ExitConfigurationError)
+			return
+		}
+	}
+
+	if arguments.Apps && arguments.NamePattern == nil && arguments.SpaceGuid == "" {
+		fmt.Fprintln(output, "-apps requires -name-pattern or -space-guid")
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	if arguments.QuotaThreshold != 0 {
+		if arguments.QuotaThreshold < 0 || arguments.QuotaThreshold > 100 {
+			fmt.Fprintf(output, "Invalid quota threshold: %d\n", arguments.QuotaThreshold)
+			printUsage(output, commandLine)
+			exit(ExitConfigurationError)
+			return
+		}
+
+		if arguments.QuotaTarget < 0 || arguments.QuotaTarget > arguments.QuotaThreshold {
+			fmt.Fprintf(output, "Invalid quota target: %d (must be between 0 and the quota threshold)\n", arguments.QuotaTarget)
+			printUsage(output, commandLine)
+			exit(ExitConfigurationError)
+			return
+		}
+
+		if arguments.Apps || arguments.UserProvided {
+			fmt.Fprintln(output, "-quota-threshold applies only to managed service instances")
+			printUsage(output, commandLine)
+			exit(ExitConfigurationError)
+			return
+		}
+	}
 
-	expiryIntervalHours, err := strconv.ParseFloat(positionalArgs[3], 32)
-	if err != nil || expiryIntervalHours < 0 {
-		fmt.Fprintf(output, "Invalid expiry interval: %s\n", positionalArgs[3])
+	if arguments.KeepNewest < 0 {
+		fmt.Fprintf(output, "Invalid number of service instances to keep: %d\n", arguments.KeepNewest)
 		printUsage(output, commandLine)
-		exit(1)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	if arguments.MaxDeletions < 0 {
+		fmt.Fprintf(output, "Invalid maximum number of deletions: %d\n", arguments.MaxDeletions)
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	if arguments.ResumeRunID != "" && arguments.StateDirectory == "" {
+		fmt.Fprintln(output, "-resume requires -state-dir")
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	if arguments.PricesFile != "" && (arguments.Apps || arguments.UserProvided) {
+		fmt.Fprintln(output, "-prices cannot be combined with -apps or -user-provided")
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	if arguments.KeepNewest > 0 && arguments.Apps {
+		fmt.Fprintln(output, "-keep-newest cannot be combined with -apps")
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	arguments.KeepGrouping, err = reaper.ParseGrouping(*keepGrouping)
+	if err != nil {
+		fmt.Fprintf(output, "Invalid grouping: %s\n", *keepGrouping)
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	arguments.ServiceKeyExpiryInterval, ok = parseOptionalDuration(*serviceKeyAge)
+	if !ok {
+		fmt.Fprintf(output, "Invalid service key age: %s\n", *serviceKeyAge)
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	if *serviceKeyNamePattern != "" && *serviceKeyAge == "" {
+		fmt.Fprintln(output, "-service-key-name-pattern requires -service-key-age")
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	if *serviceKeyNamePattern != "" {
+		arguments.ServiceKeyNamePattern, err = regexp.Compile(*serviceKeyNamePattern)
+		if err != nil {
+			fmt.Fprintf(output, "Invalid service key name pattern: %s\n", err)
+			printUsage(output, commandLine)
+			exit(ExitConfigurationError)
+			return
+		}
+	}
+
+	if *emptySpaceAge != "" && *emptySpaceNamePattern == "" {
+		fmt.Fprintln(output, "-empty-space-age requires -empty-space-name-pattern")
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	if *emptySpaceNamePattern != "" {
+		arguments.EmptySpaceNamePattern, err = regexp.Compile(*emptySpaceNamePattern)
+		if err != nil {
+			fmt.Fprintf(output, "Invalid empty space name pattern: %s\n", err)
+			printUsage(output, commandLine)
+			exit(ExitConfigurationError)
+			return
+		}
+	}
+
+	arguments.MaxClockSkew, ok = parseOptionalDuration(*maxClockSkew)
+	if !ok {
+		fmt.Fprintf(output, "Invalid maximum clock skew: %s\n", *maxClockSkew)
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	arguments.EmptySpaceExpiryInterval, ok = parseOptionalDuration(*emptySpaceAge)
+	if !ok {
+		fmt.Fprintf(output, "Invalid empty space age: %s\n", *emptySpaceAge)
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	arguments.LastOperationStates, err = parseLastOperationStates(*lastOperationStates)
+	if err != nil {
+		fmt.Fprintf(output, "Invalid last operation state: %s\n", err)
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	if arguments.Purge && len(arguments.LastOperationStates) == 0 {
+		fmt.Fprintln(output, "-purge requires -last-operation-state")
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	for _, state := range arguments.LastOperationStates {
+		if arguments.Purge && state != cloudfoundry.LastOperationFailed && state != cloudfoundry.LastOperationInProgress {
+			fmt.Fprintf(output, "-purge cannot be combined with last operation state %s\n", state)
+			printUsage(output, commandLine)
+			exit(ExitConfigurationError)
+			return
+		}
+	}
+
+	if arguments.PurgeOrphans && !*confirmPurge {
+		fmt.Fprintln(output, "-purge-orphans requires -confirm-purge")
+		printUsage(output, commandLine)
+		exit(ExitConfigurationError)
 		return
 	}
-	expiryInterval = time.Duration(expiryIntervalHours*60*60) * time.Second
 
 	return
 }
 
+func parseRestore(args []string, output io.Writer, exit func(int)) (arguments Arguments) {
+	arguments.Command = RestoreCommand
+
+	commandLine := flag.NewFlagSet(args[0]+" "+RestoreCommand, flag.ExitOnError)
+	commandLine.SetOutput(output)
+	addConnectionFlags(commandLine, &arguments)
+	logLevel, logFormat := addLoggingFlags(commandLine, &arguments)
+	commandLine.BoolVar(&arguments.Force, "force", false, "Restore the service instance even if the snapshot did not record its parameters, in which case it is provisioned without them.")
+	commandLine.Parse(args[2:])
+
+	positionalArgs := commandLine.Args()
+	if !checkPositionalArgs(positionalArgs, 2, output, func() { printRestoreUsage(output, commandLine) }, exit) {
+		return
+	}
+
+	apiUrl, ok := parseApiUrl(positionalArgs[0], output, func() { printRestoreUsage(output, commandLine) }, exit)
+	if !ok {
+		return
+	}
+	arguments.ApiUrl = apiUrl
+
+	if !parseLogging(*logLevel, *logFormat, &arguments, output, func() { printRestoreUsage(output, commandLine) }, exit) {
+		return
+	}
+
+	arguments.SnapshotFile = positionalArgs[1]
+
+	return
+}
+
+func parseHistory(args []string, output io.Writer, exit func(int)) (arguments Arguments) {
+	arguments.Command = HistoryCommand
+	arguments.LogLevel = logging.Info
+	arguments.LogFormat = logging.Text
+
+	commandLine := flag.NewFlagSet(args[0]+" "+HistoryCommand, flag.ExitOnError)
+	commandLine.SetOutput(output)
+	commandLine.IntVar(&arguments.HistoryWeeks, "weeks", 8, "Number of weeks, including the current week, to report on. Set to 0 to report on every run.")
+	commandLine.IntVar(&arguments.HistoryTop, "top", 10, "Number of spaces to list from which the most service instances were reaped.")
+	commandLine.Parse(args[2:])
+
+	positionalArgs := commandLine.Args()
+	if !checkPositionalArgs(positionalArgs, 1, output, func() { printHistoryUsage(output, commandLine) }, exit) {
+		return
+	}
+
+	if arguments.HistoryWeeks < 0 {
+		fmt.Fprintf(output, "Invalid number of weeks: %d\n", arguments.HistoryWeeks)
+		printHistoryUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	if arguments.HistoryTop < 1 {
+		fmt.Fprintf(output, "Invalid number of spaces: %d\n", arguments.HistoryTop)
+		printHistoryUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+
+	arguments.HistoryFile = positionalArgs[0]
+
+	return
+}
+
+func parseReport(args []string, output io.Writer, exit func(int)) (arguments Arguments) {
+	arguments.Command = ReportCommand
+
+	commandLine := flag.NewFlagSet(args[0]+" "+ReportCommand, flag.ExitOnError)
+	commandLine.SetOutput(output)
+	addConnectionFlags(commandLine, &arguments)
+	logLevel, logFormat := addLoggingFlags(commandLine, &arguments)
+	format := commandLine.String("format", string(inventory.Table), "Format of the report: table, json, or csv. Log messages are written to standard error so as not to interfere.")
+	addPricesFlag(commandLine, &arguments)
+	commandLine.Parse(args[2:])
+
+	positionalArgs := commandLine.Args()
+	if !checkPositionalArgs(positionalArgs, 1, output, func() { printReportUsage(output, commandLine) }, exit) {
+		return
+	}
+
+	apiUrl, ok := parseApiUrl(positionalArgs[0], output, func() { printReportUsage(output, commandLine) }, exit)
+	if !ok {
+		return
+	}
+	arguments.ApiUrl = apiUrl
+
+	if !parseLogging(*logLevel, *logFormat, &arguments, output, func() { printReportUsage(output, commandLine) }, exit) {
+		return
+	}
+
+	reportFormat, err := inventory.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintf(output, "Invalid report format: %s\n", *format)
+		printReportUsage(output, commandLine)
+		exit(ExitConfigurationError)
+		return
+	}
+	arguments.ReportFormat = reportFormat
+
+	return
+}
+
+// repeatedFlag collects the values of a flag which may be given more than once.
+type repeatedFlag []string
+
+func (f *repeatedFlag) String() string {
+	return strings.Join(*f, ", ")
+}
+
+func (f *repeatedFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// parseOptionalDuration parses the value of a flag which takes a duration, treating an empty value as zero.
+func parseOptionalDuration(duration string) (time.Duration, bool) {
+	if duration == "" {
+		return 0, true
+	}
+
+	parsed, err := ParseDuration(duration)
+	return parsed, err == nil
+}
+
+func parseLastOperationStates(states string) ([]string, error) {
+	lastOperationStates := make([]string, 0)
+	if states == "" {
+		return lastOperationStates, nil
+	}
+
+	for _, state := range strings.Split(states, ",") {
+		state = strings.Replace(strings.TrimSpace(state), "_", " ", -1)
+		switch state {
+		case cloudfoundry.LastOperationFailed, cloudfoundry.LastOperationInProgress, cloudfoundry.LastOperationSucceeded:
+			lastOperationStates = append(lastOperationStates, state)
+		default:
+			return nil, errors.New(state)
+		}
+	}
+
+	return lastOperationStates, nil
+}
+
+func parseAppStates(states string) ([]string, error) {
+	appStates := make([]string, 0)
+	if states == "" {
+		return appStates, nil
+	}
+
+	for _, state := range strings.Split(states, ",") {
+		state = strings.ToUpper(strings.TrimSpace(state))
+		switch state {
+		case cloudfoundry.AppStarted, cloudfoundry.AppStopped:
+			appStates = append(appStates, state)
+		default:
+			return nil, errors.New(state)
+		}
+	}
+
+	return appStates, nil
+}
+
+func addConnectionFlags(commandLine *flag.FlagSet, arguments *Arguments) {
+	commandLine.StringVar(&arguments.Username, "u", "", "username")
+	commandLine.StringVar(&arguments.Password, "p", "", "password")
+	commandLine.BoolVar(&arguments.SkipSslValidation, "skip-ssl-validation", false, "Skip verification of the API endpoint. Not recommended!")
+}
+
+func addPricesFlag(commandLine *flag.FlagSet, arguments *Arguments) {
+	commandLine.StringVar(&arguments.PricesFile, "prices", "", "File giving the hourly cost of a service instance of each plan, as service,plan,cost on each line, in order to report the cost of service instances and the monthly savings from reaping them.")
+}
+
+func addLoggingFlags(commandLine *flag.FlagSet, arguments *Arguments) (logLevel *string, logFormat *string) {
+	logLevel = commandLine.String("log-level", logging.Info.String(), "Least severe level of message to log: debug, info, warn, or error.")
+	logFormat = commandLine.String("log-format", string(logging.Text), "Format of log messages: text or json.")
+	commandLine.BoolVar(&arguments.Trace, "trace", false, "Log each request to and response from the Cloud Controller, with credentials redacted. Implies -log-level=debug. Also enabled by setting CF_TRACE=true.")
+	return logLevel, logFormat
+}
+
+func parseLogging(logLevel string, logFormat string, arguments *Arguments, output io.Writer, usage func(), exit func(int)) bool {
+	var err error
+	arguments.LogLevel, err = logging.ParseLevel(logLevel)
+	if err != nil {
+		fmt.Fprintf(output, "Invalid log level: %s\n", logLevel)
+		usage()
+		exit(ExitConfigurationError)
+		return false
+	}
+	if arguments.Trace {
+		arguments.LogLevel = logging.Debug
+	}
+
+	arguments.LogFormat, err = logging.ParseFormat(logFormat)
+	if err != nil {
+		fmt.Fprintf(output, "Invalid log format: %s\n", logFormat)
+		usage()
+		exit(ExitConfigurationError)
+		return false
+	}
+
+	return true
+}
+
+// checkPositionalArgs prints usage and exits successfully if help is requested, or exits with ExitConfigurationError if
+// the number of positional arguments is wrong. It reports whether parsing may continue.
+func checkPositionalArgs(positionalArgs []string, expected int, output io.Writer, usage func(), exit func(int)) bool {
+	if len(positionalArgs) > 0 && positionalArgs[0] == "help" {
+		usage()
+		exit(ExitSuccess)
+		return false
+	}
+
+	if len(positionalArgs) != expected {
+		fmt.Fprintf(output, "Expected %d arguments but got %d\n", expected, len(positionalArgs))
+		usage()
+		exit(ExitConfigurationError)
+		return false
+	}
+
+	return true
+}
+
+func parseApiUrl(apiUrlArg string, output io.Writer, usage func(), exit func(int)) (string, bool) {
+	urlArg, err := url.Parse(apiUrlArg)
+	if err != nil {
+		fmt.Fprintf(output, "Invalid api url: %s\n", apiUrlArg)
+		usage()
+		exit(ExitConfigurationError)
+		return "", false
+	}
+	urlArg.Scheme = "https"
+	return urlArg.String(), true
+}
+
 func printUsage(output io.Writer, flags *flag.FlagSet) {
-	fmt.Fprintln(output, `Delete instances of the given service older than the given age
+	fmt.Fprintln(output, `Delete instances of the given service, or apps, older than the given age
 		
 Usage:
-  service-instance-reaper [-reap] [-recursive] -u username -p password [-skip-ssl-validation] API_URL SERVICE_NAME PLAN_NAME AGE_HOURS
+  service-instance-reaper [-reap [-interactive [-interactive-batch]] [-max-deletions n]] [-fail-fast] [-recursive [-cascade-attempts n]] [-unbound-only] [-without-service-keys] [-last-operation-state states [-purge]] [-purge-orphans -confirm-purge] [-name-pattern regexp] [-space-guid guid] [-protect-tag tag] [-quota-threshold percent -quota-target percent] [-keep-newest n [-keep-group-by grouping]] [-service-key-age duration [-service-key-name-pattern regexp]] [-empty-space-name-pattern regexp [-empty-space-age duration]] [-audit-log file] [-snapshot-dir directory] [-state-dir directory [-resume run-id]] [-history file] [-prices file] [-age-basis basis] [-created-before timestamp] [-business-days [-holidays file]] [-window window]... [-blackout dates]... [-timezone zone] [-max-clock-skew duration] -u username -p password [-skip-ssl-validation] [-log-level level] [-log-format format] [-trace] API_URL SERVICE_NAME PLAN_NAME AGE
+  service-instance-reaper -user-provided [-reap] ... -u username -p password [-skip-ssl-validation] API_URL AGE
+  service-instance-reaper -apps [-app-state states] [-delete-routes] [-reap] ... (-name-pattern regexp | -space-guid guid) -u username -p password [-skip-ssl-validation] API_URL AGE
+  service-instance-reaper restore [-force] -u username -p password [-skip-ssl-validation] [-log-level level] [-log-format format] [-trace] API_URL SNAPSHOT_FILE
+  service-instance-reaper history [-weeks n] [-top n] HISTORY_FILE
+  service-instance-reaper report [-format format] [-prices file] -u username -p password [-skip-ssl-validation] [-log-level level] [-log-format format] [-trace] API_URL
+
+AGE is a number of hours, such as 336, a duration such as 36h, 7d, or 2w, or an ISO 8601 duration such as P7DT12H.
+It is omitted when -created-before is given.
+
+Flags (which must be specified BEFORE non-flag arguments):`)
+	flags.PrintDefaults()
+}
+
+func printRestoreUsage(output io.Writer, flags *flag.FlagSet) {
+	fmt.Fprintln(output, `Re-create a reaped service instance from a snapshot taken before it was deleted
+
+Usage:
+  service-instance-reaper restore [-force] -u username -p password [-skip-ssl-validation] [-log-level level] [-log-format format] [-trace] API_URL SNAPSHOT_FILE
+
+Flags (which must be specified BEFORE non-flag arguments):`)
+	flags.PrintDefaults()
+}
+
+func printHistoryUsage(output io.Writer, flags *flag.FlagSet) {
+	fmt.Fprintln(output, `Summarise the runs recorded by -history week by week
+
+Usage:
+  service-instance-reaper history [-weeks n] [-top n] HISTORY_FILE
+
+Flags (which must be specified BEFORE non-flag arguments):`)
+	flags.PrintDefaults()
+}
+
+func printReportUsage(output io.Writer, flags *flag.FlagSet) {
+	fmt.Fprintln(output, `Report the number of service instances of every service by age, without deleting anything
+
+Usage:
+  service-instance-reaper report [-format format] [-prices file] -u username -p password [-skip-ssl-validation] [-log-level level] [-log-format format] [-trace] API_URL
 
 Flags (which must be specified BEFORE non-flag arguments):`)
 	flags.PrintDefaults()
@@ -21,6 +21,9 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
 	"github.com/pivotal-cf/service-instance-reaper/arg"
+	"github.com/pivotal-cf/service-instance-reaper/inventory"
+	"github.com/pivotal-cf/service-instance-reaper/logging"
+	"github.com/pivotal-cf/service-instance-reaper/reaper"
 	"time"
 )
 
@@ -34,30 +37,22 @@ var _ = Describe("Parse", func() {
 	)
 
 	var (
-		args              []string
-		username          string
-		password          string
-		planName          string
-		skipSslValidation bool
-		reap              bool
-		recursive         bool
-		apiUrl            string
-		serviceName       string
-		expiryInterval    time.Duration
-		shouldExit        bool
-		exitCode          int
-		output            *gbytes.Buffer
+		args       []string
+		arguments  arg.Arguments
+		shouldExit bool
+		exitCode   int
+		output     *gbytes.Buffer
 	)
 
 	JustBeforeEach(func() {
 		shouldExit = false
 		output = gbytes.NewBuffer()
-		username, password, skipSslValidation, reap, recursive, apiUrl, serviceName, planName, expiryInterval = arg.Parse(args, output, func(code int) { shouldExit = true; exitCode = code })
+		arguments = arg.Parse(args, output, func(code int) { shouldExit = true; exitCode = code })
 	})
 
 	Context("with a full set of arguments", func() {
 		BeforeEach(func() {
-			args = []string{"command", "-u=user", "-p=password", "-skip-ssl-validation", "-reap", "-recursive", testUrl, testServiceName, testPlanName, expirationInterval}
+			args = []string{"command", "-u=user", "-p=password", "-skip-ssl-validation", "-reap", "-max-deletions=20", "-fail-fast", "-interactive", "-interactive-batch", "-recursive", "-cascade-attempts=5", "-snapshot-dir=/tmp/snapshots", "-state-dir=/tmp/state", "-resume=run-1", "-history=history.jsonl", "-prices=prices.csv", "-age-basis=last_operation", "-business-days", "-holidays=holidays.txt", "-timezone=Europe/London", "-window=Mon-Fri 09:00-17:00", "-window=Sat 10:00-12:00", "-blackout=2026-12-20/2027-01-03", "-unbound-only", "-without-service-keys", "-last-operation-state=failed,in_progress", "-purge", "-purge-orphans", "-confirm-purge", "-audit-log=audit.log", "-name-pattern=^ci-", "-space-guid=space-guid", "-protect-tag=keep", "-quota-threshold=90", "-quota-target=75", "-keep-newest=3", "-keep-group-by=name_prefix", "-service-key-age=12", "-service-key-name-pattern=^ci-", "-empty-space-name-pattern=^ci-space-", "-empty-space-age=24", "-max-clock-skew=30s", "-log-level=warn", "-log-format=json", testUrl, testServiceName, testPlanName, expirationInterval}
 		})
 
 		It("does not fail", func() {
@@ -65,15 +60,57 @@ var _ = Describe("Parse", func() {
 		})
 
 		It("parses the arguments correctly", func() {
-			Expect(username).To(Equal("user"))
-			Expect(password).To(Equal("password"))
-			Expect(skipSslValidation).To(BeTrue())
-			Expect(reap).To(BeTrue())
-			Expect(recursive).To(BeTrue())
-			Expect(apiUrl).To(Equal("https://some.url"))
-			Expect(serviceName).To(Equal("p-config-server"))
-			Expect(planName).To(Equal("planName"))
-			Expect(expiryInterval).To(Equal(time.Duration(168) * time.Hour))
+			Expect(arguments.Command).To(Equal(arg.ReapCommand))
+			Expect(arguments.Username).To(Equal("user"))
+			Expect(arguments.Password).To(Equal("password"))
+			Expect(arguments.SkipSslValidation).To(BeTrue())
+			Expect(arguments.Reap).To(BeTrue())
+			Expect(arguments.MaxDeletions).To(Equal(20))
+			Expect(arguments.FailFast).To(BeTrue())
+			Expect(arguments.Interactive).To(BeTrue())
+			Expect(arguments.InteractiveBatch).To(BeTrue())
+			Expect(arguments.Recursive).To(BeTrue())
+			Expect(arguments.CascadeAttempts).To(Equal(5))
+			Expect(arguments.BusinessDays).To(BeTrue())
+			Expect(arguments.HolidaysFile).To(Equal("holidays.txt"))
+			Expect(arguments.Timezone.String()).To(Equal("Europe/London"))
+			Expect(arguments.Windows).To(HaveLen(2))
+			Expect(arguments.Windows[0].String()).To(Equal("Mon-Fri 09:00-17:00"))
+			Expect(arguments.Windows[1].String()).To(Equal("Sat 10:00-12:00"))
+			Expect(arguments.Blackouts).To(HaveLen(1))
+			Expect(arguments.Blackouts[0].String()).To(Equal("2026-12-20/2027-01-03"))
+			Expect(arguments.MaxClockSkew).To(Equal(30 * time.Second))
+			Expect(arguments.LogLevel).To(Equal(logging.Warn))
+			Expect(arguments.LogFormat).To(Equal(logging.JSON))
+			Expect(arguments.Trace).To(BeFalse())
+			Expect(arguments.SnapshotDirectory).To(Equal("/tmp/snapshots"))
+			Expect(arguments.StateDirectory).To(Equal("/tmp/state"))
+			Expect(arguments.ResumeRunID).To(Equal("run-1"))
+			Expect(arguments.HistoryFile).To(Equal("history.jsonl"))
+			Expect(arguments.PricesFile).To(Equal("prices.csv"))
+			Expect(arguments.AgeBasis).To(Equal(reaper.LastOperation))
+			Expect(arguments.UnboundOnly).To(BeTrue())
+			Expect(arguments.WithoutServiceKeys).To(BeTrue())
+			Expect(arguments.LastOperationStates).To(Equal([]string{"failed", "in progress"}))
+			Expect(arguments.Purge).To(BeTrue())
+			Expect(arguments.PurgeOrphans).To(BeTrue())
+			Expect(arguments.AuditLog).To(Equal("audit.log"))
+			Expect(arguments.NamePattern.String()).To(Equal("^ci-"))
+			Expect(arguments.SpaceGuid).To(Equal("space-guid"))
+			Expect(arguments.ProtectionTag).To(Equal("keep"))
+			Expect(arguments.QuotaThreshold).To(Equal(90))
+			Expect(arguments.QuotaTarget).To(Equal(75))
+			Expect(arguments.KeepNewest).To(Equal(3))
+			Expect(arguments.KeepGrouping).To(Equal(reaper.ByNamePrefix))
+			Expect(arguments.ServiceKeyExpiryInterval).To(Equal(12 * time.Hour))
+			Expect(arguments.ServiceKeyNamePattern.String()).To(Equal("^ci-"))
+			Expect(arguments.EmptySpaceNamePattern.String()).To(Equal("^ci-space-"))
+			Expect(arguments.EmptySpaceExpiryInterval).To(Equal(24 * time.Hour))
+			Expect(arguments.UserProvided).To(BeFalse())
+			Expect(arguments.ApiUrl).To(Equal("https://some.url"))
+			Expect(arguments.ServiceName).To(Equal("p-config-server"))
+			Expect(arguments.PlanName).To(Equal("planName"))
+			Expect(arguments.ExpiryInterval).To(Equal(time.Duration(168) * time.Hour))
 		})
 	})
 
@@ -87,18 +124,59 @@ var _ = Describe("Parse", func() {
 		})
 
 		It("applies the correct defaults", func() {
-			Expect(skipSslValidation).To(BeFalse())
-			Expect(reap).To(BeFalse())
-			Expect(recursive).To(BeFalse())
+			Expect(arguments.Command).To(Equal(arg.ReapCommand))
+			Expect(arguments.SkipSslValidation).To(BeFalse())
+			Expect(arguments.Reap).To(BeFalse())
+			Expect(arguments.MaxDeletions).To(BeZero())
+			Expect(arguments.FailFast).To(BeFalse())
+			Expect(arguments.Interactive).To(BeFalse())
+			Expect(arguments.InteractiveBatch).To(BeFalse())
+			Expect(arguments.Recursive).To(BeFalse())
+			Expect(arguments.CascadeAttempts).To(Equal(3))
+			Expect(arguments.BusinessDays).To(BeFalse())
+			Expect(arguments.HolidaysFile).To(BeEmpty())
+			Expect(arguments.Timezone).To(Equal(time.UTC))
+			Expect(arguments.Windows).To(BeEmpty())
+			Expect(arguments.Blackouts).To(BeEmpty())
+			Expect(arguments.MaxClockSkew).To(BeZero())
+			Expect(arguments.LogLevel).To(Equal(logging.Info))
+			Expect(arguments.LogFormat).To(Equal(logging.Text))
+			Expect(arguments.Trace).To(BeFalse())
+			Expect(arguments.SnapshotDirectory).To(BeEmpty())
+			Expect(arguments.StateDirectory).To(BeEmpty())
+			Expect(arguments.ResumeRunID).To(BeEmpty())
+			Expect(arguments.HistoryFile).To(BeEmpty())
+			Expect(arguments.AgeBasis).To(Equal(reaper.CreatedAt))
+			Expect(arguments.UnboundOnly).To(BeFalse())
+			Expect(arguments.WithoutServiceKeys).To(BeFalse())
+			Expect(arguments.LastOperationStates).To(BeEmpty())
+			Expect(arguments.Purge).To(BeFalse())
+			Expect(arguments.PurgeOrphans).To(BeFalse())
+			Expect(arguments.AuditLog).To(BeEmpty())
+			Expect(arguments.NamePattern).To(BeNil())
+			Expect(arguments.SpaceGuid).To(BeEmpty())
+			Expect(arguments.Cutoff.IsZero()).To(BeTrue())
+			Expect(arguments.ProtectionTag).To(Equal("do-not-reap"))
+			Expect(arguments.QuotaThreshold).To(BeZero())
+			Expect(arguments.QuotaTarget).To(BeZero())
+			Expect(arguments.KeepNewest).To(BeZero())
+			Expect(arguments.KeepGrouping).To(Equal(reaper.BySpace))
+			Expect(arguments.ServiceKeyExpiryInterval).To(BeZero())
+			Expect(arguments.ServiceKeyNamePattern).To(BeNil())
+			Expect(arguments.Apps).To(BeFalse())
+			Expect(arguments.AppStates).To(BeEmpty())
+			Expect(arguments.DeleteRoutes).To(BeFalse())
+			Expect(arguments.EmptySpaceNamePattern).To(BeNil())
+			Expect(arguments.EmptySpaceExpiryInterval).To(BeZero())
 		})
 
 		It("parses the specified arguments correctly", func() {
-			Expect(username).To(Equal("user"))
-			Expect(password).To(Equal("password"))
-			Expect(apiUrl).To(Equal("https://some.url"))
-			Expect(serviceName).To(Equal("p-config-server"))
-			Expect(planName).To(Equal("planName"))
-			Expect(expiryInterval).To(Equal(time.Duration(168) * time.Hour))
+			Expect(arguments.Username).To(Equal("user"))
+			Expect(arguments.Password).To(Equal("password"))
+			Expect(arguments.ApiUrl).To(Equal("https://some.url"))
+			Expect(arguments.ServiceName).To(Equal("p-config-server"))
+			Expect(arguments.PlanName).To(Equal("planName"))
+			Expect(arguments.ExpiryInterval).To(Equal(time.Duration(168) * time.Hour))
 		})
 	})
 
@@ -108,12 +186,13 @@ var _ = Describe("Parse", func() {
 			args = []string{"command", "-u=user", "-p=password", testUrl, testServiceName, testPlanName, expirationInterval, "banana"}
 		})
 
-		It("fails with exit status code 0", func() {
+		It("fails with exit status code 2", func() {
 			Expect(shouldExit).To(BeTrue())
-			Expect(exitCode).To(Equal(0))
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
 		})
 
 		It("prints usage information", func() {
+			Expect(output).To(gbytes.Say("Expected 4 arguments but got 5"))
 			Expect(output).To(gbytes.Say("Usage"))
 		})
 	})
@@ -123,9 +202,9 @@ var _ = Describe("Parse", func() {
 			args = []string{"command", "-u=user", "-p=password", "help"}
 		})
 
-		It("fails with exit status code 0", func() {
+		It("exits with exit status code 0", func() {
 			Expect(shouldExit).To(BeTrue())
-			Expect(exitCode).To(Equal(0))
+			Expect(exitCode).To(Equal(arg.ExitSuccess))
 		})
 
 		It("prints usage information", func() {
@@ -138,9 +217,9 @@ var _ = Describe("Parse", func() {
 			args = []string{"command", "-u=user", "-p=password", ":///:/", testServiceName, testPlanName, expirationInterval}
 		})
 
-		It("fails with exit status code 1", func() {
+		It("fails with exit status code 2", func() {
 			Expect(shouldExit).To(BeTrue())
-			Expect(exitCode).To(Equal(1))
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
 		})
 
 		It("prints usage information", func() {
@@ -148,6 +227,42 @@ var _ = Describe("Parse", func() {
 		})
 	})
 
+	Context("when the expiry interval is specified in days", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", testUrl, testServiceName, testPlanName, "14d"}
+		})
+
+		It("parses it", func() {
+			Expect(shouldExit).To(BeFalse())
+			Expect(arguments.ExpiryInterval).To(Equal(14 * 24 * time.Hour))
+		})
+	})
+
+	Context("when a cutoff time is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-created-before=2026-01-01T00:00:00Z", testUrl, testServiceName, testPlanName}
+		})
+
+		It("does not require an expiry interval", func() {
+			Expect(shouldExit).To(BeFalse())
+			Expect(arguments.Cutoff).To(Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+			Expect(arguments.ExpiryInterval).To(BeZero())
+			Expect(arguments.PlanName).To(Equal(testPlanName))
+		})
+
+		Context("when the cutoff time is invalid", func() {
+			BeforeEach(func() {
+				args = []string{"command", "-u=user", "-p=password", "-created-before=yesterday", testUrl, testServiceName, testPlanName}
+			})
+
+			It("fails with exit status code 2", func() {
+				Expect(shouldExit).To(BeTrue())
+				Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+				Expect(output).To(gbytes.Say("Invalid cutoff time: yesterday"))
+			})
+		})
+	})
+
 	Context("when an invalid expiry interval is specified", func() {
 		BeforeEach(func() {
 			args = []string{"command", "-u=user", "-p=password", testUrl, testServiceName, testPlanName, "-1"}
@@ -155,7 +270,686 @@ var _ = Describe("Parse", func() {
 
 		It("fails with exit status code 0", func() {
 			Expect(shouldExit).To(BeTrue())
-			Expect(exitCode).To(Equal(1))
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+		})
+	})
+
+	Context("when an invalid timezone is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-business-days", "-timezone=Mars/Olympus_Mons", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid timezone: Mars/Olympus_Mons"))
+		})
+	})
+
+	Context("when an invalid maintenance window is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-window=weekdays 9-5", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid maintenance window: weekdays 9-5"))
+		})
+	})
+
+	Context("when an invalid blackout is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-blackout=2027-01-03/2026-12-20", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid blackout: 2027-01-03/2026-12-20"))
+		})
+	})
+
+	Context("when an invalid maximum clock skew is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-max-clock-skew=soon", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid maximum clock skew: soon"))
+		})
+	})
+
+	Context("when batch confirmation is specified without interactive mode", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-interactive-batch", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("-interactive-batch requires -interactive"))
+		})
+	})
+
+	Context("when a negative maximum number of deletions is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-max-deletions=-1", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid maximum number of deletions: -1"))
+		})
+	})
+
+	Context("when a maximum number of deletions is specified for apps", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-apps", "-max-deletions=5", "-space-guid=space-guid", testUrl, expirationInterval}
+		})
+
+		It("accepts it", func() {
+			Expect(shouldExit).To(BeFalse())
+			Expect(arguments.Apps).To(BeTrue())
+			Expect(arguments.MaxDeletions).To(Equal(5))
+		})
+	})
+
+	Context("when a run is resumed without a state directory", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-resume=run-1", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("-resume requires -state-dir"))
+		})
+	})
+
+	Context("when a state directory is specified for apps", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-apps", "-state-dir=/tmp/state", "-space-guid=space-guid", testUrl, expirationInterval}
+		})
+
+		It("accepts it", func() {
+			Expect(shouldExit).To(BeFalse())
+			Expect(arguments.StateDirectory).To(Equal("/tmp/state"))
+		})
+	})
+
+	Context("when prices are specified for user-provided service instances", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-user-provided", "-prices=prices.csv", testUrl, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("-prices cannot be combined with -apps or -user-provided"))
+		})
+	})
+
+	Context("when interactive mode is specified for apps", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-apps", "-interactive", "-space-guid=space-guid", testUrl, expirationInterval}
+		})
+
+		It("accepts it", func() {
+			Expect(shouldExit).To(BeFalse())
+			Expect(arguments.Interactive).To(BeTrue())
+		})
+	})
+
+	Context("when tracing is enabled", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-trace", "-log-level=error", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("logs at debug level", func() {
+			Expect(shouldExit).To(BeFalse())
+			Expect(arguments.Trace).To(BeTrue())
+			Expect(arguments.LogLevel).To(Equal(logging.Debug))
+		})
+	})
+
+	Context("when an invalid log level is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-log-level=verbose", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid log level: verbose"))
+		})
+	})
+
+	Context("when an invalid log format is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-log-format=xml", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid log format: xml"))
+		})
+	})
+
+	Context("when holidays are specified without business days", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-holidays=holidays.txt", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("-holidays requires -business-days"))
+		})
+	})
+
+	Context("when a service key name pattern is specified without a service key age", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-service-key-name-pattern=^ci-", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("-service-key-name-pattern requires -service-key-age"))
+		})
+	})
+
+	Context("when an empty space age is specified without an empty space name pattern", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-empty-space-age=24", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("-empty-space-age requires -empty-space-name-pattern"))
+		})
+	})
+
+	Context("when an invalid number of cascade attempts is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-cascade-attempts=0", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid cascade attempts: 0"))
+		})
+	})
+
+	Context("when an invalid age basis is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-age-basis=banana", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid age basis: banana"))
+		})
+	})
+
+	Context("when an invalid last operation state is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-last-operation-state=failed,banana", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid last operation state: banana"))
+		})
+	})
+
+	Context("when purge is requested for service instances whose last operation succeeded", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-last-operation-state=failed,succeeded", "-purge", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("-purge cannot be combined with last operation state succeeded"))
+		})
+	})
+
+	Context("when purge is requested without a last operation state", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-purge", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("-purge requires -last-operation-state"))
+		})
+	})
+
+	Context("when purging orphans is requested without confirmation", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-purge-orphans", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("-purge-orphans requires -confirm-purge"))
+		})
+	})
+
+	Context("when user-provided service instances are to be reaped", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-user-provided", testUrl, expirationInterval}
+		})
+
+		It("does not require a service or plan name", func() {
+			Expect(shouldExit).To(BeFalse())
+			Expect(arguments.UserProvided).To(BeTrue())
+			Expect(arguments.ApiUrl).To(Equal("https://some.url"))
+			Expect(arguments.ServiceName).To(BeEmpty())
+			Expect(arguments.PlanName).To(BeEmpty())
+			Expect(arguments.ExpiryInterval).To(Equal(time.Duration(168) * time.Hour))
+		})
+	})
+
+	Context("when an invalid empty space name pattern is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-empty-space-name-pattern=(", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid empty space name pattern"))
+		})
+	})
+
+	Context("when apps are to be reaped", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-apps", "-app-state=stopped", "-delete-routes", "-age-basis=updated_at", "-space-guid=space-guid", testUrl, expirationInterval}
+		})
+
+		It("does not require a service or plan name", func() {
+			Expect(shouldExit).To(BeFalse())
+			Expect(arguments.Apps).To(BeTrue())
+			Expect(arguments.AppStates).To(Equal([]string{"STOPPED"}))
+			Expect(arguments.DeleteRoutes).To(BeTrue())
+			Expect(arguments.AgeBasis).To(Equal(reaper.UpdatedAt))
+			Expect(arguments.ServiceName).To(BeEmpty())
+			Expect(arguments.PlanName).To(BeEmpty())
+			Expect(arguments.ExpiryInterval).To(Equal(time.Duration(168) * time.Hour))
+		})
+	})
+
+	Context("when apps are to be reaped without a name pattern or space", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-apps", testUrl, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("-apps requires -name-pattern or -space-guid"))
+		})
+	})
+
+	Context("when apps are to be reaped by name pattern", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-apps", "-name-pattern=^ci-", testUrl, expirationInterval}
+		})
+
+		It("accepts it", func() {
+			Expect(shouldExit).To(BeFalse())
+			Expect(arguments.NamePattern.String()).To(Equal("^ci-"))
+		})
+	})
+
+	Context("when routes are to be deleted without reaping apps", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-delete-routes", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("-delete-routes and -app-state require -apps"))
+		})
+	})
+
+	Context("when app states are specified without reaping apps", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-app-state=stopped", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("-delete-routes and -app-state require -apps"))
+		})
+	})
+
+	Context("when an invalid app state is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-apps", "-app-state=crashed", testUrl, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid app state: CRASHED"))
+		})
+	})
+
+	Context("when apps are to be reaped by an age basis which applies only to service instances", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-apps", "-age-basis=last_bound", testUrl, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid age basis for apps: last_bound"))
+		})
+	})
+
+	Context("when apps and user-provided service instances are both to be reaped", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-apps", "-user-provided", testUrl, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("-apps cannot be combined with -user-provided"))
+		})
+	})
+
+	Context("when an invalid name pattern is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-name-pattern=(", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid name pattern"))
+		})
+	})
+
+	Context("when a quota target above the quota threshold is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-quota-threshold=80", "-quota-target=90", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid quota target: 90"))
+		})
+	})
+
+	Context("when an invalid quota threshold is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-quota-threshold=101", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid quota threshold: 101"))
+		})
+	})
+
+	Context("when an invalid grouping is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-keep-newest=3", "-keep-group-by=org", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid grouping: org"))
+		})
+	})
+
+	Context("when grouping by a label", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-keep-newest=3", "-keep-group-by=label:team", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("groups by the value of that label", func() {
+			Expect(shouldExit).To(BeFalse())
+			Expect(arguments.KeepGrouping).To(Equal(reaper.ByLabel("team")))
+		})
+	})
+
+	Context("when grouping by a label without a key", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-keep-newest=3", "-keep-group-by=label:", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid grouping: label:"))
+		})
+	})
+
+	Context("when a negative number of service instances to keep is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-keep-newest=-1", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid number of service instances to keep: -1"))
+		})
+	})
+
+	Context("when an invalid service key name pattern is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-service-key-age=12", "-service-key-name-pattern=(", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid service key name pattern"))
+		})
+	})
+
+	Context("when a negative service key age is specified", func() {
+		BeforeEach(func() {
+			args = []string{"command", "-u=user", "-p=password", "-service-key-age=-1", testUrl, testServiceName, testPlanName, expirationInterval}
+		})
+
+		It("fails with exit status code 2", func() {
+			Expect(shouldExit).To(BeTrue())
+			Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			Expect(output).To(gbytes.Say("Invalid service key age: -1"))
+		})
+	})
+
+	Describe("the restore command", func() {
+		Context("with a full set of arguments", func() {
+			BeforeEach(func() {
+				args = []string{"command", "restore", "-u=user", "-p=password", "-skip-ssl-validation", "-log-format=json", "-trace", "-force", testUrl, "snapshot.json"}
+			})
+
+			It("does not fail", func() {
+				Expect(shouldExit).To(BeFalse())
+			})
+
+			It("parses the arguments correctly", func() {
+				Expect(arguments.Command).To(Equal(arg.RestoreCommand))
+				Expect(arguments.Username).To(Equal("user"))
+				Expect(arguments.Password).To(Equal("password"))
+				Expect(arguments.SkipSslValidation).To(BeTrue())
+				Expect(arguments.ApiUrl).To(Equal("https://some.url"))
+				Expect(arguments.SnapshotFile).To(Equal("snapshot.json"))
+				Expect(arguments.Force).To(BeTrue())
+				Expect(arguments.LogFormat).To(Equal(logging.JSON))
+				Expect(arguments.LogLevel).To(Equal(logging.Debug))
+				Expect(arguments.Trace).To(BeTrue())
+			})
+		})
+
+		Context("with an invalid number of arguments", func() {
+			BeforeEach(func() {
+				args = []string{"command", "restore", "-u=user", "-p=password", testUrl}
+			})
+
+			It("fails with exit status code 2", func() {
+				Expect(shouldExit).To(BeTrue())
+				Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+			})
+
+			It("prints usage information", func() {
+				Expect(output).To(gbytes.Say("Usage"))
+			})
+		})
+	})
+
+	Describe("the history command", func() {
+		Context("with a full set of arguments", func() {
+			BeforeEach(func() {
+				args = []string{"command", "history", "-weeks=4", "-top=3", "history.jsonl"}
+			})
+
+			It("parses the arguments correctly", func() {
+				Expect(shouldExit).To(BeFalse())
+				Expect(arguments.Command).To(Equal(arg.HistoryCommand))
+				Expect(arguments.HistoryFile).To(Equal("history.jsonl"))
+				Expect(arguments.HistoryWeeks).To(Equal(4))
+				Expect(arguments.HistoryTop).To(Equal(3))
+				Expect(arguments.LogLevel).To(Equal(logging.Info))
+			})
+		})
+
+		Context("with only the required arguments", func() {
+			BeforeEach(func() {
+				args = []string{"command", "history", "history.jsonl"}
+			})
+
+			It("uses the defaults", func() {
+				Expect(shouldExit).To(BeFalse())
+				Expect(arguments.HistoryWeeks).To(Equal(8))
+				Expect(arguments.HistoryTop).To(Equal(10))
+			})
+		})
+
+		Context("with an invalid number of arguments", func() {
+			BeforeEach(func() {
+				args = []string{"command", "history"}
+			})
+
+			It("prints usage information and fails with exit status code 2", func() {
+				Expect(shouldExit).To(BeTrue())
+				Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+				Expect(output).To(gbytes.Say("Usage"))
+			})
+		})
+
+		Context("when help is requested", func() {
+			BeforeEach(func() {
+				args = []string{"command", "history", "help"}
+			})
+
+			It("prints usage information and exits successfully", func() {
+				Expect(shouldExit).To(BeTrue())
+				Expect(exitCode).To(Equal(arg.ExitSuccess))
+				Expect(output).To(gbytes.Say("Usage"))
+			})
+		})
+
+		Context("with a negative number of weeks", func() {
+			BeforeEach(func() {
+				args = []string{"command", "history", "-weeks=-1", "history.jsonl"}
+			})
+
+			It("fails with exit status code 2", func() {
+				Expect(shouldExit).To(BeTrue())
+				Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+				Expect(output).To(gbytes.Say("Invalid number of weeks: -1"))
+			})
+		})
+
+		Context("with no spaces to list", func() {
+			BeforeEach(func() {
+				args = []string{"command", "history", "-top=0", "history.jsonl"}
+			})
+
+			It("fails with exit status code 2", func() {
+				Expect(shouldExit).To(BeTrue())
+				Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+				Expect(output).To(gbytes.Say("Invalid number of spaces: 0"))
+			})
+		})
+	})
+
+	Describe("the report command", func() {
+		Context("with a full set of arguments", func() {
+			BeforeEach(func() {
+				args = []string{"command", "report", "-u", "user", "-p", "pass", "-skip-ssl-validation", "-format=csv", "-prices=prices.csv", testUrl}
+			})
+
+			It("parses the arguments correctly", func() {
+				Expect(shouldExit).To(BeFalse())
+				Expect(arguments.Command).To(Equal(arg.ReportCommand))
+				Expect(arguments.ApiUrl).To(Equal("https://some.url"))
+				Expect(arguments.Username).To(Equal("user"))
+				Expect(arguments.Password).To(Equal("pass"))
+				Expect(arguments.SkipSslValidation).To(BeTrue())
+				Expect(arguments.ReportFormat).To(Equal(inventory.CSV))
+				Expect(arguments.PricesFile).To(Equal("prices.csv"))
+			})
+		})
+
+		Context("with only the required arguments", func() {
+			BeforeEach(func() {
+				args = []string{"command", "report", testUrl}
+			})
+
+			It("reports in a table", func() {
+				Expect(shouldExit).To(BeFalse())
+				Expect(arguments.ReportFormat).To(Equal(inventory.Table))
+			})
+		})
+
+		Context("with an invalid number of arguments", func() {
+			BeforeEach(func() {
+				args = []string{"command", "report"}
+			})
+
+			It("prints usage information and fails with exit status code 2", func() {
+				Expect(shouldExit).To(BeTrue())
+				Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+				Expect(output).To(gbytes.Say("Usage"))
+			})
+		})
+
+		Context("with an unknown report format", func() {
+			BeforeEach(func() {
+				args = []string{"command", "report", "-format=xml", testUrl}
+			})
+
+			It("fails with exit status code 2", func() {
+				Expect(shouldExit).To(BeTrue())
+				Expect(exitCode).To(Equal(arg.ExitConfigurationError))
+				Expect(output).To(gbytes.Say("Invalid report format: xml"))
+			})
 		})
 	})
 })
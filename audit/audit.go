@@ -0,0 +1,86 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+const (
+	Deleted = "deleted"
+	Purged  = "purged"
+	Failed  = "failed"
+)
+
+// Entry records the outcome of reaping a single resource. Only the fields identifying the kind of resource which was
+// reaped are set.
+type Entry struct {
+	Time                string `json:"time"`
+	Action              string `json:"action"`
+	ServiceInstanceGuid string `json:"service_instance_guid,omitempty"`
+	ServiceInstanceName string `json:"service_instance_name,omitempty"`
+	ServiceKeyGuid      string `json:"service_key_guid,omitempty"`
+	ServiceKeyName      string `json:"service_key_name,omitempty"`
+	AppGuid             string `json:"app_guid,omitempty"`
+	AppName             string `json:"app_name,omitempty"`
+	RouteGuid           string `json:"route_guid,omitempty"`
+	RouteHost           string `json:"route_host,omitempty"`
+	SpaceGuid           string `json:"space_guid,omitempty"`
+	SpaceName           string `json:"space_name,omitempty"`
+	Detail              string `json:"detail,omitempty"`
+}
+
+//go:generate counterfeiter . Trail
+type Trail interface {
+	Record(entry Entry) error
+}
+
+type fileTrail struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewTrail returns a trail which appends entries, one JSON object per line, to the file at the given path.
+func NewTrail(path string) Trail {
+	return &fileTrail{path: path}
+}
+
+func (t *fileTrail) Record(entry Entry) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("cannot encode audit entry: %s", err)
+	}
+
+	file, err := os.OpenFile(t.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("cannot open audit trail %s: %s", t.path, err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("cannot write audit trail %s: %s", t.path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package audit_test
+
+import (
+	"encoding/json"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/service-instance-reaper/audit"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var _ = Describe("Trail", func() {
+	var (
+		directory string
+		path      string
+		trail     audit.Trail
+	)
+
+	BeforeEach(func() {
+		var err error
+		directory, err = ioutil.TempDir("", "audit")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(directory, "audit.log")
+		trail = audit.NewTrail(path)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(directory)
+	})
+
+	It("appends each entry as a line of JSON", func() {
+		Expect(trail.Record(audit.Entry{Action: audit.Deleted, ServiceInstanceGuid: "guid-0"})).To(Succeed())
+		Expect(trail.Record(audit.Entry{Action: audit.Purged, ServiceInstanceGuid: "guid-1", Detail: "broker gone"})).To(Succeed())
+
+		contents, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+		Expect(lines).To(HaveLen(2))
+
+		var entry audit.Entry
+		Expect(json.Unmarshal([]byte(lines[1]), &entry)).To(Succeed())
+		Expect(entry).To(Equal(audit.Entry{Action: audit.Purged, ServiceInstanceGuid: "guid-1", Detail: "broker gone"}))
+	})
+
+	Context("when the trail cannot be written", func() {
+		BeforeEach(func() {
+			trail = audit.NewTrail(filepath.Join(directory, "missing", "audit.log"))
+		})
+
+		It("returns an error", func() {
+			Expect(trail.Record(audit.Entry{})).To(MatchError(ContainSubstring("cannot open audit trail")))
+		})
+	})
+})
@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Calendar measures time in business days, which are the weekdays that are not holidays, in a particular time zone.
+type Calendar struct {
+	location *time.Location
+	holidays map[string]bool
+}
+
+func New(location *time.Location, holidays []time.Time) *Calendar {
+	calendar := &Calendar{
+		location: location,
+		holidays: make(map[string]bool),
+	}
+	for _, holiday := range holidays {
+		calendar.holidays[holiday.Format(dateLayout)] = true
+	}
+	return calendar
+}
+
+// BusinessTimeBetween returns the time between from and to which falls on business days.
+func (c *Calendar) BusinessTimeBetween(from time.Time, to time.Time) (businessTime time.Duration) {
+	from = from.In(c.location)
+	to = to.In(c.location)
+
+	for start := from; start.Before(to); {
+		year, month, day := start.Date()
+		end := time.Date(year, month, day+1, 0, 0, 0, 0, c.location)
+		if end.After(to) {
+			end = to
+		}
+
+		if c.businessDay(start) {
+			businessTime += end.Sub(start)
+		}
+		start = end
+	}
+
+	return
+}
+
+func (c *Calendar) businessDay(t time.Time) bool {
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	return !c.holidays[t.Format(dateLayout)]
+}
+
+// LoadHolidays reads the holidays from the file at the given path. The file is either an iCalendar file, in which case
+// the start date of each event is a holiday, or a list of dates in the form 2006-01-02, one per line. Blank lines and
+// lines starting with # are ignored.
+func LoadHolidays(path string) ([]time.Time, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open holidays %s: %s", path, err)
+	}
+	defer file.Close()
+
+	holidays := make([]time.Time, 0)
+	iCalendar := false
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if lineNumber == 1 && line == "BEGIN:VCALENDAR" {
+			iCalendar = true
+		}
+
+		var holiday time.Time
+		if iCalendar {
+			if !strings.HasPrefix(line, "DTSTART") {
+				continue
+			}
+			holiday, err = parseICalendarDate(line)
+		} else {
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			holiday, err = time.Parse(dateLayout, line)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday at line %d of %s: %s", lineNumber, path, line)
+		}
+
+		holidays = append(holidays, holiday)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read holidays %s: %s", path, err)
+	}
+
+	return holidays, nil
+}
+
+// parseICalendarDate parses the date of a DTSTART property such as DTSTART;VALUE=DATE:20261225 or
+// DTSTART:20261225T090000Z.
+func parseICalendarDate(line string) (time.Time, error) {
+	i := strings.LastIndex(line, ":")
+	if i < 0 || len(line) < i+9 {
+		return time.Time{}, fmt.Errorf("invalid date")
+	}
+	return time.Parse("20060102", line[i+1:i+9])
+}
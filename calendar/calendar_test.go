@@ -0,0 +1,148 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calendar_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/service-instance-reaper/calendar"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe("Calendar", func() {
+	var (
+		cal      *calendar.Calendar
+		holidays []time.Time
+		location *time.Location
+	)
+
+	BeforeEach(func() {
+		holidays = nil
+		location = time.UTC
+	})
+
+	JustBeforeEach(func() {
+		cal = calendar.New(location, holidays)
+	})
+
+	Describe("BusinessTimeBetween", func() {
+		// Friday 2026-10-16 to Monday 2026-10-19
+		var (
+			fridayAfternoon = time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+			mondayMorning   = time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+		)
+
+		It("excludes weekends", func() {
+			Expect(cal.BusinessTimeBetween(fridayAfternoon, mondayMorning)).To(Equal(18 * time.Hour))
+		})
+
+		It("counts all the time on a weekday", func() {
+			Expect(cal.BusinessTimeBetween(mondayMorning, mondayMorning.Add(3*time.Hour))).To(Equal(3 * time.Hour))
+		})
+
+		It("counts no time if the end precedes the start", func() {
+			Expect(cal.BusinessTimeBetween(mondayMorning, fridayAfternoon)).To(BeZero())
+		})
+
+		Context("when there are holidays", func() {
+			BeforeEach(func() {
+				holidays = []time.Time{time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)}
+			})
+
+			It("excludes them", func() {
+				Expect(cal.BusinessTimeBetween(fridayAfternoon, mondayMorning)).To(Equal(9 * time.Hour))
+			})
+		})
+
+		Context("when a time zone is given", func() {
+			BeforeEach(func() {
+				var err error
+				location, err = time.LoadLocation("America/New_York")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("determines weekends in that time zone", func() {
+				// 15:00 UTC on Friday is 11:00 in New York, leaving 13 hours of Friday; 09:00 UTC on Monday is
+				// 05:00 in New York.
+				Expect(cal.BusinessTimeBetween(fridayAfternoon, mondayMorning)).To(Equal(18 * time.Hour))
+				Expect(cal.BusinessTimeBetween(fridayAfternoon, fridayAfternoon.Add(14*time.Hour))).To(Equal(13 * time.Hour))
+			})
+		})
+	})
+
+	Describe("LoadHolidays", func() {
+		var directory string
+
+		BeforeEach(func() {
+			var err error
+			directory, err = ioutil.TempDir("", "calendar")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(directory)
+		})
+
+		write := func(contents string) string {
+			path := filepath.Join(directory, "holidays")
+			Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+			return path
+		}
+
+		It("reads a list of dates", func() {
+			holidays, err := calendar.LoadHolidays(write("# Christmas\n2026-12-25\n\n2026-12-28\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(holidays).To(Equal([]time.Time{
+				time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC),
+			}))
+		})
+
+		It("reads an iCalendar file", func() {
+			holidays, err := calendar.LoadHolidays(write(`BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+DTSTART;VALUE=DATE:20261225
+SUMMARY:Christmas Day
+END:VEVENT
+BEGIN:VEVENT
+DTSTART:20261228T000000Z
+SUMMARY:Boxing Day (substitute)
+END:VEVENT
+END:VCALENDAR
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(holidays).To(Equal([]time.Time{
+				time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC),
+			}))
+		})
+
+		It("rejects an invalid date", func() {
+			_, err := calendar.LoadHolidays(write("2026-12-25\nChristmas\n"))
+			Expect(err).To(MatchError(ContainSubstring("invalid holiday at line 2")))
+		})
+
+		It("fails if the file does not exist", func() {
+			_, err := calendar.LoadHolidays(filepath.Join(directory, "missing"))
+			Expect(err).To(MatchError(ContainSubstring("cannot open holidays")))
+		})
+	})
+})
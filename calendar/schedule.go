@@ -0,0 +1,206 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Window is a range of times of day on certain days of the week. A window whose end precedes its start runs past
+// midnight into the following day.
+type Window struct {
+	days  map[time.Weekday]bool
+	start time.Duration
+	end   time.Duration
+	text  string
+}
+
+// ParseWindow parses a window such as "Mon-Fri 09:00-17:00", "Sat,Sun 10:00-12:00", or "Mon-Thu 22:00-06:00".
+func ParseWindow(window string) (Window, error) {
+	fields := strings.Fields(window)
+	if len(fields) != 2 {
+		return Window{}, fmt.Errorf("invalid window: %s (expected days and times such as Mon-Fri 09:00-17:00)", window)
+	}
+
+	days, err := parseDays(fields[0])
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid window: %s (%s)", window, err)
+	}
+
+	times := strings.Split(fields[1], "-")
+	if len(times) != 2 {
+		return Window{}, fmt.Errorf("invalid window: %s (expected times such as 09:00-17:00)", window)
+	}
+	start, err := parseTimeOfDay(times[0])
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid window: %s (%s)", window, err)
+	}
+	end, err := parseTimeOfDay(times[1])
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid window: %s (%s)", window, err)
+	}
+
+	return Window{days: days, start: start, end: end, text: window}, nil
+}
+
+func parseDays(days string) (map[time.Weekday]bool, error) {
+	parsed := make(map[time.Weekday]bool)
+	for _, dayRange := range strings.Split(days, ",") {
+		bounds := strings.Split(dayRange, "-")
+		if len(bounds) > 2 {
+			return nil, fmt.Errorf("invalid days: %s", dayRange)
+		}
+
+		first, ok := weekdays[strings.ToLower(bounds[0])]
+		if !ok {
+			return nil, fmt.Errorf("invalid day: %s", bounds[0])
+		}
+		last := first
+		if len(bounds) == 2 {
+			last, ok = weekdays[strings.ToLower(bounds[1])]
+			if !ok {
+				return nil, fmt.Errorf("invalid day: %s", bounds[1])
+			}
+		}
+
+		for day := first; ; day = (day + 1) % 7 {
+			parsed[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+	return parsed, nil
+}
+
+func parseTimeOfDay(timeOfDay string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", timeOfDay)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time: %s", timeOfDay)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+func (w Window) contains(t time.Time) bool {
+	year, month, day := t.Date()
+	timeOfDay := t.Sub(time.Date(year, month, day, 0, 0, 0, 0, t.Location()))
+
+	if w.start < w.end {
+		return w.days[t.Weekday()] && timeOfDay >= w.start && timeOfDay < w.end
+	}
+
+	previousDay := (t.Weekday() + 6) % 7
+	return (w.days[t.Weekday()] && timeOfDay >= w.start) || (w.days[previousDay] && timeOfDay < w.end)
+}
+
+func (w Window) String() string {
+	return w.text
+}
+
+// Blackout is a range of dates, inclusive, on which nothing may be deleted.
+type Blackout struct {
+	first time.Time
+	last  time.Time
+	text  string
+}
+
+// ParseBlackout parses a single date such as 2026-12-25 or a range of dates such as 2026-12-20/2027-01-03.
+func ParseBlackout(blackout string) (Blackout, error) {
+	dates := strings.Split(blackout, "/")
+	if len(dates) > 2 {
+		return Blackout{}, fmt.Errorf("invalid blackout: %s", blackout)
+	}
+
+	first, err := time.Parse(dateLayout, dates[0])
+	if err != nil {
+		return Blackout{}, fmt.Errorf("invalid blackout: %s", blackout)
+	}
+	last := first
+	if len(dates) == 2 {
+		last, err = time.Parse(dateLayout, dates[1])
+		if err != nil || last.Before(first) {
+			return Blackout{}, fmt.Errorf("invalid blackout: %s", blackout)
+		}
+	}
+
+	return Blackout{first: first, last: last, text: blackout}, nil
+}
+
+func (b Blackout) contains(t time.Time) bool {
+	date := t.Format(dateLayout)
+	return date >= b.first.Format(dateLayout) && date <= b.last.Format(dateLayout)
+}
+
+func (b Blackout) String() string {
+	return b.text
+}
+
+// Schedule determines when deletions are permitted: only during one of its windows, if it has any, and never during
+// one of its blackouts. Both are interpreted in the schedule's time zone.
+type Schedule struct {
+	location  *time.Location
+	windows   []Window
+	blackouts []Blackout
+}
+
+// NewSchedule creates a schedule with the given windows and blackouts, interpreted in the given location.
+func NewSchedule(location *time.Location, windows []Window, blackouts []Blackout) *Schedule {
+	return &Schedule{
+		location:  location,
+		windows:   windows,
+		blackouts: blackouts,
+	}
+}
+
+// Permits determines whether deletions are permitted at the given time and, if not, why not.
+func (s *Schedule) Permits(t time.Time) (bool, string) {
+	t = t.In(s.location)
+
+	for _, blackout := range s.blackouts {
+		if blackout.contains(t) {
+			return false, fmt.Sprintf("%s is during the blackout %s", t.Format(time.RFC3339), blackout)
+		}
+	}
+
+	if len(s.windows) == 0 {
+		return true, ""
+	}
+
+	for _, window := range s.windows {
+		if window.contains(t) {
+			return true, ""
+		}
+	}
+
+	names := make([]string, len(s.windows))
+	for i, window := range s.windows {
+		names[i] = window.String()
+	}
+	return false, fmt.Sprintf("%s is outside the maintenance windows %s", t.Format(time.RFC3339), strings.Join(names, ", "))
+}
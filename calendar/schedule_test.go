@@ -0,0 +1,176 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package calendar_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/service-instance-reaper/calendar"
+	"time"
+)
+
+var _ = Describe("Schedule", func() {
+	var (
+		windows   []calendar.Window
+		blackouts []calendar.Blackout
+		location  *time.Location
+		schedule  *calendar.Schedule
+	)
+
+	// Wednesday 2026-10-14
+	wednesdayAt := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 14, hour, minute, 0, 0, time.UTC)
+	}
+
+	window := func(text string) calendar.Window {
+		parsed, err := calendar.ParseWindow(text)
+		Expect(err).NotTo(HaveOccurred())
+		return parsed
+	}
+
+	blackout := func(text string) calendar.Blackout {
+		parsed, err := calendar.ParseBlackout(text)
+		Expect(err).NotTo(HaveOccurred())
+		return parsed
+	}
+
+	BeforeEach(func() {
+		windows = nil
+		blackouts = nil
+		location = time.UTC
+	})
+
+	JustBeforeEach(func() {
+		schedule = calendar.NewSchedule(location, windows, blackouts)
+	})
+
+	It("permits deletions at any time if there are no windows or blackouts", func() {
+		permitted, reason := schedule.Permits(wednesdayAt(3, 0))
+		Expect(permitted).To(BeTrue())
+		Expect(reason).To(BeEmpty())
+	})
+
+	Context("with maintenance windows", func() {
+		BeforeEach(func() {
+			windows = []calendar.Window{window("Mon-Fri 09:00-17:00"), window("Sat,Sun 10:00-12:00")}
+		})
+
+		It("permits deletions within a window", func() {
+			permitted, _ := schedule.Permits(wednesdayAt(9, 0))
+			Expect(permitted).To(BeTrue())
+		})
+
+		It("does not permit deletions at the end of a window", func() {
+			permitted, reason := schedule.Permits(wednesdayAt(17, 0))
+			Expect(permitted).To(BeFalse())
+			Expect(reason).To(Equal("2026-10-14T17:00:00Z is outside the maintenance windows Mon-Fri 09:00-17:00, Sat,Sun 10:00-12:00"))
+		})
+
+		It("does not permit deletions on other days", func() {
+			permitted, _ := schedule.Permits(time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC))
+			Expect(permitted).To(BeFalse())
+		})
+
+		Context("in another time zone", func() {
+			BeforeEach(func() {
+				var err error
+				location, err = time.LoadLocation("America/New_York")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("interprets the windows in that time zone", func() {
+				permitted, _ := schedule.Permits(wednesdayAt(9, 0))
+				Expect(permitted).To(BeFalse())
+
+				permitted, _ = schedule.Permits(wednesdayAt(13, 0))
+				Expect(permitted).To(BeTrue())
+			})
+		})
+	})
+
+	Context("with a window which runs past midnight", func() {
+		BeforeEach(func() {
+			windows = []calendar.Window{window("Wed 22:00-06:00")}
+		})
+
+		It("permits deletions on the following morning", func() {
+			permitted, _ := schedule.Permits(wednesdayAt(23, 0))
+			Expect(permitted).To(BeTrue())
+
+			permitted, _ = schedule.Permits(wednesdayAt(29, 0))
+			Expect(permitted).To(BeTrue())
+
+			permitted, _ = schedule.Permits(wednesdayAt(5, 0))
+			Expect(permitted).To(BeFalse())
+		})
+	})
+
+	Context("with blackouts", func() {
+		BeforeEach(func() {
+			windows = []calendar.Window{window("Mon-Fri 09:00-17:00")}
+			blackouts = []calendar.Blackout{blackout("2026-10-13/2026-10-14")}
+		})
+
+		It("does not permit deletions during a blackout, even within a window", func() {
+			permitted, reason := schedule.Permits(wednesdayAt(12, 0))
+			Expect(permitted).To(BeFalse())
+			Expect(reason).To(Equal("2026-10-14T12:00:00Z is during the blackout 2026-10-13/2026-10-14"))
+		})
+
+		It("permits deletions after a blackout", func() {
+			permitted, _ := schedule.Permits(wednesdayAt(36, 0))
+			Expect(permitted).To(BeTrue())
+		})
+	})
+
+	Describe("ParseWindow", func() {
+		It("rejects invalid windows", func() {
+			for _, text := range []string{"", "Mon-Fri", "Mon-Fri 09:00", "Funday 09:00-17:00", "Mon-Fri 9am-5pm", "Mon-Wed-Fri 09:00-17:00"} {
+				_, err := calendar.ParseWindow(text)
+				Expect(err).To(HaveOccurred(), text)
+			}
+		})
+
+		It("accepts day ranges which wrap around the week", func() {
+			windows = []calendar.Window{window("Fri-Mon 00:00-23:59")}
+			schedule = calendar.NewSchedule(location, windows, nil)
+
+			permitted, _ := schedule.Permits(time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+			Expect(permitted).To(BeTrue())
+
+			permitted, _ = schedule.Permits(wednesdayAt(12, 0))
+			Expect(permitted).To(BeFalse())
+		})
+	})
+
+	Describe("ParseBlackout", func() {
+		It("accepts a single date", func() {
+			blackouts = []calendar.Blackout{blackout("2026-10-14")}
+			schedule = calendar.NewSchedule(location, nil, blackouts)
+
+			permitted, _ := schedule.Permits(wednesdayAt(23, 59))
+			Expect(permitted).To(BeFalse())
+		})
+
+		It("rejects invalid blackouts", func() {
+			for _, text := range []string{"", "2026-10-14/", "2026-10-15/2026-10-14", "14/10/2026"} {
+				_, err := calendar.ParseBlackout(text)
+				Expect(err).To(HaveOccurred(), text)
+			}
+		})
+	})
+})
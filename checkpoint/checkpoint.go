@@ -0,0 +1,245 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package checkpoint
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Selection describes how a run chose its candidates, such as by its service, plan, age, and filters. A run may be
+// resumed only with the same selection, or its candidates would not be those which the resumed run would choose.
+type Selection map[string]string
+
+// Differences describes each way in which the given selection differs from this one.
+func (s Selection) Differences(other Selection) []string {
+	keys := make([]string, 0, len(s)+len(other))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	for key := range other {
+		if _, ok := s[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var differences []string
+	for _, key := range keys {
+		if s[key] != other[key] {
+			differences = append(differences, fmt.Sprintf("%s was %q but is now %q", key, s[key], other[key]))
+		}
+	}
+	return differences
+}
+
+// State is the progress of a run. Attempted, Completed, and Failed hold the guids of service instances whose
+// deletion was started, succeeded, or failed.
+type State struct {
+	RunID      string                         `json:"run_id"`
+	Selection  Selection                      `json:"selection"`
+	Listed     bool                           `json:"listed"`
+	Candidates []cloudfoundry.ServiceInstance `json:"candidates"`
+	Attempted  []string                       `json:"attempted"`
+	Completed  []string                       `json:"completed"`
+	Failed     []string                       `json:"failed"`
+}
+
+// IsAttempted reports whether the deletion of the service instance with the given guid was started.
+func (s State) IsAttempted(serviceInstanceGuid string) bool {
+	return contains(s.Attempted, serviceInstanceGuid)
+}
+
+// IsCompleted reports whether the service instance with the given guid has been deleted.
+func (s State) IsCompleted(serviceInstanceGuid string) bool {
+	return contains(s.Completed, serviceInstanceGuid)
+}
+
+// Pending returns the candidates which have not been deleted.
+func (s State) Pending() []cloudfoundry.ServiceInstance {
+	var pending []cloudfoundry.ServiceInstance
+	for _, candidate := range s.Candidates {
+		if !s.IsCompleted(candidate.Metadata.Guid) {
+			pending = append(pending, candidate)
+		}
+	}
+	return pending
+}
+
+//go:generate counterfeiter . Checkpoint
+type Checkpoint interface {
+	// State returns the progress of the run so far.
+	State() State
+
+	// Candidate records a service instance to be deleted.
+	Candidate(serviceInstance cloudfoundry.ServiceInstance) error
+
+	// Listed records that every candidate has been found, so that a resumed run need not list them again.
+	Listed() error
+
+	// Attempted, Completed, and Failed record the progress of deleting a candidate.
+	Attempted(serviceInstanceGuid string) error
+	Completed(serviceInstanceGuid string) error
+	Failed(serviceInstanceGuid string) error
+}
+
+type fileCheckpoint struct {
+	path  string
+	state State
+	mutex sync.Mutex
+}
+
+// NewRunID identifies a run started at the given time. A random suffix distinguishes runs started together.
+func NewRunID(started time.Time) string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s-%s", started.UTC().Format("20060102T150405Z"), hex.EncodeToString(suffix))
+}
+
+// Path returns the path of the state file of the given run in the given directory.
+func Path(directory string, runID string) string {
+	return filepath.Join(directory, runID+".json")
+}
+
+// New starts a checkpoint for the given run, which chooses its candidates by the given selection, saving its state
+// file in the given directory.
+func New(directory string, runID string, selection Selection) (Checkpoint, error) {
+	checkpoint := &fileCheckpoint{path: Path(directory, runID), state: State{RunID: runID, Selection: selection}}
+	if err := checkpoint.save(); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// Load resumes the checkpoint of the given run from its state file in the given directory.
+func Load(directory string, runID string) (Checkpoint, error) {
+	path := Path(directory, runID)
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read checkpoint %s: %s", path, err)
+	}
+
+	checkpoint := &fileCheckpoint{path: path}
+	if err := json.Unmarshal(contents, &checkpoint.state); err != nil {
+		return nil, fmt.Errorf("cannot parse checkpoint %s: %s", path, err)
+	}
+	return checkpoint, nil
+}
+
+func (c *fileCheckpoint) State() State {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	state := c.state
+	state.Candidates = append([]cloudfoundry.ServiceInstance(nil), c.state.Candidates...)
+	state.Attempted = append([]string(nil), c.state.Attempted...)
+	state.Completed = append([]string(nil), c.state.Completed...)
+	state.Failed = append([]string(nil), c.state.Failed...)
+	return state
+}
+
+func (c *fileCheckpoint) Candidate(serviceInstance cloudfoundry.ServiceInstance) error {
+	return c.update(func(state *State) {
+		for _, candidate := range state.Candidates {
+			if candidate.Metadata.Guid == serviceInstance.Metadata.Guid {
+				return
+			}
+		}
+		state.Candidates = append(state.Candidates, serviceInstance)
+	})
+}
+
+func (c *fileCheckpoint) Listed() error {
+	return c.update(func(state *State) { state.Listed = true })
+}
+
+func (c *fileCheckpoint) Attempted(serviceInstanceGuid string) error {
+	return c.update(func(state *State) { state.Attempted = add(state.Attempted, serviceInstanceGuid) })
+}
+
+func (c *fileCheckpoint) Completed(serviceInstanceGuid string) error {
+	return c.update(func(state *State) {
+		state.Completed = add(state.Completed, serviceInstanceGuid)
+		state.Failed = remove(state.Failed, serviceInstanceGuid)
+	})
+}
+
+func (c *fileCheckpoint) Failed(serviceInstanceGuid string) error {
+	return c.update(func(state *State) { state.Failed = add(state.Failed, serviceInstanceGuid) })
+}
+
+func (c *fileCheckpoint) update(change func(state *State)) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	change(&c.state)
+	return c.save()
+}
+
+// save replaces the state file, writing to a temporary file first so that an interruption never leaves it partial.
+func (c *fileCheckpoint) save() error {
+	contents, err := json.MarshalIndent(c.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode checkpoint: %s", err)
+	}
+
+	temporaryPath := c.path + ".tmp"
+	if err := ioutil.WriteFile(temporaryPath, contents, 0600); err != nil {
+		return fmt.Errorf("cannot write checkpoint %s: %s", temporaryPath, err)
+	}
+
+	if err := os.Rename(temporaryPath, c.path); err != nil {
+		return fmt.Errorf("cannot write checkpoint %s: %s", c.path, err)
+	}
+
+	return nil
+}
+
+func contains(guids []string, guid string) bool {
+	for _, g := range guids {
+		if g == guid {
+			return true
+		}
+	}
+	return false
+}
+
+func add(guids []string, guid string) []string {
+	if contains(guids, guid) {
+		return guids
+	}
+	return append(guids, guid)
+}
+
+func remove(guids []string, guid string) []string {
+	var remaining []string
+	for _, g := range guids {
+		if g != guid {
+			remaining = append(remaining, g)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package checkpoint_test
+
+import (
+	"encoding/json"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/service-instance-reaper/checkpoint"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe("Checkpoint", func() {
+	const runID = "20261018T120000Z-0a1b2c3d"
+
+	var (
+		directory        string
+		cp               checkpoint.Checkpoint
+		serviceInstance1 cloudfoundry.ServiceInstance
+		serviceInstance2 cloudfoundry.ServiceInstance
+	)
+
+	BeforeEach(func() {
+		var err error
+		directory, err = ioutil.TempDir("", "checkpoint")
+		Expect(err).NotTo(HaveOccurred())
+
+		cp, err = checkpoint.New(directory, runID, checkpoint.Selection{"service": "service", "plan": "plan"})
+		Expect(err).NotTo(HaveOccurred())
+
+		serviceInstance1 = cloudfoundry.ServiceInstance{Metadata: cloudfoundry.Metadata{Guid: "guid-1"}, Entity: cloudfoundry.ServiceInstanceEntity{Name: "name-1"}}
+		serviceInstance2 = cloudfoundry.ServiceInstance{Metadata: cloudfoundry.Metadata{Guid: "guid-2"}, Entity: cloudfoundry.ServiceInstanceEntity{Name: "name-2"}}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(directory)
+	})
+
+	It("saves a state file for the run as soon as it is created", func() {
+		contents, err := ioutil.ReadFile(filepath.Join(directory, runID+".json"))
+		Expect(err).NotTo(HaveOccurred())
+
+		var state checkpoint.State
+		Expect(json.Unmarshal(contents, &state)).To(Succeed())
+		Expect(state.RunID).To(Equal(runID))
+	})
+
+	It("records the progress of the run", func() {
+		Expect(cp.Candidate(serviceInstance1)).To(Succeed())
+		Expect(cp.Candidate(serviceInstance2)).To(Succeed())
+		Expect(cp.Candidate(serviceInstance1)).To(Succeed())
+		Expect(cp.Listed()).To(Succeed())
+		Expect(cp.Attempted("guid-1")).To(Succeed())
+		Expect(cp.Completed("guid-1")).To(Succeed())
+		Expect(cp.Attempted("guid-2")).To(Succeed())
+		Expect(cp.Failed("guid-2")).To(Succeed())
+
+		Expect(cp.State()).To(Equal(checkpoint.State{
+			RunID:      runID,
+			Selection:  checkpoint.Selection{"service": "service", "plan": "plan"},
+			Listed:     true,
+			Candidates: []cloudfoundry.ServiceInstance{serviceInstance1, serviceInstance2},
+			Attempted:  []string{"guid-1", "guid-2"},
+			Completed:  []string{"guid-1"},
+			Failed:     []string{"guid-2"},
+		}))
+		Expect(cp.State().Pending()).To(Equal([]cloudfoundry.ServiceInstance{serviceInstance2}))
+		Expect(cp.State().IsAttempted("guid-2")).To(BeTrue())
+		Expect(cp.State().IsAttempted("guid-3")).To(BeFalse())
+	})
+
+	It("no longer counts a deletion as failed once a retry completes it", func() {
+		Expect(cp.Failed("guid-1")).To(Succeed())
+		Expect(cp.Completed("guid-1")).To(Succeed())
+
+		Expect(cp.State().Failed).To(BeEmpty())
+		Expect(cp.State().IsCompleted("guid-1")).To(BeTrue())
+	})
+
+	It("can be resumed from its state file", func() {
+		Expect(cp.Candidate(serviceInstance1)).To(Succeed())
+		Expect(cp.Completed("guid-1")).To(Succeed())
+
+		resumed, err := checkpoint.Load(directory, runID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resumed.State()).To(Equal(cp.State()))
+
+		Expect(resumed.Candidate(serviceInstance2)).To(Succeed())
+		Expect(resumed.State().Pending()).To(Equal([]cloudfoundry.ServiceInstance{serviceInstance2}))
+	})
+
+	Describe("selections", func() {
+		It("describe how they differ", func() {
+			selection := checkpoint.Selection{"service": "service", "plan": "plan", "age": "24h0m0s"}
+
+			Expect(selection.Differences(checkpoint.Selection{"service": "service", "plan": "plan", "age": "24h0m0s"})).To(BeEmpty())
+			Expect(selection.Differences(checkpoint.Selection{"service": "service", "plan": "other", "space_guid": "space-guid"})).To(Equal([]string{
+				`age was "24h0m0s" but is now ""`,
+				`plan was "plan" but is now "other"`,
+				`space_guid was "" but is now "space-guid"`,
+			}))
+		})
+	})
+
+	Context("when the run has no state file", func() {
+		It("cannot be resumed", func() {
+			_, err := checkpoint.Load(directory, "unknown")
+			Expect(err).To(MatchError(ContainSubstring("cannot read checkpoint")))
+		})
+	})
+
+	Context("when the state file is corrupt", func() {
+		BeforeEach(func() {
+			Expect(ioutil.WriteFile(filepath.Join(directory, runID+".json"), []byte("{"), 0600)).To(Succeed())
+		})
+
+		It("cannot be resumed", func() {
+			_, err := checkpoint.Load(directory, runID)
+			Expect(err).To(MatchError(ContainSubstring("cannot parse checkpoint")))
+		})
+	})
+
+	Context("when the directory does not exist", func() {
+		It("returns an error", func() {
+			_, err := checkpoint.New(filepath.Join(directory, "missing"), runID, nil)
+			Expect(err).To(MatchError(ContainSubstring("cannot write checkpoint")))
+		})
+	})
+
+	Describe("run IDs", func() {
+		It("start with the time the run started and are unique", func() {
+			started := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+			runID := checkpoint.NewRunID(started)
+			Expect(runID).To(MatchRegexp(`^20261018T120000Z-[0-9a-f]{8}$`))
+			Expect(checkpoint.NewRunID(started)).NotTo(Equal(runID))
+		})
+	})
+})
@@ -19,11 +19,14 @@ package cloudfoundry
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/pivotal-cf/service-instance-reaper/httpclient"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 )
 
 const MaximumResultsPerPage = 50
@@ -31,9 +34,62 @@ const MaximumResultsPerPage = 50
 //go:generate counterfeiter . Client
 type Client interface {
 	GetServices(serviceName string) ([]Service, error)
+	GetAllServices() ([]Service, error)
 	GetServicePlans(serviceGuid string) ([]ServicePlan, error)
 	GetServicePlanInstances(servicePlanGuid string) (chan ServiceInstance, chan error)
-	DeleteServiceInstance(serviceInstanceGuid string, recursive bool) error
+	GetUserProvidedServiceInstances() (chan ServiceInstance, chan error)
+	DeleteServiceInstance(serviceInstanceGuid string, recursive bool) (inProgress bool, err error)
+	DeleteUserProvidedServiceInstance(serviceInstanceGuid string) error
+	PurgeServiceInstance(serviceInstanceGuid string) error
+	GetServiceInstanceParameters(serviceInstanceGuid string) (map[string]interface{}, error)
+	GetServiceInstanceLabels(serviceInstanceGuid string) (map[string]string, error)
+	GetServiceBindings(serviceInstanceGuid string) ([]ServiceBinding, error)
+	GetServiceKeys(serviceInstanceGuid string) ([]ServiceKey, error)
+	DeleteServiceKey(serviceKeyGuid string) error
+	DeleteServiceBinding(serviceBindingGuid string) (inProgress bool, err error)
+	GetServiceBinding(serviceBindingGuid string) (serviceBinding ServiceBinding, found bool, err error)
+	GetServiceInstance(serviceInstanceGuid string) (serviceInstance ServiceInstance, found bool, err error)
+	GetUserProvidedServiceInstance(serviceInstanceGuid string) (serviceInstance ServiceInstance, found bool, err error)
+	GetServiceInstanceRoutes(serviceInstanceGuid string) ([]Route, error)
+	UnbindRouteService(serviceInstanceGuid string, routeGuid string) error
+	CreateServiceInstance(request CreateServiceInstanceRequest) (ServiceInstance, error)
+	GetServiceBindingDeleteEvents(spaceGuid string) ([]Event, error)
+	GetApps() (chan App, chan error)
+	DeleteApp(appGuid string) error
+	GetAppRoutes(appGuid string) ([]Route, error)
+	GetRouteApps(routeGuid string) ([]App, error)
+	DeleteRoute(routeGuid string) error
+	GetSpaces() (chan Space, chan error)
+	GetSpaceUsage(spaceGuid string) (SpaceUsage, error)
+	DeleteSpace(spaceGuid string) error
+	GetSpace(spaceGuid string) (Space, error)
+	GetSpaceQuotaDefinition(spaceQuotaDefinitionGuid string) (QuotaDefinition, error)
+	GetOrganization(organizationGuid string) (Organization, error)
+	GetOrganizationQuotaDefinition(quotaDefinitionGuid string) (QuotaDefinition, error)
+	CountSpaceServiceInstances(spaceGuid string) (int, error)
+	CountOrganizationServiceInstances(organizationGuid string) (int, error)
+}
+
+// brokerUnreachableErrorCodes are the error codes with which the Cloud Controller reports that it could not reach a
+// service broker.
+var brokerUnreachableErrorCodes = map[string]bool{
+	"CF-ServiceBrokerApiUnreachable": true,
+	"CF-ServiceBrokerApiTimeout":     true,
+}
+
+// BrokerUnreachableError indicates that the Cloud Controller could not reach the service broker responsible for a
+// service instance, typically because the broker has been removed.
+type BrokerUnreachableError struct {
+	message string
+}
+
+func (e *BrokerUnreachableError) Error() string {
+	return e.message
+}
+
+func IsBrokerUnreachable(err error) bool {
+	var brokerUnreachableError *BrokerUnreachableError
+	return errors.As(err, &brokerUnreachableError)
 }
 
 type client struct {
@@ -84,6 +140,38 @@ func GetOauthToken(client httpclient.HttpClient, apiUrl string, username string,
 	return tokenResp.AccessToken, nil
 }
 
+// GetServerTime returns the current time according to the Cloud Controller, as given by the Date header of its
+// response to /v2/info.
+func GetServerTime(client httpclient.HttpClient, apiUrl string) (time.Time, error) {
+	request, err := http.NewRequest("GET", apiUrl+"/v2/info", nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to build http request: %s", err)
+	}
+	request.Header.Add("Accept", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("/v2/info failure: %s", err)
+	}
+	if response.Body != nil {
+		defer response.Body.Close()
+	}
+	if response.StatusCode != http.StatusOK {
+		return time.Time{}, fmt.Errorf("/v2/info failure: request failed: %s", response.Status)
+	}
+
+	date := response.Header.Get("Date")
+	if date == "" {
+		return time.Time{}, errors.New("/v2/info failure: response has no Date header")
+	}
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("/v2/info failure: invalid Date header: %s", date)
+	}
+
+	return serverTime.UTC(), nil
+}
+
 func NewClient(authClient httpclient.AuthenticatedClient, apiUrl, accessToken string) Client {
 	return &client{
 		authClient:  authClient,
@@ -99,6 +187,25 @@ func (cf *client) GetServices(serviceName string) (services []Service, err error
 	return
 }
 
+func (cf *client) GetAllServices() (services []Service, err error) {
+	services = make([]Service, 0)
+	endpoint := fmt.Sprintf("/v2/services?results-per-page=%d", MaximumResultsPerPage)
+
+	for endpoint != "" {
+		var servicesResponse listServicesResponse
+		err = cf.get(endpoint, &servicesResponse)
+		if err != nil {
+			return
+		}
+
+		services = append(services, servicesResponse.Resources...)
+
+		endpoint = servicesResponse.NextUrl
+	}
+
+	return
+}
+
 func (cf *client) GetServicePlans(serviceGuid string) (servicePlans []ServicePlan, err error) {
 	servicePlans = make([]ServicePlan, 0)
 	endpoint := fmt.Sprintf("/v2/services/%s/service_plans?results-per-page=%d", serviceGuid, MaximumResultsPerPage)
@@ -149,13 +256,387 @@ func (cf *client) GetServicePlanInstances(servicePlanGuid string) (servicePlanIn
 	return
 }
 
-func (cf *client) DeleteServiceInstance(serviceInstanceGuid string, recursive bool) (err error) {
-	return cf.delete(fmt.Sprintf("/v2/service_instances/%s?accepts_incomplete=true;async=true;recursive=%t", serviceInstanceGuid, recursive))
+func (cf *client) GetUserProvidedServiceInstances() (serviceInstances chan ServiceInstance, errorChannel chan error) {
+	serviceInstances = make(chan ServiceInstance, MaximumResultsPerPage)
+	errorChannel = make(chan error, 1)
+
+	endpoint := fmt.Sprintf("/v2/user_provided_service_instances?results-per-page=%d", MaximumResultsPerPage)
+
+	go func() {
+		defer close(serviceInstances)
+		defer close(errorChannel)
+
+		for endpoint != "" {
+			var userProvidedServiceInstancesResponse listUserProvidedServiceInstancesResponse
+			err := cf.get(endpoint, &userProvidedServiceInstancesResponse)
+			if err != nil {
+				errorChannel <- err
+				return
+			}
+
+			for _, serviceInstance := range userProvidedServiceInstancesResponse.Resources {
+				// The type is implied by the endpoint and is not always present in the response
+				serviceInstance.Entity.Type = UserProvidedServiceInstanceType
+				serviceInstances <- serviceInstance
+			}
+
+			endpoint = userProvidedServiceInstancesResponse.NextUrl
+		}
+	}()
+
+	return
+}
+
+// DeleteServiceInstance deletes the service instance via its service broker, allowing the broker to do so
+// asynchronously. If the broker has yet to finish, inProgress is true and the service instance remains, as
+// GetServiceInstance shows, until it has. If the Cloud Controller reports that the broker cannot be reached, it
+// returns a BrokerUnreachableError.
+func (cf *client) DeleteServiceInstance(serviceInstanceGuid string, recursive bool) (inProgress bool, err error) {
+	endpoint := fmt.Sprintf("/v2/service_instances/%s?accepts_incomplete=true;async=true;recursive=%t", serviceInstanceGuid, recursive)
+	statusCode, errorCode, err := cf.deleteWithStatus(endpoint, http.StatusNoContent, http.StatusAccepted)
+	if err != nil && brokerUnreachableErrorCodes[errorCode] {
+		return false, &BrokerUnreachableError{fmt.Sprintf("DELETE %s failed: service broker unreachable (%s)", endpoint, errorCode)}
+	}
+	return statusCode == http.StatusAccepted, err
+}
+
+// PurgeServiceInstance removes the service instance, together with its bindings and keys, from the Cloud Controller
+// without contacting its service broker.
+func (cf *client) PurgeServiceInstance(serviceInstanceGuid string) error {
+	return cf.delete(fmt.Sprintf("/v2/service_instances/%s?purge=true", serviceInstanceGuid))
+}
+
+func (cf *client) DeleteUserProvidedServiceInstance(serviceInstanceGuid string) error {
+	return cf.delete(fmt.Sprintf("/v2/user_provided_service_instances/%s", serviceInstanceGuid))
+}
+
+func (cf *client) GetServiceInstanceParameters(serviceInstanceGuid string) (parameters map[string]interface{}, err error) {
+	parameters = make(map[string]interface{})
+	err = cf.get(fmt.Sprintf("/v2/service_instances/%s/parameters", serviceInstanceGuid), &parameters)
+	return
+}
+
+// GetServiceInstanceLabels returns the metadata labels of the given service instance. Labels are served only by
+// version 3 of the Cloud Controller API.
+func (cf *client) GetServiceInstanceLabels(serviceInstanceGuid string) (map[string]string, error) {
+	var serviceInstance struct {
+		Metadata struct {
+			Labels map[string]string
+		}
+	}
+	err := cf.get(fmt.Sprintf("/v3/service_instances/%s", serviceInstanceGuid), &serviceInstance)
+	return serviceInstance.Metadata.Labels, err
+}
+
+// GetServiceBindings returns the service bindings of the given service instance. It filters all service bindings by
+// service instance, rather than listing those of /v2/service_instances/:guid, because that endpoint only serves managed
+// service instances and so fails for user-provided ones.
+func (cf *client) GetServiceBindings(serviceInstanceGuid string) (serviceBindings []ServiceBinding, err error) {
+	serviceBindings = make([]ServiceBinding, 0)
+	endpoint := fmt.Sprintf("/v2/service_bindings?q=service_instance_guid:%s&results-per-page=%d", serviceInstanceGuid, MaximumResultsPerPage)
+
+	for endpoint != "" {
+		var serviceBindingsResponse listServiceBindingsResponse
+		err = cf.get(endpoint, &serviceBindingsResponse)
+		if err != nil {
+			return
+		}
+
+		serviceBindings = append(serviceBindings, serviceBindingsResponse.Resources...)
+		endpoint = serviceBindingsResponse.NextUrl
+	}
+
+	return
+}
+
+// GetServiceKeys returns the service keys of the given service instance. Like GetServiceBindings, it filters all service
+// keys by service instance, so that a user-provided service instance, which cannot have service keys, has none rather
+// than failing.
+func (cf *client) GetServiceKeys(serviceInstanceGuid string) (serviceKeys []ServiceKey, err error) {
+	serviceKeys = make([]ServiceKey, 0)
+	endpoint := fmt.Sprintf("/v2/service_keys?q=service_instance_guid:%s&results-per-page=%d", serviceInstanceGuid, MaximumResultsPerPage)
+
+	for endpoint != "" {
+		var serviceKeysResponse listServiceKeysResponse
+		err = cf.get(endpoint, &serviceKeysResponse)
+		if err != nil {
+			return
+		}
+
+		serviceKeys = append(serviceKeys, serviceKeysResponse.Resources...)
+		endpoint = serviceKeysResponse.NextUrl
+	}
+
+	return
+}
+
+// GetServiceBindingDeleteEvents returns the audit events recorded when service bindings were deleted in the given
+// space, most recent first.
+func (cf *client) GetServiceBindingDeleteEvents(spaceGuid string) (events []Event, err error) {
+	events = make([]Event, 0)
+	endpoint := fmt.Sprintf("/v2/events?q=type:audit.service_binding.delete&q=space_guid:%s&order-direction=desc&results-per-page=%d", spaceGuid, MaximumResultsPerPage)
+
+	for endpoint != "" {
+		var eventsResponse listEventsResponse
+		err = cf.get(endpoint, &eventsResponse)
+		if err != nil {
+			return
+		}
+
+		events = append(events, eventsResponse.Resources...)
+		endpoint = eventsResponse.NextUrl
+	}
+
+	return
+}
+
+func (cf *client) DeleteServiceKey(serviceKeyGuid string) error {
+	return cf.delete(fmt.Sprintf("/v2/service_keys/%s", serviceKeyGuid))
+}
+
+// DeleteServiceBinding unbinds the service binding, allowing its broker to do so asynchronously. If the broker has
+// yet to finish, inProgress is true and the service binding remains, as GetServiceBinding shows, until it has.
+func (cf *client) DeleteServiceBinding(serviceBindingGuid string) (inProgress bool, err error) {
+	statusCode, _, err := cf.deleteWithStatus(fmt.Sprintf("/v2/service_bindings/%s?accepts_incomplete=true", serviceBindingGuid),
+		http.StatusNoContent, http.StatusAccepted)
+	return statusCode == http.StatusAccepted, err
+}
+
+// GetServiceBinding returns the service binding with the given guid, if it exists.
+func (cf *client) GetServiceBinding(serviceBindingGuid string) (serviceBinding ServiceBinding, found bool, err error) {
+	found, err = cf.getIfFound(fmt.Sprintf("/v2/service_bindings/%s", serviceBindingGuid), &serviceBinding)
+	return
+}
+
+// GetServiceInstance returns the managed service instance with the given guid, if it exists.
+func (cf *client) GetServiceInstance(serviceInstanceGuid string) (serviceInstance ServiceInstance, found bool, err error) {
+	found, err = cf.getIfFound(fmt.Sprintf("/v2/service_instances/%s", serviceInstanceGuid), &serviceInstance)
+	return
+}
+
+// GetUserProvidedServiceInstance returns the user-provided service instance with the given guid, if it exists.
+func (cf *client) GetUserProvidedServiceInstance(serviceInstanceGuid string) (serviceInstance ServiceInstance, found bool, err error) {
+	found, err = cf.getIfFound(fmt.Sprintf("/v2/user_provided_service_instances/%s", serviceInstanceGuid), &serviceInstance)
+	// The type is implied by the endpoint and is not always present in the response
+	serviceInstance.Entity.Type = UserProvidedServiceInstanceType
+	return
+}
+
+// GetServiceInstanceRoutes returns the routes bound to the given route service instance.
+func (cf *client) GetServiceInstanceRoutes(serviceInstanceGuid string) (routes []Route, err error) {
+	routes = make([]Route, 0)
+	endpoint := fmt.Sprintf("/v2/service_instances/%s/routes?results-per-page=%d", serviceInstanceGuid, MaximumResultsPerPage)
+
+	for endpoint != "" {
+		var routesResponse listRoutesResponse
+		err = cf.get(endpoint, &routesResponse)
+		if err != nil {
+			return
+		}
+
+		routes = append(routes, routesResponse.Resources...)
+		endpoint = routesResponse.NextUrl
+	}
+
+	return
+}
+
+func (cf *client) UnbindRouteService(serviceInstanceGuid string, routeGuid string) error {
+	return cf.delete(fmt.Sprintf("/v2/service_instances/%s/routes/%s", serviceInstanceGuid, routeGuid))
+}
+
+func (cf *client) CreateServiceInstance(request CreateServiceInstanceRequest) (serviceInstance ServiceInstance, err error) {
+	err = cf.post("/v2/service_instances?accepts_incomplete=true", request, &serviceInstance)
+	return
+}
+
+func (cf *client) GetApps() (apps chan App, errorChannel chan error) {
+	apps = make(chan App, MaximumResultsPerPage)
+	errorChannel = make(chan error, 1)
+
+	endpoint := fmt.Sprintf("/v2/apps?results-per-page=%d", MaximumResultsPerPage)
+
+	go func() {
+		defer close(apps)
+		defer close(errorChannel)
+
+		for endpoint != "" {
+			var appsResponse listAppsResponse
+			err := cf.get(endpoint, &appsResponse)
+			if err != nil {
+				errorChannel <- err
+				return
+			}
+
+			for _, app := range appsResponse.Resources {
+				apps <- app
+			}
+
+			endpoint = appsResponse.NextUrl
+		}
+	}()
+
+	return
+}
+
+// DeleteApp deletes the app together with its service bindings. Its routes are left in place.
+func (cf *client) DeleteApp(appGuid string) error {
+	return cf.delete(fmt.Sprintf("/v2/apps/%s?recursive=true", appGuid))
+}
+
+func (cf *client) GetAppRoutes(appGuid string) (routes []Route, err error) {
+	routes = make([]Route, 0)
+	endpoint := fmt.Sprintf("/v2/apps/%s/routes?results-per-page=%d", appGuid, MaximumResultsPerPage)
+
+	for endpoint != "" {
+		var routesResponse listRoutesResponse
+		err = cf.get(endpoint, &routesResponse)
+		if err != nil {
+			return
+		}
+
+		routes = append(routes, routesResponse.Resources...)
+		endpoint = routesResponse.NextUrl
+	}
+
+	return
+}
+
+func (cf *client) GetRouteApps(routeGuid string) (apps []App, err error) {
+	apps = make([]App, 0)
+	endpoint := fmt.Sprintf("/v2/routes/%s/apps?results-per-page=%d", routeGuid, MaximumResultsPerPage)
+
+	for endpoint != "" {
+		var appsResponse listAppsResponse
+		err = cf.get(endpoint, &appsResponse)
+		if err != nil {
+			return
+		}
+
+		apps = append(apps, appsResponse.Resources...)
+		endpoint = appsResponse.NextUrl
+	}
+
+	return
+}
+
+func (cf *client) DeleteRoute(routeGuid string) error {
+	return cf.delete(fmt.Sprintf("/v2/routes/%s", routeGuid))
+}
+
+func (cf *client) GetSpaces() (spaces chan Space, errorChannel chan error) {
+	spaces = make(chan Space, MaximumResultsPerPage)
+	errorChannel = make(chan error, 1)
+
+	endpoint := fmt.Sprintf("/v2/spaces?results-per-page=%d", MaximumResultsPerPage)
+
+	go func() {
+		defer close(spaces)
+		defer close(errorChannel)
+
+		for endpoint != "" {
+			var spacesResponse listSpacesResponse
+			err := cf.get(endpoint, &spacesResponse)
+			if err != nil {
+				errorChannel <- err
+				return
+			}
+
+			for _, space := range spacesResponse.Resources {
+				spaces <- space
+			}
+
+			endpoint = spacesResponse.NextUrl
+		}
+	}()
+
+	return
+}
+
+// GetSpaceUsage counts the apps, service instances, including user-provided service instances, and routes in the
+// given space without listing them.
+func (cf *client) GetSpaceUsage(spaceGuid string) (usage SpaceUsage, err error) {
+	usage.Apps, err = cf.count(fmt.Sprintf("/v2/spaces/%s/apps", spaceGuid))
+	if err != nil {
+		return
+	}
+
+	usage.ServiceInstances, err = cf.count(fmt.Sprintf("/v2/spaces/%s/service_instances?return_user_provided_service_instances=true", spaceGuid))
+	if err != nil {
+		return
+	}
+
+	usage.Routes, err = cf.count(fmt.Sprintf("/v2/spaces/%s/routes", spaceGuid))
+	return
+}
+
+func (cf *client) DeleteSpace(spaceGuid string) error {
+	return cf.delete(fmt.Sprintf("/v2/spaces/%s", spaceGuid))
+}
+
+func (cf *client) GetSpace(spaceGuid string) (space Space, err error) {
+	err = cf.get(fmt.Sprintf("/v2/spaces/%s", spaceGuid), &space)
+	return
+}
+
+func (cf *client) GetSpaceQuotaDefinition(spaceQuotaDefinitionGuid string) (quotaDefinition QuotaDefinition, err error) {
+	err = cf.get(fmt.Sprintf("/v2/space_quota_definitions/%s", spaceQuotaDefinitionGuid), &quotaDefinition)
+	return
+}
+
+func (cf *client) GetOrganization(organizationGuid string) (organization Organization, err error) {
+	err = cf.get(fmt.Sprintf("/v2/organizations/%s", organizationGuid), &organization)
+	return
+}
+
+func (cf *client) GetOrganizationQuotaDefinition(quotaDefinitionGuid string) (quotaDefinition QuotaDefinition, err error) {
+	err = cf.get(fmt.Sprintf("/v2/quota_definitions/%s", quotaDefinitionGuid), &quotaDefinition)
+	return
+}
+
+// CountSpaceServiceInstances counts the managed service instances in the given space, which are those counted
+// against quotas.
+func (cf *client) CountSpaceServiceInstances(spaceGuid string) (int, error) {
+	return cf.count(fmt.Sprintf("/v2/spaces/%s/service_instances", spaceGuid))
+}
+
+// CountOrganizationServiceInstances counts the managed service instances in all the spaces of the given
+// organization, which are those counted against its quota.
+func (cf *client) CountOrganizationServiceInstances(organizationGuid string) (int, error) {
+	return cf.count(fmt.Sprintf("/v2/service_instances?q=organization_guid:%s", organizationGuid))
+}
+
+// count returns the total number of resources listed by the given endpoint, fetching only the first of them.
+func (cf *client) count(endpoint string) (int, error) {
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+
+	var countResponse countResponse
+	err := cf.get(endpoint+separator+"results-per-page=1", &countResponse)
+	return countResponse.TotalResults, err
 }
 
 func (cf *client) get(endpoint string, response interface{}) error {
 	bodyReader, statusCode, err := cf.authClient.DoAuthenticatedGet(cf.apiUrl+endpoint, cf.accessToken)
+	return decodeGetResponse(endpoint, bodyReader, statusCode, err, response)
+}
+
+// getIfFound gets as get does, but reports a resource which does not exist as not found rather than as an error.
+func (cf *client) getIfFound(endpoint string, response interface{}) (found bool, err error) {
+	bodyReader, statusCode, err := cf.authClient.DoAuthenticatedGet(cf.apiUrl+endpoint, cf.accessToken)
+	if statusCode == http.StatusNotFound {
+		if bodyReader != nil {
+			bodyReader.Close()
+		}
+		return false, nil
+	}
+	return true, decodeGetResponse(endpoint, bodyReader, statusCode, err, response)
+}
 
+// decodeGetResponse decodes the response to a GET of the given endpoint, closing its body.
+func decodeGetResponse(endpoint string, bodyReader io.ReadCloser, statusCode int, err error, response interface{}) error {
 	if err != nil {
 		return fmt.Errorf("GET %s failed: %s", endpoint, err)
 	}
@@ -167,6 +648,7 @@ func (cf *client) get(endpoint string, response interface{}) error {
 	if bodyReader == nil {
 		return fmt.Errorf("GET %s response body missing", endpoint)
 	}
+	defer bodyReader.Close()
 
 	body, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
@@ -181,19 +663,72 @@ func (cf *client) get(endpoint string, response interface{}) error {
 	return nil
 }
 
-func (cf *client) delete(endpoint string) error {
-	statusCode, err := cf.authClient.DoAuthenticatedDelete(cf.apiUrl+endpoint, cf.accessToken)
+func (cf *client) post(endpoint string, request interface{}, response interface{}) error {
+	requestBody, err := json.Marshal(request)
 	if err != nil {
-		return fmt.Errorf("DELETE %s failed: %s", endpoint, err)
+		return fmt.Errorf("cannot encode POST %s request JSON: %s", endpoint, err)
 	}
 
-	if statusCode != http.StatusNoContent {
-		return fmt.Errorf("DELETE %s failed: HTTP status %d", endpoint, statusCode)
+	bodyReader, statusCode, err := cf.authClient.DoAuthenticatedPost(cf.apiUrl+endpoint, "application/json", string(requestBody), cf.accessToken)
+	if err != nil {
+		return fmt.Errorf("POST %s failed: %s", endpoint, err)
+	}
+
+	if statusCode != http.StatusCreated && statusCode != http.StatusAccepted {
+		return fmt.Errorf("POST %s failed: HTTP status %d", endpoint, statusCode)
+	}
+
+	if bodyReader == nil {
+		return fmt.Errorf("POST %s response body missing", endpoint)
+	}
+	defer bodyReader.Close()
+
+	body, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return fmt.Errorf("cannot read POST %s response body: %s", endpoint, err)
+	}
+
+	err = json.Unmarshal(body, response)
+	if err != nil {
+		return fmt.Errorf("invalid POST %s response JSON: %s", endpoint, err)
 	}
 
 	return nil
 }
 
+func (cf *client) delete(endpoint string) error {
+	_, _, err := cf.deleteWithStatus(endpoint, http.StatusNoContent)
+	return err
+}
+
+// deleteWithStatus deletes as delete does, but succeeds with any of the given HTTP status codes and returns the one
+// received. If the deletion fails, it also returns the error code, such as CF-ServiceBrokerApiTimeout, given in the
+// Cloud Controller's response, if any.
+func (cf *client) deleteWithStatus(endpoint string, successStatusCodes ...int) (statusCode int, errorCode string, err error) {
+	body, statusCode, err := cf.authClient.DoAuthenticatedDelete(cf.apiUrl+endpoint, cf.accessToken)
+	if body != nil {
+		defer body.Close()
+	}
+
+	if err != nil {
+		var errorResponse struct {
+			ErrorCode string `json:"error_code"`
+		}
+		if body != nil {
+			json.NewDecoder(body).Decode(&errorResponse)
+		}
+		return statusCode, errorResponse.ErrorCode, fmt.Errorf("DELETE %s failed: %s", endpoint, err)
+	}
+
+	for _, successStatusCode := range successStatusCodes {
+		if statusCode == successStatusCode {
+			return statusCode, "", nil
+		}
+	}
+
+	return statusCode, "", fmt.Errorf("DELETE %s failed: HTTP status %d", endpoint, statusCode)
+}
+
 func do(client httpclient.HttpClient, request *http.Request, v interface{}) error {
 	response, err := client.Do(request)
 	if err != nil {
@@ -27,6 +27,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"time"
 )
 
 const (
@@ -35,6 +36,10 @@ const (
 	testServiceGuid         = "test-service-guid"
 	testServicePlanGuid     = "test-service-plan-guid"
 	testServiceInstanceGuid = "test-service-instance-guid"
+	testServiceKeyGuid      = "test-service-key-guid"
+	testAppGuid             = "test-app-guid"
+	testRouteGuid           = "test-route-guid"
+	testSpaceGuid           = "test-space-guid"
 	testApiUrl              = "example.com"
 )
 
@@ -237,6 +242,86 @@ var _ = Describe("CF", func() {
 				})
 			})
 		})
+
+		Describe("GetServerTime", func() {
+			var (
+				serverTime time.Time
+				err        error
+				apiUrl     string
+			)
+
+			BeforeEach(func() {
+				fakeClient = &httpclientfakes.FakeHttpClient{}
+				apiUrl = testApiUrl
+			})
+
+			JustBeforeEach(func() {
+				serverTime, err = cloudfoundry.GetServerTime(fakeClient, apiUrl)
+			})
+
+			Context("when /v2/info returns a Date header", func() {
+				BeforeEach(func() {
+					response := &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     http.Header{"Date": []string{"Wed, 24 Jan 2018 20:00:00 GMT"}},
+						Body:       ioutil.NopCloser(bytes.NewBufferString("{}")),
+					}
+					fakeClient.DoReturns(response, nil)
+				})
+
+				It("returns the time it gives", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(serverTime).To(Equal(time.Date(2018, 1, 24, 20, 0, 0, 0, time.UTC)))
+
+					request := fakeClient.DoArgsForCall(0)
+					Expect(request.URL.String()).To(Equal(testApiUrl + "/v2/info"))
+				})
+			})
+
+			Context("when /v2/info fails with an error", func() {
+				BeforeEach(func() {
+					fakeClient.DoReturns(&http.Response{}, testError)
+				})
+
+				It("percolates the error", func() {
+					Expect(err).To(MatchError(fmt.Sprintf("/v2/info failure: %s", testError)))
+				})
+			})
+
+			Context("when /v2/info fails with an invalid HTTP response", func() {
+				BeforeEach(func() {
+					fakeClient.DoReturns(&http.Response{StatusCode: http.StatusBadGateway, Status: "HTTP 502"}, nil)
+				})
+
+				It("percolates the error", func() {
+					Expect(err).To(MatchError("/v2/info failure: request failed: HTTP 502"))
+				})
+			})
+
+			Context("when /v2/info returns no Date header", func() {
+				BeforeEach(func() {
+					fakeClient.DoReturns(&http.Response{StatusCode: http.StatusOK}, nil)
+				})
+
+				It("returns a suitable error", func() {
+					Expect(err).To(MatchError("/v2/info failure: response has no Date header"))
+				})
+			})
+
+			Context("when /v2/info returns an invalid Date header", func() {
+				BeforeEach(func() {
+					response := &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     http.Header{"Date": []string{"yesterday"}},
+					}
+					fakeClient.DoReturns(response, nil)
+				})
+
+				It("returns a suitable error", func() {
+					Expect(err).To(MatchError("/v2/info failure: invalid Date header: yesterday"))
+				})
+			})
+		})
 	})
 
 	Describe("authenticated functions", func() {
@@ -292,6 +377,61 @@ var _ = Describe("CF", func() {
 			})
 		})
 
+		Describe("GetAllServices", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.GetAllServices() },
+				fmt.Sprintf("/v2/services?results-per-page=%d", cloudfoundry.MaximumResultsPerPage),
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					servicesJson := []string{
+						fmt.Sprintf(`{
+  "next_url": "/v2/services?page=2&results-per-page=%d",
+  "resources": [
+    {
+      "metadata": {
+        "guid": "service-guid-0"
+      },
+      "entity": {
+        "label": "service-label-0"
+      }
+    }
+  ]
+}`, cloudfoundry.MaximumResultsPerPage),
+						`{
+  "resources": [
+    {
+      "metadata": {
+        "guid": "service-guid-1"
+      },
+      "entity": {
+        "label": "service-label-1"
+      }
+    }
+  ]
+}`,
+					}
+					authClient.DoAuthenticatedGetReturnsOnCall(0, stringReadCloser(servicesJson[0]), http.StatusOK, nil)
+					authClient.DoAuthenticatedGetReturnsOnCall(1, stringReadCloser(servicesJson[1]), http.StatusOK, nil)
+				})
+
+				It("returns every service", func() {
+					services, err := cf.GetAllServices()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(authClient.DoAuthenticatedGetCallCount()).To(Equal(2), "Incorrect number of calls to CF API")
+					url, _ := authClient.DoAuthenticatedGetArgsForCall(1)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/services?page=2&results-per-page=%d", testApiUrl, cloudfoundry.MaximumResultsPerPage)))
+
+					Expect(services).To(HaveLen(2))
+					Expect(services[0].Metadata.Guid).To(Equal("service-guid-0"))
+					Expect(services[0].Entity.Label).To(Equal("service-label-0"))
+					Expect(services[1].Entity.Label).To(Equal("service-label-1"))
+				})
+			})
+		})
+
 		Describe("GetServicePlans", func() {
 			assertStandardHttpGetErrorHandling(
 				func() (interface{}, error) { return cf.GetServicePlans(testServiceGuid) },
@@ -425,70 +565,1097 @@ var _ = Describe("CF", func() {
 			})
 		})
 
-		Describe("DeleteServiceInstance", func() {
-			Context("when the recursive flag is false", func() {
-				assertStandardHttpDeleteErrorHandling(
-					func() error { return cf.DeleteServiceInstance(testServiceInstanceGuid, false) },
-					fmt.Sprintf("/v2/service_instances/%s?accepts_incomplete=true;async=true;recursive=false", testServiceInstanceGuid),
-				)
+		Describe("GetUserProvidedServiceInstances", func() {
+			assertPaginatedHttpGetErrorHandling(
+				func() (interface{}, chan error) { return cf.GetUserProvidedServiceInstances() },
+				fmt.Sprintf("/v2/user_provided_service_instances?results-per-page=%d", cloudfoundry.MaximumResultsPerPage),
+			)
 
-				Context("when the API call is successful", func() {
-					BeforeEach(func() {
-						authClient.DoAuthenticatedDeleteReturns(http.StatusNoContent, nil)
-					})
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+  "resources": [
+    {
+      "metadata": {
+        "guid": "user-provided-service-instance-guid-0",
+        "created_at": "user-provided-service-instance-created-at-0"
+      },
+      "entity": {
+        "name": "user-provided-service-instance-name-0",
+        "space_guid": "space-guid-0"
+      }
+    }
+  ]
+}`), http.StatusOK, nil)
+				})
 
-					It("succeeds", func() {
-						Expect(cf.DeleteServiceInstance(testServiceInstanceGuid, false)).To(Succeed())
-						Expect(authClient.DoAuthenticatedDeleteCallCount()).To(Equal(1), "Unexpected number of delete API calls")
-						url, accessToken := authClient.DoAuthenticatedDeleteArgsForCall(0)
-						Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_instances/%s?accepts_incomplete=true;async=true;recursive=false", testApiUrl, testServiceInstanceGuid)))
-						Expect(accessToken).To(Equal(testAccessToken))
-					})
+				It("returns the user-provided service instances", func() {
+					serviceInstances, errors := cf.GetUserProvidedServiceInstances()
+
+					var serviceInstance cloudfoundry.ServiceInstance
+					Eventually(serviceInstances).Should(Receive(&serviceInstance))
+					Expect(serviceInstance.Metadata.Guid).To(Equal("user-provided-service-instance-guid-0"))
+					Expect(serviceInstance.Entity.Name).To(Equal("user-provided-service-instance-name-0"))
+					Expect(serviceInstance.Entity.SpaceGuid).To(Equal("space-guid-0"))
+					Expect(serviceInstance.UserProvided()).To(BeTrue())
+					Eventually(serviceInstances).Should(BeClosed())
+
+					Eventually(errors).Should(BeClosed())
+					Expect(len(errors)).To(BeZero(), "No errors should have occurred")
 				})
 			})
+		})
 
-			Context("when the recursive flag is true", func() {
-				assertStandardHttpDeleteErrorHandling(
-					func() error { return cf.DeleteServiceInstance(testServiceInstanceGuid, true) },
-					fmt.Sprintf("/v2/service_instances/%s?accepts_incomplete=true;async=true;recursive=true", testServiceInstanceGuid),
-				)
+		Describe("DeleteUserProvidedServiceInstance", func() {
+			assertStandardHttpDeleteErrorHandling(
+				func() error { return cf.DeleteUserProvidedServiceInstance(testServiceInstanceGuid) },
+				fmt.Sprintf("/v2/user_provided_service_instances/%s", testServiceInstanceGuid),
+			)
 
-				Context("when the API call is successful", func() {
-					BeforeEach(func() {
-						authClient.DoAuthenticatedDeleteReturns(http.StatusNoContent, nil)
-					})
+			Context("when the API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedDeleteReturns(nil, http.StatusNoContent, nil)
+				})
 
-					It("succeeds", func() {
-						Expect(cf.DeleteServiceInstance(testServiceInstanceGuid, true)).To(Succeed())
-						Expect(authClient.DoAuthenticatedDeleteCallCount()).To(Equal(1), "Unexpected number of delete API calls")
-						url, accessToken := authClient.DoAuthenticatedDeleteArgsForCall(0)
-						Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_instances/%s?accepts_incomplete=true;async=true;recursive=true", testApiUrl, testServiceInstanceGuid)))
-						Expect(accessToken).To(Equal(testAccessToken))
-					})
+				It("succeeds", func() {
+					Expect(cf.DeleteUserProvidedServiceInstance(testServiceInstanceGuid)).To(Succeed())
+					url, _ := authClient.DoAuthenticatedDeleteArgsForCall(0)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/user_provided_service_instances/%s", testApiUrl, testServiceInstanceGuid)))
 				})
 			})
 		})
-	})
-})
 
-func assertStandardHttpDeleteErrorHandling(cfDeleteOperation func() error, expectedEndpoint string) {
-	Context("when call to the CF API fails", func() {
-		BeforeEach(func() {
-			authClient.DoAuthenticatedDeleteReturns(0, testError)
+		Describe("DeleteServiceKey", func() {
+			assertStandardHttpDeleteErrorHandling(
+				func() error { return cf.DeleteServiceKey(testServiceKeyGuid) },
+				fmt.Sprintf("/v2/service_keys/%s", testServiceKeyGuid),
+			)
+
+			Context("when the API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedDeleteReturns(nil, http.StatusNoContent, nil)
+				})
+
+				It("succeeds", func() {
+					Expect(cf.DeleteServiceKey(testServiceKeyGuid)).To(Succeed())
+					url, accessToken := authClient.DoAuthenticatedDeleteArgsForCall(0)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_keys/%s", testApiUrl, testServiceKeyGuid)))
+					Expect(accessToken).To(Equal(testAccessToken))
+				})
+			})
 		})
 
-		It("returns the error", func() {
-			Expect(cfDeleteOperation()).To(MatchError(fmt.Sprintf("DELETE %s failed: test error", expectedEndpoint)))
+		Describe("PurgeServiceInstance", func() {
+			assertStandardHttpDeleteErrorHandling(
+				func() error { return cf.PurgeServiceInstance(testServiceInstanceGuid) },
+				fmt.Sprintf("/v2/service_instances/%s?purge=true", testServiceInstanceGuid),
+			)
+
+			Context("when the API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedDeleteReturns(nil, http.StatusNoContent, nil)
+				})
+
+				It("succeeds", func() {
+					Expect(cf.PurgeServiceInstance(testServiceInstanceGuid)).To(Succeed())
+					url, accessToken := authClient.DoAuthenticatedDeleteArgsForCall(0)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_instances/%s?purge=true", testApiUrl, testServiceInstanceGuid)))
+					Expect(accessToken).To(Equal(testAccessToken))
+				})
+			})
 		})
-	})
 
-	Context("when a non-OK HTTP status code is returned from the CF API", func() {
-		BeforeEach(func() {
-			authClient.DoAuthenticatedDeleteReturns(http.StatusInternalServerError, nil)
+		Describe("GetServiceInstanceParameters", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.GetServiceInstanceParameters(testServiceInstanceGuid) },
+				fmt.Sprintf("/v2/service_instances/%s/parameters", testServiceInstanceGuid),
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{"key": "value", "count": 2}`), http.StatusOK, nil)
+				})
+
+				It("returns the parameters of the service instance", func() {
+					parameters, err := cf.GetServiceInstanceParameters(testServiceInstanceGuid)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(parameters).To(Equal(map[string]interface{}{"key": "value", "count": float64(2)}))
+
+					url, accessToken := authClient.DoAuthenticatedGetArgsForCall(0)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_instances/%s/parameters", testApiUrl, testServiceInstanceGuid)))
+					Expect(accessToken).To(Equal(testAccessToken))
+				})
+			})
 		})
 
-		It("returns the error", func() {
-			Expect(cfDeleteOperation()).To(MatchError(fmt.Sprintf("DELETE %s failed: HTTP status 500", expectedEndpoint)))
+		Describe("GetServiceInstanceLabels", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.GetServiceInstanceLabels(testServiceInstanceGuid) },
+				fmt.Sprintf("/v3/service_instances/%s", testServiceInstanceGuid),
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+						"guid": "test-service-instance-guid",
+						"metadata": {"labels": {"team": "perf", "env": "ci"}, "annotations": {}}
+					}`), http.StatusOK, nil)
+				})
+
+				It("returns the labels of the service instance", func() {
+					labels, err := cf.GetServiceInstanceLabels(testServiceInstanceGuid)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(labels).To(Equal(map[string]string{"team": "perf", "env": "ci"}))
+
+					url, accessToken := authClient.DoAuthenticatedGetArgsForCall(0)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v3/service_instances/%s", testApiUrl, testServiceInstanceGuid)))
+					Expect(accessToken).To(Equal(testAccessToken))
+				})
+			})
+		})
+
+		Describe("GetServiceBindings", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.GetServiceBindings(testServiceInstanceGuid) },
+				fmt.Sprintf("/v2/service_bindings?q=service_instance_guid:%s&results-per-page=%d", testServiceInstanceGuid, cloudfoundry.MaximumResultsPerPage),
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturnsOnCall(0, stringReadCloser(`{
+  "next_url": "/v2/service_bindings?q=service_instance_guid:test-service-instance-guid&page=2",
+  "resources": [
+    {
+      "metadata": {
+        "guid": "service-binding-guid-0"
+      },
+      "entity": {
+        "app_guid": "app-guid-0",
+        "service_instance_guid": "test-service-instance-guid"
+      }
+    }
+  ]
+}`), http.StatusOK, nil)
+					authClient.DoAuthenticatedGetReturnsOnCall(1, stringReadCloser(`{
+  "resources": [
+    {
+      "metadata": {
+        "guid": "service-binding-guid-1"
+      },
+      "entity": {
+        "app_guid": "app-guid-1",
+        "service_instance_guid": "test-service-instance-guid"
+      }
+    }
+  ]
+}`), http.StatusOK, nil)
+				})
+
+				It("returns every page of service bindings", func() {
+					serviceBindings, err := cf.GetServiceBindings(testServiceInstanceGuid)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(authClient.DoAuthenticatedGetCallCount()).To(Equal(2), "Incorrect number of calls to CF API")
+					url, _ := authClient.DoAuthenticatedGetArgsForCall(0)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_bindings?q=service_instance_guid:%s&results-per-page=%d", testApiUrl, testServiceInstanceGuid, cloudfoundry.MaximumResultsPerPage)),
+						"Service bindings should be filtered by service instance so that those of user-provided service instances are found")
+					url, _ = authClient.DoAuthenticatedGetArgsForCall(1)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_bindings?q=service_instance_guid:%s&page=2", testApiUrl, testServiceInstanceGuid)))
+
+					Expect(serviceBindings).To(HaveLen(2))
+					Expect(serviceBindings[0].Metadata.Guid).To(Equal("service-binding-guid-0"))
+					Expect(serviceBindings[0].Entity.AppGuid).To(Equal("app-guid-0"))
+					Expect(serviceBindings[1].Metadata.Guid).To(Equal("service-binding-guid-1"))
+					Expect(serviceBindings[1].Entity.AppGuid).To(Equal("app-guid-1"))
+				})
+			})
+		})
+
+		Describe("GetServiceKeys", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.GetServiceKeys(testServiceInstanceGuid) },
+				fmt.Sprintf("/v2/service_keys?q=service_instance_guid:%s&results-per-page=%d", testServiceInstanceGuid, cloudfoundry.MaximumResultsPerPage),
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+  "resources": [
+    {
+      "metadata": {
+        "guid": "service-key-guid-0"
+      },
+      "entity": {
+        "name": "service-key-name-0",
+        "service_instance_guid": "test-service-instance-guid",
+        "credentials": {
+          "password": "secret"
+        }
+      }
+    }
+  ]
+}`), http.StatusOK, nil)
+				})
+
+				It("returns the service keys", func() {
+					serviceKeys, err := cf.GetServiceKeys(testServiceInstanceGuid)
+					Expect(err).NotTo(HaveOccurred())
+
+					url, _ := authClient.DoAuthenticatedGetArgsForCall(0)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_keys?q=service_instance_guid:%s&results-per-page=%d", testApiUrl, testServiceInstanceGuid, cloudfoundry.MaximumResultsPerPage)),
+						"Service keys should be filtered by service instance so that user-provided service instances have none")
+
+					Expect(serviceKeys).To(HaveLen(1))
+					Expect(serviceKeys[0].Metadata.Guid).To(Equal("service-key-guid-0"))
+					Expect(serviceKeys[0].Entity.Name).To(Equal("service-key-name-0"))
+				})
+			})
+		})
+
+		Describe("DeleteServiceBinding", func() {
+			const testServiceBindingGuid = "test-service-binding-guid"
+
+			assertStandardHttpDeleteErrorHandling(
+				func() error {
+					_, err := cf.DeleteServiceBinding(testServiceBindingGuid)
+					return err
+				},
+				fmt.Sprintf("/v2/service_bindings/%s?accepts_incomplete=true", testServiceBindingGuid),
+			)
+
+			Context("when the API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedDeleteReturns(nil, http.StatusNoContent, nil)
+				})
+
+				It("succeeds", func() {
+					inProgress, err := cf.DeleteServiceBinding(testServiceBindingGuid)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(inProgress).To(BeFalse())
+					url, accessToken := authClient.DoAuthenticatedDeleteArgsForCall(0)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_bindings/%s?accepts_incomplete=true", testApiUrl, testServiceBindingGuid)))
+					Expect(accessToken).To(Equal(testAccessToken))
+				})
+			})
+
+			Context("when the broker unbinds asynchronously", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedDeleteReturns(stringReadCloser(`{}`), http.StatusAccepted, nil)
+				})
+
+				It("succeeds and reports that the unbinding is in progress", func() {
+					inProgress, err := cf.DeleteServiceBinding(testServiceBindingGuid)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(inProgress).To(BeTrue())
+				})
+			})
+		})
+
+		Describe("GetServiceBinding", func() {
+			const testServiceBindingGuid = "test-service-binding-guid"
+
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) {
+					serviceBinding, _, err := cf.GetServiceBinding(testServiceBindingGuid)
+					return serviceBinding, err
+				},
+				fmt.Sprintf("/v2/service_bindings/%s", testServiceBindingGuid),
+			)
+
+			Context("when the service binding exists", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+						"metadata": {"guid": "test-service-binding-guid"},
+						"entity": {"app_guid": "app-guid", "last_operation": {"type": "delete", "state": "in progress"}}
+					}`), http.StatusOK, nil)
+				})
+
+				It("returns it", func() {
+					serviceBinding, found, err := cf.GetServiceBinding(testServiceBindingGuid)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(serviceBinding.Metadata.Guid).To(Equal(testServiceBindingGuid))
+					Expect(serviceBinding.Entity.LastOperation.State).To(Equal(cloudfoundry.LastOperationInProgress))
+					url, _ := authClient.DoAuthenticatedGetArgsForCall(0)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_bindings/%s", testApiUrl, testServiceBindingGuid)))
+				})
+			})
+
+			Context("when the service binding does not exist", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(nil, http.StatusNotFound, testError)
+				})
+
+				It("reports that it was not found", func() {
+					_, found, err := cf.GetServiceBinding(testServiceBindingGuid)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeFalse())
+				})
+			})
+		})
+
+		Describe("GetServiceInstance", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) {
+					serviceInstance, _, err := cf.GetServiceInstance(testServiceInstanceGuid)
+					return serviceInstance, err
+				},
+				fmt.Sprintf("/v2/service_instances/%s", testServiceInstanceGuid),
+			)
+
+			Context("when the service instance exists", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+						"metadata": {"guid": "test-service-instance-guid"},
+						"entity": {"name": "name", "service_plan_guid": "plan-guid", "tags": ["tag"]}
+					}`), http.StatusOK, nil)
+				})
+
+				It("returns it", func() {
+					serviceInstance, found, err := cf.GetServiceInstance(testServiceInstanceGuid)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(serviceInstance.Metadata.Guid).To(Equal(testServiceInstanceGuid))
+					Expect(serviceInstance.Entity.ServicePlanGuid).To(Equal("plan-guid"))
+					Expect(serviceInstance.Entity.Tags).To(Equal([]string{"tag"}))
+					url, _ := authClient.DoAuthenticatedGetArgsForCall(0)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_instances/%s", testApiUrl, testServiceInstanceGuid)))
+				})
+			})
+
+			Context("when the service instance does not exist", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(nil, http.StatusNotFound, testError)
+				})
+
+				It("reports that it was not found", func() {
+					_, found, err := cf.GetServiceInstance(testServiceInstanceGuid)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeFalse())
+				})
+			})
+		})
+
+		Describe("GetUserProvidedServiceInstance", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) {
+					serviceInstance, _, err := cf.GetUserProvidedServiceInstance(testServiceInstanceGuid)
+					return serviceInstance, err
+				},
+				fmt.Sprintf("/v2/user_provided_service_instances/%s", testServiceInstanceGuid),
+			)
+
+			Context("when the service instance exists", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+						"metadata": {"guid": "test-service-instance-guid"},
+						"entity": {"name": "name"}
+					}`), http.StatusOK, nil)
+				})
+
+				It("returns it as a user-provided service instance", func() {
+					serviceInstance, found, err := cf.GetUserProvidedServiceInstance(testServiceInstanceGuid)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(serviceInstance.Metadata.Guid).To(Equal(testServiceInstanceGuid))
+					Expect(serviceInstance.UserProvided()).To(BeTrue())
+				})
+			})
+
+			Context("when the service instance does not exist", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(nil, http.StatusNotFound, testError)
+				})
+
+				It("reports that it was not found", func() {
+					_, found, err := cf.GetUserProvidedServiceInstance(testServiceInstanceGuid)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeFalse())
+				})
+			})
+		})
+
+		Describe("GetServiceInstanceRoutes", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.GetServiceInstanceRoutes(testServiceInstanceGuid) },
+				fmt.Sprintf("/v2/service_instances/%s/routes?results-per-page=%d", testServiceInstanceGuid, cloudfoundry.MaximumResultsPerPage),
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+  "resources": [
+    {
+      "metadata": {
+        "guid": "route-guid-0"
+      },
+      "entity": {
+        "host": "route-host-0"
+      }
+    }
+  ]
+}`), http.StatusOK, nil)
+				})
+
+				It("returns the routes", func() {
+					routes, err := cf.GetServiceInstanceRoutes(testServiceInstanceGuid)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(routes).To(HaveLen(1))
+					Expect(routes[0].Metadata.Guid).To(Equal("route-guid-0"))
+					Expect(routes[0].Entity.Host).To(Equal("route-host-0"))
+				})
+			})
+		})
+
+		Describe("UnbindRouteService", func() {
+			assertStandardHttpDeleteErrorHandling(
+				func() error { return cf.UnbindRouteService(testServiceInstanceGuid, testRouteGuid) },
+				fmt.Sprintf("/v2/service_instances/%s/routes/%s", testServiceInstanceGuid, testRouteGuid),
+			)
+
+			Context("when the API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedDeleteReturns(nil, http.StatusNoContent, nil)
+				})
+
+				It("succeeds", func() {
+					Expect(cf.UnbindRouteService(testServiceInstanceGuid, testRouteGuid)).To(Succeed())
+					url, accessToken := authClient.DoAuthenticatedDeleteArgsForCall(0)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_instances/%s/routes/%s", testApiUrl, testServiceInstanceGuid, testRouteGuid)))
+					Expect(accessToken).To(Equal(testAccessToken))
+				})
+			})
+		})
+
+		Describe("GetApps", func() {
+			assertPaginatedHttpGetErrorHandling(
+				func() (interface{}, chan error) { return cf.GetApps() },
+				fmt.Sprintf("/v2/apps?results-per-page=%d", cloudfoundry.MaximumResultsPerPage),
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+  "resources": [
+    {
+      "metadata": {
+        "guid": "app-guid-0",
+        "created_at": "2018-01-01T10:00:00Z"
+      },
+      "entity": {
+        "name": "app-name-0",
+        "space_guid": "space-guid-0",
+        "state": "STOPPED"
+      }
+    }
+  ]
+}`), http.StatusOK, nil)
+				})
+
+				It("returns the apps", func() {
+					apps, errors := cf.GetApps()
+
+					var app cloudfoundry.App
+					Eventually(apps).Should(Receive(&app))
+					Expect(app.Metadata.Guid).To(Equal("app-guid-0"))
+					Expect(app.Metadata.CreatedAt).To(Equal("2018-01-01T10:00:00Z"))
+					Expect(app.Entity.Name).To(Equal("app-name-0"))
+					Expect(app.Entity.SpaceGuid).To(Equal("space-guid-0"))
+					Expect(app.Entity.State).To(Equal(cloudfoundry.AppStopped))
+					Eventually(apps).Should(BeClosed())
+
+					Eventually(errors).Should(BeClosed())
+					Expect(len(errors)).To(BeZero(), "No errors should have occurred")
+				})
+			})
+		})
+
+		Describe("DeleteApp", func() {
+			assertStandardHttpDeleteErrorHandling(
+				func() error { return cf.DeleteApp(testAppGuid) },
+				fmt.Sprintf("/v2/apps/%s?recursive=true", testAppGuid),
+			)
+
+			Context("when the API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedDeleteReturns(nil, http.StatusNoContent, nil)
+				})
+
+				It("succeeds", func() {
+					Expect(cf.DeleteApp(testAppGuid)).To(Succeed())
+					url, accessToken := authClient.DoAuthenticatedDeleteArgsForCall(0)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/apps/%s?recursive=true", testApiUrl, testAppGuid)))
+					Expect(accessToken).To(Equal(testAccessToken))
+				})
+			})
+		})
+
+		Describe("GetAppRoutes", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.GetAppRoutes(testAppGuid) },
+				fmt.Sprintf("/v2/apps/%s/routes?results-per-page=%d", testAppGuid, cloudfoundry.MaximumResultsPerPage),
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+  "resources": [
+    {
+      "metadata": {
+        "guid": "route-guid-0"
+      },
+      "entity": {
+        "host": "route-host-0",
+        "path": "/route-path-0",
+        "domain_guid": "domain-guid-0",
+        "space_guid": "space-guid-0"
+      }
+    }
+  ]
+}`), http.StatusOK, nil)
+				})
+
+				It("returns the routes", func() {
+					routes, err := cf.GetAppRoutes(testAppGuid)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(routes).To(HaveLen(1))
+					Expect(routes[0].Metadata.Guid).To(Equal("route-guid-0"))
+					Expect(routes[0].Entity.Host).To(Equal("route-host-0"))
+					Expect(routes[0].Entity.Path).To(Equal("/route-path-0"))
+					Expect(routes[0].Entity.DomainGuid).To(Equal("domain-guid-0"))
+					Expect(routes[0].Entity.SpaceGuid).To(Equal("space-guid-0"))
+				})
+			})
+		})
+
+		Describe("GetRouteApps", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.GetRouteApps(testRouteGuid) },
+				fmt.Sprintf("/v2/routes/%s/apps?results-per-page=%d", testRouteGuid, cloudfoundry.MaximumResultsPerPage),
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+  "resources": [
+    {
+      "metadata": {
+        "guid": "app-guid-0"
+      },
+      "entity": {
+        "name": "app-name-0"
+      }
+    }
+  ]
+}`), http.StatusOK, nil)
+				})
+
+				It("returns the apps", func() {
+					apps, err := cf.GetRouteApps(testRouteGuid)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(apps).To(HaveLen(1))
+					Expect(apps[0].Metadata.Guid).To(Equal("app-guid-0"))
+					Expect(apps[0].Entity.Name).To(Equal("app-name-0"))
+				})
+			})
+		})
+
+		Describe("DeleteRoute", func() {
+			assertStandardHttpDeleteErrorHandling(
+				func() error { return cf.DeleteRoute(testRouteGuid) },
+				fmt.Sprintf("/v2/routes/%s", testRouteGuid),
+			)
+
+			Context("when the API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedDeleteReturns(nil, http.StatusNoContent, nil)
+				})
+
+				It("succeeds", func() {
+					Expect(cf.DeleteRoute(testRouteGuid)).To(Succeed())
+					url, accessToken := authClient.DoAuthenticatedDeleteArgsForCall(0)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/routes/%s", testApiUrl, testRouteGuid)))
+					Expect(accessToken).To(Equal(testAccessToken))
+				})
+			})
+		})
+
+		Describe("GetSpaces", func() {
+			assertPaginatedHttpGetErrorHandling(
+				func() (interface{}, chan error) { return cf.GetSpaces() },
+				fmt.Sprintf("/v2/spaces?results-per-page=%d", cloudfoundry.MaximumResultsPerPage),
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+  "resources": [
+    {
+      "metadata": {
+        "guid": "space-guid-0",
+        "created_at": "2018-01-01T10:00:00Z"
+      },
+      "entity": {
+        "name": "space-name-0",
+        "organization_guid": "organization-guid-0"
+      }
+    }
+  ]
+}`), http.StatusOK, nil)
+				})
+
+				It("returns the spaces", func() {
+					spaces, errors := cf.GetSpaces()
+
+					var space cloudfoundry.Space
+					Eventually(spaces).Should(Receive(&space))
+					Expect(space.Metadata.Guid).To(Equal("space-guid-0"))
+					Expect(space.Metadata.CreatedAt).To(Equal("2018-01-01T10:00:00Z"))
+					Expect(space.Entity.Name).To(Equal("space-name-0"))
+					Expect(space.Entity.OrganizationGuid).To(Equal("organization-guid-0"))
+					Eventually(spaces).Should(BeClosed())
+
+					Eventually(errors).Should(BeClosed())
+					Expect(len(errors)).To(BeZero(), "No errors should have occurred")
+				})
+			})
+		})
+
+		Describe("GetSpaceUsage", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.GetSpaceUsage(testSpaceGuid) },
+				fmt.Sprintf("/v2/spaces/%s/apps?results-per-page=1", testSpaceGuid),
+			)
+
+			Context("when the CF API calls are successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturnsOnCall(0, stringReadCloser(`{"total_results": 1}`), http.StatusOK, nil)
+					authClient.DoAuthenticatedGetReturnsOnCall(1, stringReadCloser(`{"total_results": 2}`), http.StatusOK, nil)
+					authClient.DoAuthenticatedGetReturnsOnCall(2, stringReadCloser(`{"total_results": 3}`), http.StatusOK, nil)
+				})
+
+				It("counts the resources in the space", func() {
+					usage, err := cf.GetSpaceUsage(testSpaceGuid)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(usage).To(Equal(cloudfoundry.SpaceUsage{Apps: 1, ServiceInstances: 2, Routes: 3}))
+					Expect(usage.Empty()).To(BeFalse())
+
+					Expect(authClient.DoAuthenticatedGetCallCount()).To(Equal(3))
+					url, _ := authClient.DoAuthenticatedGetArgsForCall(1)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/spaces/%s/service_instances?return_user_provided_service_instances=true&results-per-page=1", testApiUrl, testSpaceGuid)))
+					url, _ = authClient.DoAuthenticatedGetArgsForCall(2)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/spaces/%s/routes?results-per-page=1", testApiUrl, testSpaceGuid)))
+				})
+			})
+		})
+
+		Describe("DeleteSpace", func() {
+			assertStandardHttpDeleteErrorHandling(
+				func() error { return cf.DeleteSpace(testSpaceGuid) },
+				fmt.Sprintf("/v2/spaces/%s", testSpaceGuid),
+			)
+
+			Context("when the API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedDeleteReturns(nil, http.StatusNoContent, nil)
+				})
+
+				It("succeeds", func() {
+					Expect(cf.DeleteSpace(testSpaceGuid)).To(Succeed())
+					url, accessToken := authClient.DoAuthenticatedDeleteArgsForCall(0)
+					Expect(url).To(Equal(fmt.Sprintf("%s/v2/spaces/%s", testApiUrl, testSpaceGuid)))
+					Expect(accessToken).To(Equal(testAccessToken))
+				})
+			})
+		})
+
+		Describe("GetSpace", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.GetSpace(testSpaceGuid) },
+				fmt.Sprintf("/v2/spaces/%s", testSpaceGuid),
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+  "metadata": {
+    "guid": "test-space-guid"
+  },
+  "entity": {
+    "name": "space-name",
+    "organization_guid": "organization-guid",
+    "space_quota_definition_guid": "space-quota-definition-guid"
+  }
+}`), http.StatusOK, nil)
+				})
+
+				It("returns the space", func() {
+					space, err := cf.GetSpace(testSpaceGuid)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(space.Metadata.Guid).To(Equal(testSpaceGuid))
+					Expect(space.Entity.OrganizationGuid).To(Equal("organization-guid"))
+					Expect(space.Entity.SpaceQuotaDefinitionGuid).To(Equal("space-quota-definition-guid"))
+				})
+			})
+		})
+
+		Describe("GetSpaceQuotaDefinition", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.GetSpaceQuotaDefinition("space-quota-definition-guid") },
+				"/v2/space_quota_definitions/space-quota-definition-guid",
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+  "entity": {
+    "name": "space-quota",
+    "total_services": 10
+  }
+}`), http.StatusOK, nil)
+				})
+
+				It("returns the quota definition", func() {
+					quotaDefinition, err := cf.GetSpaceQuotaDefinition("space-quota-definition-guid")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(quotaDefinition.Entity.Name).To(Equal("space-quota"))
+					Expect(quotaDefinition.Entity.TotalServices).To(Equal(10))
+				})
+			})
+		})
+
+		Describe("GetOrganization", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.GetOrganization("organization-guid") },
+				"/v2/organizations/organization-guid",
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+  "entity": {
+    "name": "organization-name",
+    "quota_definition_guid": "quota-definition-guid"
+  }
+}`), http.StatusOK, nil)
+				})
+
+				It("returns the organization", func() {
+					organization, err := cf.GetOrganization("organization-guid")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(organization.Entity.Name).To(Equal("organization-name"))
+					Expect(organization.Entity.QuotaDefinitionGuid).To(Equal("quota-definition-guid"))
+				})
+			})
+		})
+
+		Describe("GetOrganizationQuotaDefinition", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.GetOrganizationQuotaDefinition("quota-definition-guid") },
+				"/v2/quota_definitions/quota-definition-guid",
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+  "entity": {
+    "name": "default",
+    "total_services": -1
+  }
+}`), http.StatusOK, nil)
+				})
+
+				It("returns the quota definition", func() {
+					quotaDefinition, err := cf.GetOrganizationQuotaDefinition("quota-definition-guid")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(quotaDefinition.Entity.TotalServices).To(Equal(cloudfoundry.UnlimitedServices))
+				})
+			})
+		})
+
+		Describe("CountSpaceServiceInstances", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.CountSpaceServiceInstances(testSpaceGuid) },
+				fmt.Sprintf("/v2/spaces/%s/service_instances?results-per-page=1", testSpaceGuid),
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{"total_results": 7}`), http.StatusOK, nil)
+				})
+
+				It("returns the number of service instances", func() {
+					Expect(cf.CountSpaceServiceInstances(testSpaceGuid)).To(Equal(7))
+				})
+			})
+		})
+
+		Describe("CountOrganizationServiceInstances", func() {
+			const testOrganizationGuid = "test-organization-guid"
+
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.CountOrganizationServiceInstances(testOrganizationGuid) },
+				fmt.Sprintf("/v2/service_instances?q=organization_guid:%s&results-per-page=1", testOrganizationGuid),
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{"total_results": 12}`), http.StatusOK, nil)
+				})
+
+				It("returns the number of service instances", func() {
+					Expect(cf.CountOrganizationServiceInstances(testOrganizationGuid)).To(Equal(12))
+				})
+			})
+		})
+
+		Describe("GetServiceBindingDeleteEvents", func() {
+			assertStandardHttpGetErrorHandling(
+				func() (interface{}, error) { return cf.GetServiceBindingDeleteEvents(testSpaceGuid) },
+				fmt.Sprintf("/v2/events?q=type:audit.service_binding.delete&q=space_guid:%s&order-direction=desc&results-per-page=%d", testSpaceGuid, cloudfoundry.MaximumResultsPerPage),
+			)
+
+			Context("when the CF API call is successful", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedGetReturns(stringReadCloser(`{
+  "resources": [
+    {
+      "metadata": {
+        "guid": "event-guid-0"
+      },
+      "entity": {
+        "type": "audit.service_binding.delete",
+        "actee": "service-binding-guid-0",
+        "timestamp": "2018-01-24T10:00:00Z",
+        "metadata": {
+          "request": {
+            "app_guid": "app-guid-0",
+            "service_instance_guid": "test-service-instance-guid"
+          }
+        }
+      }
+    }
+  ]
+}`), http.StatusOK, nil)
+				})
+
+				It("returns the events", func() {
+					events, err := cf.GetServiceBindingDeleteEvents(testSpaceGuid)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(events).To(HaveLen(1))
+					Expect(events[0].Entity.Type).To(Equal("audit.service_binding.delete"))
+					Expect(events[0].Entity.Timestamp).To(Equal("2018-01-24T10:00:00Z"))
+					Expect(events[0].Entity.Metadata.Request.ServiceInstanceGuid).To(Equal(testServiceInstanceGuid))
+				})
+			})
+		})
+
+		Describe("CreateServiceInstance", func() {
+			var (
+				request         cloudfoundry.CreateServiceInstanceRequest
+				serviceInstance cloudfoundry.ServiceInstance
+				err             error
+			)
+
+			BeforeEach(func() {
+				request = cloudfoundry.CreateServiceInstanceRequest{
+					Name:            "service-instance-name",
+					SpaceGuid:       "space-guid",
+					ServicePlanGuid: testServicePlanGuid,
+					Parameters:      map[string]interface{}{"key": "value"},
+					Tags:            []string{"tag"},
+				}
+				authClient.DoAuthenticatedPostReturns(stringReadCloser(`{
+  "metadata": {
+    "guid": "new-service-instance-guid"
+  },
+  "entity": {
+    "name": "service-instance-name"
+  }
+}`), http.StatusAccepted, nil)
+			})
+
+			JustBeforeEach(func() {
+				serviceInstance, err = cf.CreateServiceInstance(request)
+			})
+
+			It("POSTs the service instance definition", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(authClient.DoAuthenticatedPostCallCount()).To(Equal(1), "Unexpected number of post API calls")
+				url, bodyType, body, accessToken := authClient.DoAuthenticatedPostArgsForCall(0)
+				Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_instances?accepts_incomplete=true", testApiUrl)))
+				Expect(bodyType).To(Equal("application/json"))
+				Expect(body).To(MatchJSON(`{
+  "name": "service-instance-name",
+  "space_guid": "space-guid",
+  "service_plan_guid": "test-service-plan-guid",
+  "parameters": {"key": "value"},
+  "tags": ["tag"]
+}`))
+				Expect(accessToken).To(Equal(testAccessToken))
+			})
+
+			It("returns the new service instance", func() {
+				Expect(serviceInstance.Metadata.Guid).To(Equal("new-service-instance-guid"))
+				Expect(serviceInstance.Entity.Name).To(Equal("service-instance-name"))
+			})
+
+			Context("when call to the CF API fails", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedPostReturns(nil, 0, testError)
+				})
+
+				It("returns the error", func() {
+					Expect(err).To(MatchError("POST /v2/service_instances?accepts_incomplete=true failed: test error"))
+				})
+			})
+
+			Context("when an unexpected HTTP status code is returned from the CF API", func() {
+				BeforeEach(func() {
+					authClient.DoAuthenticatedPostReturns(stringReadCloser("{}"), http.StatusOK, nil)
+				})
+
+				It("returns the error", func() {
+					Expect(err).To(MatchError("POST /v2/service_instances?accepts_incomplete=true failed: HTTP status 200"))
+				})
+			})
+		})
+
+		Describe("DeleteServiceInstance", func() {
+			Context("when the recursive flag is false", func() {
+				assertStandardHttpDeleteErrorHandling(
+					func() error {
+						_, err := cf.DeleteServiceInstance(testServiceInstanceGuid, false)
+						return err
+					},
+					fmt.Sprintf("/v2/service_instances/%s?accepts_incomplete=true;async=true;recursive=false", testServiceInstanceGuid),
+				)
+
+				Context("when the CF API reports that the service broker is unreachable", func() {
+					BeforeEach(func() {
+						authClient.DoAuthenticatedDeleteReturns(stringReadCloser(`{"code": 10001, "error_code": "CF-ServiceBrokerApiUnreachable"}`), http.StatusBadGateway, testError)
+					})
+
+					It("returns a broker unreachable error", func() {
+						_, err := cf.DeleteServiceInstance(testServiceInstanceGuid, false)
+						Expect(err).To(MatchError(fmt.Sprintf("DELETE /v2/service_instances/%s?accepts_incomplete=true;async=true;recursive=false failed: service broker unreachable (CF-ServiceBrokerApiUnreachable)", testServiceInstanceGuid)))
+						Expect(cloudfoundry.IsBrokerUnreachable(err)).To(BeTrue())
+					})
+				})
+
+				Context("when the CF API reports that the service broker timed out", func() {
+					BeforeEach(func() {
+						authClient.DoAuthenticatedDeleteReturns(stringReadCloser(`{"code": 10001, "error_code": "CF-ServiceBrokerApiTimeout"}`), http.StatusGatewayTimeout, testError)
+					})
+
+					It("returns a broker unreachable error", func() {
+						_, err := cf.DeleteServiceInstance(testServiceInstanceGuid, false)
+						Expect(cloudfoundry.IsBrokerUnreachable(err)).To(BeTrue())
+					})
+				})
+
+				Context("when the CF API reports some other error", func() {
+					BeforeEach(func() {
+						authClient.DoAuthenticatedDeleteReturns(stringReadCloser(`{"code": 10001, "error_code": "CF-ServiceBrokerBadResponse"}`), http.StatusBadGateway, testError)
+					})
+
+					It("returns an ordinary error", func() {
+						_, err := cf.DeleteServiceInstance(testServiceInstanceGuid, false)
+						Expect(cloudfoundry.IsBrokerUnreachable(err)).To(BeFalse())
+					})
+				})
+
+				Context("when the API call is successful", func() {
+					BeforeEach(func() {
+						authClient.DoAuthenticatedDeleteReturns(nil, http.StatusNoContent, nil)
+					})
+
+					It("succeeds", func() {
+						inProgress, err := cf.DeleteServiceInstance(testServiceInstanceGuid, false)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(inProgress).To(BeFalse())
+						Expect(authClient.DoAuthenticatedDeleteCallCount()).To(Equal(1), "Unexpected number of delete API calls")
+						url, accessToken := authClient.DoAuthenticatedDeleteArgsForCall(0)
+						Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_instances/%s?accepts_incomplete=true;async=true;recursive=false", testApiUrl, testServiceInstanceGuid)))
+						Expect(accessToken).To(Equal(testAccessToken))
+					})
+				})
+
+				Context("when the broker deletes the service instance asynchronously", func() {
+					BeforeEach(func() {
+						authClient.DoAuthenticatedDeleteReturns(stringReadCloser(`{}`), http.StatusAccepted, nil)
+					})
+
+					It("succeeds and reports that the deletion is in progress", func() {
+						inProgress, err := cf.DeleteServiceInstance(testServiceInstanceGuid, false)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(inProgress).To(BeTrue())
+					})
+				})
+			})
+
+			Context("when the recursive flag is true", func() {
+				assertStandardHttpDeleteErrorHandling(
+					func() error {
+						_, err := cf.DeleteServiceInstance(testServiceInstanceGuid, true)
+						return err
+					},
+					fmt.Sprintf("/v2/service_instances/%s?accepts_incomplete=true;async=true;recursive=true", testServiceInstanceGuid),
+				)
+
+				Context("when the API call is successful", func() {
+					BeforeEach(func() {
+						authClient.DoAuthenticatedDeleteReturns(nil, http.StatusNoContent, nil)
+					})
+
+					It("succeeds", func() {
+						_, err := cf.DeleteServiceInstance(testServiceInstanceGuid, true)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(authClient.DoAuthenticatedDeleteCallCount()).To(Equal(1), "Unexpected number of delete API calls")
+						url, accessToken := authClient.DoAuthenticatedDeleteArgsForCall(0)
+						Expect(url).To(Equal(fmt.Sprintf("%s/v2/service_instances/%s?accepts_incomplete=true;async=true;recursive=true", testApiUrl, testServiceInstanceGuid)))
+						Expect(accessToken).To(Equal(testAccessToken))
+					})
+				})
+			})
+		})
+	})
+})
+
+func assertStandardHttpDeleteErrorHandling(cfDeleteOperation func() error, expectedEndpoint string) {
+	Context("when call to the CF API fails", func() {
+		BeforeEach(func() {
+			authClient.DoAuthenticatedDeleteReturns(nil, 0, testError)
+		})
+
+		It("returns the error", func() {
+			Expect(cfDeleteOperation()).To(MatchError(fmt.Sprintf("DELETE %s failed: test error", expectedEndpoint)))
+		})
+	})
+
+	Context("when a non-OK HTTP status code is returned from the CF API", func() {
+		BeforeEach(func() {
+			authClient.DoAuthenticatedDeleteReturns(nil, http.StatusInternalServerError, nil)
+		})
+
+		It("returns the error", func() {
+			Expect(cfDeleteOperation()).To(MatchError(fmt.Sprintf("DELETE %s failed: HTTP status 500", expectedEndpoint)))
+		})
+	})
+
+	Context("when a gateway in front of the CF API fails", func() {
+		BeforeEach(func() {
+			authClient.DoAuthenticatedDeleteReturns(stringReadCloser("<html>Bad Gateway</html>"), http.StatusBadGateway, testError)
+		})
+
+		It("returns an ordinary error", func() {
+			err := cfDeleteOperation()
+			Expect(err).To(MatchError(fmt.Sprintf("DELETE %s failed: test error", expectedEndpoint)))
+			Expect(cloudfoundry.IsBrokerUnreachable(err)).To(BeFalse())
 		})
 	})
 }
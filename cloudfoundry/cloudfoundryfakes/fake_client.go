@@ -21,6 +21,18 @@ type FakeClient struct {
 		result1 []cloudfoundry.Service
 		result2 error
 	}
+	GetAllServicesStub        func() ([]cloudfoundry.Service, error)
+	getAllServicesMutex       sync.RWMutex
+	getAllServicesArgsForCall []struct {
+	}
+	getAllServicesReturns struct {
+		result1 []cloudfoundry.Service
+		result2 error
+	}
+	getAllServicesReturnsOnCall map[int]struct {
+		result1 []cloudfoundry.Service
+		result2 error
+	}
 	GetServicePlansStub        func(serviceGuid string) ([]cloudfoundry.ServicePlan, error)
 	getServicePlansMutex       sync.RWMutex
 	getServicePlansArgsForCall []struct {
@@ -47,18 +59,400 @@ type FakeClient struct {
 		result1 chan cloudfoundry.ServiceInstance
 		result2 chan error
 	}
-	DeleteServiceInstanceStub        func(serviceInstanceGuid string, recursive bool) error
+	GetUserProvidedServiceInstancesStub        func() (chan cloudfoundry.ServiceInstance, chan error)
+	getUserProvidedServiceInstancesMutex       sync.RWMutex
+	getUserProvidedServiceInstancesArgsForCall []struct {
+	}
+	getUserProvidedServiceInstancesReturns struct {
+		result1 chan cloudfoundry.ServiceInstance
+		result2 chan error
+	}
+	getUserProvidedServiceInstancesReturnsOnCall map[int]struct {
+		result1 chan cloudfoundry.ServiceInstance
+		result2 chan error
+	}
+	DeleteServiceInstanceStub        func(serviceInstanceGuid string, recursive bool) (bool, error)
 	deleteServiceInstanceMutex       sync.RWMutex
 	deleteServiceInstanceArgsForCall []struct {
 		serviceInstanceGuid string
 		recursive           bool
 	}
 	deleteServiceInstanceReturns struct {
-		result1 error
+		result1 bool
+		result2 error
 	}
 	deleteServiceInstanceReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	DeleteUserProvidedServiceInstanceStub        func(serviceInstanceGuid string) error
+	deleteUserProvidedServiceInstanceMutex       sync.RWMutex
+	deleteUserProvidedServiceInstanceArgsForCall []struct {
+		serviceInstanceGuid string
+	}
+	deleteUserProvidedServiceInstanceReturns struct {
+		result1 error
+	}
+	deleteUserProvidedServiceInstanceReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PurgeServiceInstanceStub        func(serviceInstanceGuid string) error
+	purgeServiceInstanceMutex       sync.RWMutex
+	purgeServiceInstanceArgsForCall []struct {
+		serviceInstanceGuid string
+	}
+	purgeServiceInstanceReturns struct {
+		result1 error
+	}
+	purgeServiceInstanceReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetServiceInstanceParametersStub        func(serviceInstanceGuid string) (map[string]interface{}, error)
+	getServiceInstanceParametersMutex       sync.RWMutex
+	getServiceInstanceParametersArgsForCall []struct {
+		serviceInstanceGuid string
+	}
+	getServiceInstanceParametersReturns struct {
+		result1 map[string]interface{}
+		result2 error
+	}
+	getServiceInstanceParametersReturnsOnCall map[int]struct {
+		result1 map[string]interface{}
+		result2 error
+	}
+	GetServiceInstanceLabelsStub        func(serviceInstanceGuid string) (map[string]string, error)
+	getServiceInstanceLabelsMutex       sync.RWMutex
+	getServiceInstanceLabelsArgsForCall []struct {
+		serviceInstanceGuid string
+	}
+	getServiceInstanceLabelsReturns struct {
+		result1 map[string]string
+		result2 error
+	}
+	getServiceInstanceLabelsReturnsOnCall map[int]struct {
+		result1 map[string]string
+		result2 error
+	}
+	GetServiceBindingsStub        func(serviceInstanceGuid string) ([]cloudfoundry.ServiceBinding, error)
+	getServiceBindingsMutex       sync.RWMutex
+	getServiceBindingsArgsForCall []struct {
+		serviceInstanceGuid string
+	}
+	getServiceBindingsReturns struct {
+		result1 []cloudfoundry.ServiceBinding
+		result2 error
+	}
+	getServiceBindingsReturnsOnCall map[int]struct {
+		result1 []cloudfoundry.ServiceBinding
+		result2 error
+	}
+	GetServiceKeysStub        func(serviceInstanceGuid string) ([]cloudfoundry.ServiceKey, error)
+	getServiceKeysMutex       sync.RWMutex
+	getServiceKeysArgsForCall []struct {
+		serviceInstanceGuid string
+	}
+	getServiceKeysReturns struct {
+		result1 []cloudfoundry.ServiceKey
+		result2 error
+	}
+	getServiceKeysReturnsOnCall map[int]struct {
+		result1 []cloudfoundry.ServiceKey
+		result2 error
+	}
+	DeleteServiceKeyStub        func(serviceKeyGuid string) error
+	deleteServiceKeyMutex       sync.RWMutex
+	deleteServiceKeyArgsForCall []struct {
+		serviceKeyGuid string
+	}
+	deleteServiceKeyReturns struct {
+		result1 error
+	}
+	deleteServiceKeyReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteServiceBindingStub        func(serviceBindingGuid string) (bool, error)
+	deleteServiceBindingMutex       sync.RWMutex
+	deleteServiceBindingArgsForCall []struct {
+		serviceBindingGuid string
+	}
+	deleteServiceBindingReturns struct {
+		result1 bool
+		result2 error
+	}
+	deleteServiceBindingReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	GetServiceBindingStub        func(serviceBindingGuid string) (cloudfoundry.ServiceBinding, bool, error)
+	getServiceBindingMutex       sync.RWMutex
+	getServiceBindingArgsForCall []struct {
+		serviceBindingGuid string
+	}
+	getServiceBindingReturns struct {
+		result1 cloudfoundry.ServiceBinding
+		result2 bool
+		result3 error
+	}
+	getServiceBindingReturnsOnCall map[int]struct {
+		result1 cloudfoundry.ServiceBinding
+		result2 bool
+		result3 error
+	}
+	GetServiceInstanceStub        func(serviceInstanceGuid string) (cloudfoundry.ServiceInstance, bool, error)
+	getServiceInstanceMutex       sync.RWMutex
+	getServiceInstanceArgsForCall []struct {
+		serviceInstanceGuid string
+	}
+	getServiceInstanceReturns struct {
+		result1 cloudfoundry.ServiceInstance
+		result2 bool
+		result3 error
+	}
+	getServiceInstanceReturnsOnCall map[int]struct {
+		result1 cloudfoundry.ServiceInstance
+		result2 bool
+		result3 error
+	}
+	GetUserProvidedServiceInstanceStub        func(serviceInstanceGuid string) (cloudfoundry.ServiceInstance, bool, error)
+	getUserProvidedServiceInstanceMutex       sync.RWMutex
+	getUserProvidedServiceInstanceArgsForCall []struct {
+		serviceInstanceGuid string
+	}
+	getUserProvidedServiceInstanceReturns struct {
+		result1 cloudfoundry.ServiceInstance
+		result2 bool
+		result3 error
+	}
+	getUserProvidedServiceInstanceReturnsOnCall map[int]struct {
+		result1 cloudfoundry.ServiceInstance
+		result2 bool
+		result3 error
+	}
+	GetServiceInstanceRoutesStub        func(serviceInstanceGuid string) ([]cloudfoundry.Route, error)
+	getServiceInstanceRoutesMutex       sync.RWMutex
+	getServiceInstanceRoutesArgsForCall []struct {
+		serviceInstanceGuid string
+	}
+	getServiceInstanceRoutesReturns struct {
+		result1 []cloudfoundry.Route
+		result2 error
+	}
+	getServiceInstanceRoutesReturnsOnCall map[int]struct {
+		result1 []cloudfoundry.Route
+		result2 error
+	}
+	UnbindRouteServiceStub        func(serviceInstanceGuid string, routeGuid string) error
+	unbindRouteServiceMutex       sync.RWMutex
+	unbindRouteServiceArgsForCall []struct {
+		serviceInstanceGuid string
+		routeGuid           string
+	}
+	unbindRouteServiceReturns struct {
+		result1 error
+	}
+	unbindRouteServiceReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateServiceInstanceStub        func(request cloudfoundry.CreateServiceInstanceRequest) (cloudfoundry.ServiceInstance, error)
+	createServiceInstanceMutex       sync.RWMutex
+	createServiceInstanceArgsForCall []struct {
+		request cloudfoundry.CreateServiceInstanceRequest
+	}
+	createServiceInstanceReturns struct {
+		result1 cloudfoundry.ServiceInstance
+		result2 error
+	}
+	createServiceInstanceReturnsOnCall map[int]struct {
+		result1 cloudfoundry.ServiceInstance
+		result2 error
+	}
+	GetServiceBindingDeleteEventsStub        func(spaceGuid string) ([]cloudfoundry.Event, error)
+	getServiceBindingDeleteEventsMutex       sync.RWMutex
+	getServiceBindingDeleteEventsArgsForCall []struct {
+		spaceGuid string
+	}
+	getServiceBindingDeleteEventsReturns struct {
+		result1 []cloudfoundry.Event
+		result2 error
+	}
+	getServiceBindingDeleteEventsReturnsOnCall map[int]struct {
+		result1 []cloudfoundry.Event
+		result2 error
+	}
+	GetAppsStub        func() (chan cloudfoundry.App, chan error)
+	getAppsMutex       sync.RWMutex
+	getAppsArgsForCall []struct {
+	}
+	getAppsReturns struct {
+		result1 chan cloudfoundry.App
+		result2 chan error
+	}
+	getAppsReturnsOnCall map[int]struct {
+		result1 chan cloudfoundry.App
+		result2 chan error
+	}
+	DeleteAppStub        func(appGuid string) error
+	deleteAppMutex       sync.RWMutex
+	deleteAppArgsForCall []struct {
+		appGuid string
+	}
+	deleteAppReturns struct {
+		result1 error
+	}
+	deleteAppReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetAppRoutesStub        func(appGuid string) ([]cloudfoundry.Route, error)
+	getAppRoutesMutex       sync.RWMutex
+	getAppRoutesArgsForCall []struct {
+		appGuid string
+	}
+	getAppRoutesReturns struct {
+		result1 []cloudfoundry.Route
+		result2 error
+	}
+	getAppRoutesReturnsOnCall map[int]struct {
+		result1 []cloudfoundry.Route
+		result2 error
+	}
+	GetRouteAppsStub        func(routeGuid string) ([]cloudfoundry.App, error)
+	getRouteAppsMutex       sync.RWMutex
+	getRouteAppsArgsForCall []struct {
+		routeGuid string
+	}
+	getRouteAppsReturns struct {
+		result1 []cloudfoundry.App
+		result2 error
+	}
+	getRouteAppsReturnsOnCall map[int]struct {
+		result1 []cloudfoundry.App
+		result2 error
+	}
+	DeleteRouteStub        func(routeGuid string) error
+	deleteRouteMutex       sync.RWMutex
+	deleteRouteArgsForCall []struct {
+		routeGuid string
+	}
+	deleteRouteReturns struct {
+		result1 error
+	}
+	deleteRouteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetSpacesStub        func() (chan cloudfoundry.Space, chan error)
+	getSpacesMutex       sync.RWMutex
+	getSpacesArgsForCall []struct {
+	}
+	getSpacesReturns struct {
+		result1 chan cloudfoundry.Space
+		result2 chan error
+	}
+	getSpacesReturnsOnCall map[int]struct {
+		result1 chan cloudfoundry.Space
+		result2 chan error
+	}
+	GetSpaceUsageStub        func(spaceGuid string) (cloudfoundry.SpaceUsage, error)
+	getSpaceUsageMutex       sync.RWMutex
+	getSpaceUsageArgsForCall []struct {
+		spaceGuid string
+	}
+	getSpaceUsageReturns struct {
+		result1 cloudfoundry.SpaceUsage
+		result2 error
+	}
+	getSpaceUsageReturnsOnCall map[int]struct {
+		result1 cloudfoundry.SpaceUsage
+		result2 error
+	}
+	DeleteSpaceStub        func(spaceGuid string) error
+	deleteSpaceMutex       sync.RWMutex
+	deleteSpaceArgsForCall []struct {
+		spaceGuid string
+	}
+	deleteSpaceReturns struct {
+		result1 error
+	}
+	deleteSpaceReturnsOnCall map[int]struct {
 		result1 error
 	}
+	GetSpaceStub        func(spaceGuid string) (cloudfoundry.Space, error)
+	getSpaceMutex       sync.RWMutex
+	getSpaceArgsForCall []struct {
+		spaceGuid string
+	}
+	getSpaceReturns struct {
+		result1 cloudfoundry.Space
+		result2 error
+	}
+	getSpaceReturnsOnCall map[int]struct {
+		result1 cloudfoundry.Space
+		result2 error
+	}
+	GetSpaceQuotaDefinitionStub        func(spaceQuotaDefinitionGuid string) (cloudfoundry.QuotaDefinition, error)
+	getSpaceQuotaDefinitionMutex       sync.RWMutex
+	getSpaceQuotaDefinitionArgsForCall []struct {
+		spaceQuotaDefinitionGuid string
+	}
+	getSpaceQuotaDefinitionReturns struct {
+		result1 cloudfoundry.QuotaDefinition
+		result2 error
+	}
+	getSpaceQuotaDefinitionReturnsOnCall map[int]struct {
+		result1 cloudfoundry.QuotaDefinition
+		result2 error
+	}
+	GetOrganizationStub        func(organizationGuid string) (cloudfoundry.Organization, error)
+	getOrganizationMutex       sync.RWMutex
+	getOrganizationArgsForCall []struct {
+		organizationGuid string
+	}
+	getOrganizationReturns struct {
+		result1 cloudfoundry.Organization
+		result2 error
+	}
+	getOrganizationReturnsOnCall map[int]struct {
+		result1 cloudfoundry.Organization
+		result2 error
+	}
+	GetOrganizationQuotaDefinitionStub        func(quotaDefinitionGuid string) (cloudfoundry.QuotaDefinition, error)
+	getOrganizationQuotaDefinitionMutex       sync.RWMutex
+	getOrganizationQuotaDefinitionArgsForCall []struct {
+		quotaDefinitionGuid string
+	}
+	getOrganizationQuotaDefinitionReturns struct {
+		result1 cloudfoundry.QuotaDefinition
+		result2 error
+	}
+	getOrganizationQuotaDefinitionReturnsOnCall map[int]struct {
+		result1 cloudfoundry.QuotaDefinition
+		result2 error
+	}
+	CountSpaceServiceInstancesStub        func(spaceGuid string) (int, error)
+	countSpaceServiceInstancesMutex       sync.RWMutex
+	countSpaceServiceInstancesArgsForCall []struct {
+		spaceGuid string
+	}
+	countSpaceServiceInstancesReturns struct {
+		result1 int
+		result2 error
+	}
+	countSpaceServiceInstancesReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	CountOrganizationServiceInstancesStub        func(organizationGuid string) (int, error)
+	countOrganizationServiceInstancesMutex       sync.RWMutex
+	countOrganizationServiceInstancesArgsForCall []struct {
+		organizationGuid string
+	}
+	countOrganizationServiceInstancesReturns struct {
+		result1 int
+		result2 error
+	}
+	countOrganizationServiceInstancesReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -114,6 +508,50 @@ func (fake *FakeClient) GetServicesReturnsOnCall(i int, result1 []cloudfoundry.S
 	}{result1, result2}
 }
 
+func (fake *FakeClient) GetAllServices() ([]cloudfoundry.Service, error) {
+	fake.getAllServicesMutex.Lock()
+	ret, specificReturn := fake.getAllServicesReturnsOnCall[len(fake.getAllServicesArgsForCall)]
+	fake.getAllServicesArgsForCall = append(fake.getAllServicesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetAllServices", []interface{}{})
+	fake.getAllServicesMutex.Unlock()
+	if fake.GetAllServicesStub != nil {
+		return fake.GetAllServicesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getAllServicesReturns.result1, fake.getAllServicesReturns.result2
+}
+
+func (fake *FakeClient) GetAllServicesCallCount() int {
+	fake.getAllServicesMutex.RLock()
+	defer fake.getAllServicesMutex.RUnlock()
+	return len(fake.getAllServicesArgsForCall)
+}
+
+func (fake *FakeClient) GetAllServicesReturns(result1 []cloudfoundry.Service, result2 error) {
+	fake.GetAllServicesStub = nil
+	fake.getAllServicesReturns = struct {
+		result1 []cloudfoundry.Service
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetAllServicesReturnsOnCall(i int, result1 []cloudfoundry.Service, result2 error) {
+	fake.GetAllServicesStub = nil
+	if fake.getAllServicesReturnsOnCall == nil {
+		fake.getAllServicesReturnsOnCall = make(map[int]struct {
+			result1 []cloudfoundry.Service
+			result2 error
+		})
+	}
+	fake.getAllServicesReturnsOnCall[i] = struct {
+		result1 []cloudfoundry.Service
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) GetServicePlans(serviceGuid string) ([]cloudfoundry.ServicePlan, error) {
 	fake.getServicePlansMutex.Lock()
 	ret, specificReturn := fake.getServicePlansReturnsOnCall[len(fake.getServicePlansArgsForCall)]
@@ -216,7 +654,51 @@ func (fake *FakeClient) GetServicePlanInstancesReturnsOnCall(i int, result1 chan
 	}{result1, result2}
 }
 
-func (fake *FakeClient) DeleteServiceInstance(serviceInstanceGuid string, recursive bool) error {
+func (fake *FakeClient) GetUserProvidedServiceInstances() (chan cloudfoundry.ServiceInstance, chan error) {
+	fake.getUserProvidedServiceInstancesMutex.Lock()
+	ret, specificReturn := fake.getUserProvidedServiceInstancesReturnsOnCall[len(fake.getUserProvidedServiceInstancesArgsForCall)]
+	fake.getUserProvidedServiceInstancesArgsForCall = append(fake.getUserProvidedServiceInstancesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetUserProvidedServiceInstances", []interface{}{})
+	fake.getUserProvidedServiceInstancesMutex.Unlock()
+	if fake.GetUserProvidedServiceInstancesStub != nil {
+		return fake.GetUserProvidedServiceInstancesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getUserProvidedServiceInstancesReturns.result1, fake.getUserProvidedServiceInstancesReturns.result2
+}
+
+func (fake *FakeClient) GetUserProvidedServiceInstancesCallCount() int {
+	fake.getUserProvidedServiceInstancesMutex.RLock()
+	defer fake.getUserProvidedServiceInstancesMutex.RUnlock()
+	return len(fake.getUserProvidedServiceInstancesArgsForCall)
+}
+
+func (fake *FakeClient) GetUserProvidedServiceInstancesReturns(result1 chan cloudfoundry.ServiceInstance, result2 chan error) {
+	fake.GetUserProvidedServiceInstancesStub = nil
+	fake.getUserProvidedServiceInstancesReturns = struct {
+		result1 chan cloudfoundry.ServiceInstance
+		result2 chan error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetUserProvidedServiceInstancesReturnsOnCall(i int, result1 chan cloudfoundry.ServiceInstance, result2 chan error) {
+	fake.GetUserProvidedServiceInstancesStub = nil
+	if fake.getUserProvidedServiceInstancesReturnsOnCall == nil {
+		fake.getUserProvidedServiceInstancesReturnsOnCall = make(map[int]struct {
+			result1 chan cloudfoundry.ServiceInstance
+			result2 chan error
+		})
+	}
+	fake.getUserProvidedServiceInstancesReturnsOnCall[i] = struct {
+		result1 chan cloudfoundry.ServiceInstance
+		result2 chan error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteServiceInstance(serviceInstanceGuid string, recursive bool) (bool, error) {
 	fake.deleteServiceInstanceMutex.Lock()
 	ret, specificReturn := fake.deleteServiceInstanceReturnsOnCall[len(fake.deleteServiceInstanceArgsForCall)]
 	fake.deleteServiceInstanceArgsForCall = append(fake.deleteServiceInstanceArgsForCall, struct {
@@ -229,9 +711,9 @@ func (fake *FakeClient) DeleteServiceInstance(serviceInstanceGuid string, recurs
 		return fake.DeleteServiceInstanceStub(serviceInstanceGuid, recursive)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fake.deleteServiceInstanceReturns.result1
+	return fake.deleteServiceInstanceReturns.result1, fake.deleteServiceInstanceReturns.result2
 }
 
 func (fake *FakeClient) DeleteServiceInstanceCallCount() int {
@@ -246,36 +728,1555 @@ func (fake *FakeClient) DeleteServiceInstanceArgsForCall(i int) (string, bool) {
 	return fake.deleteServiceInstanceArgsForCall[i].serviceInstanceGuid, fake.deleteServiceInstanceArgsForCall[i].recursive
 }
 
-func (fake *FakeClient) DeleteServiceInstanceReturns(result1 error) {
+func (fake *FakeClient) DeleteServiceInstanceReturns(result1 bool, result2 error) {
 	fake.DeleteServiceInstanceStub = nil
 	fake.deleteServiceInstanceReturns = struct {
-		result1 error
-	}{result1}
+		result1 bool
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeClient) DeleteServiceInstanceReturnsOnCall(i int, result1 error) {
+func (fake *FakeClient) DeleteServiceInstanceReturnsOnCall(i int, result1 bool, result2 error) {
 	fake.DeleteServiceInstanceStub = nil
 	if fake.deleteServiceInstanceReturnsOnCall == nil {
 		fake.deleteServiceInstanceReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 bool
+			result2 error
 		})
 	}
 	fake.deleteServiceInstanceReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteUserProvidedServiceInstance(serviceInstanceGuid string) error {
+	fake.deleteUserProvidedServiceInstanceMutex.Lock()
+	ret, specificReturn := fake.deleteUserProvidedServiceInstanceReturnsOnCall[len(fake.deleteUserProvidedServiceInstanceArgsForCall)]
+	fake.deleteUserProvidedServiceInstanceArgsForCall = append(fake.deleteUserProvidedServiceInstanceArgsForCall, struct {
+		serviceInstanceGuid string
+	}{serviceInstanceGuid})
+	fake.recordInvocation("DeleteUserProvidedServiceInstance", []interface{}{serviceInstanceGuid})
+	fake.deleteUserProvidedServiceInstanceMutex.Unlock()
+	if fake.DeleteUserProvidedServiceInstanceStub != nil {
+		return fake.DeleteUserProvidedServiceInstanceStub(serviceInstanceGuid)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.deleteUserProvidedServiceInstanceReturns.result1
+}
+
+func (fake *FakeClient) DeleteUserProvidedServiceInstanceCallCount() int {
+	fake.deleteUserProvidedServiceInstanceMutex.RLock()
+	defer fake.deleteUserProvidedServiceInstanceMutex.RUnlock()
+	return len(fake.deleteUserProvidedServiceInstanceArgsForCall)
+}
+
+func (fake *FakeClient) DeleteUserProvidedServiceInstanceArgsForCall(i int) string {
+	fake.deleteUserProvidedServiceInstanceMutex.RLock()
+	defer fake.deleteUserProvidedServiceInstanceMutex.RUnlock()
+	return fake.deleteUserProvidedServiceInstanceArgsForCall[i].serviceInstanceGuid
+}
+
+func (fake *FakeClient) DeleteUserProvidedServiceInstanceReturns(result1 error) {
+	fake.DeleteUserProvidedServiceInstanceStub = nil
+	fake.deleteUserProvidedServiceInstanceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteUserProvidedServiceInstanceReturnsOnCall(i int, result1 error) {
+	fake.DeleteUserProvidedServiceInstanceStub = nil
+	if fake.deleteUserProvidedServiceInstanceReturnsOnCall == nil {
+		fake.deleteUserProvidedServiceInstanceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteUserProvidedServiceInstanceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) PurgeServiceInstance(serviceInstanceGuid string) error {
+	fake.purgeServiceInstanceMutex.Lock()
+	ret, specificReturn := fake.purgeServiceInstanceReturnsOnCall[len(fake.purgeServiceInstanceArgsForCall)]
+	fake.purgeServiceInstanceArgsForCall = append(fake.purgeServiceInstanceArgsForCall, struct {
+		serviceInstanceGuid string
+	}{serviceInstanceGuid})
+	fake.recordInvocation("PurgeServiceInstance", []interface{}{serviceInstanceGuid})
+	fake.purgeServiceInstanceMutex.Unlock()
+	if fake.PurgeServiceInstanceStub != nil {
+		return fake.PurgeServiceInstanceStub(serviceInstanceGuid)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.purgeServiceInstanceReturns.result1
+}
+
+func (fake *FakeClient) PurgeServiceInstanceCallCount() int {
+	fake.purgeServiceInstanceMutex.RLock()
+	defer fake.purgeServiceInstanceMutex.RUnlock()
+	return len(fake.purgeServiceInstanceArgsForCall)
+}
+
+func (fake *FakeClient) PurgeServiceInstanceArgsForCall(i int) string {
+	fake.purgeServiceInstanceMutex.RLock()
+	defer fake.purgeServiceInstanceMutex.RUnlock()
+	return fake.purgeServiceInstanceArgsForCall[i].serviceInstanceGuid
+}
+
+func (fake *FakeClient) PurgeServiceInstanceReturns(result1 error) {
+	fake.PurgeServiceInstanceStub = nil
+	fake.purgeServiceInstanceReturns = struct {
 		result1 error
 	}{result1}
 }
 
+func (fake *FakeClient) PurgeServiceInstanceReturnsOnCall(i int, result1 error) {
+	fake.PurgeServiceInstanceStub = nil
+	if fake.purgeServiceInstanceReturnsOnCall == nil {
+		fake.purgeServiceInstanceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.purgeServiceInstanceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) GetServiceInstanceParameters(serviceInstanceGuid string) (map[string]interface{}, error) {
+	fake.getServiceInstanceParametersMutex.Lock()
+	ret, specificReturn := fake.getServiceInstanceParametersReturnsOnCall[len(fake.getServiceInstanceParametersArgsForCall)]
+	fake.getServiceInstanceParametersArgsForCall = append(fake.getServiceInstanceParametersArgsForCall, struct {
+		serviceInstanceGuid string
+	}{serviceInstanceGuid})
+	fake.recordInvocation("GetServiceInstanceParameters", []interface{}{serviceInstanceGuid})
+	fake.getServiceInstanceParametersMutex.Unlock()
+	if fake.GetServiceInstanceParametersStub != nil {
+		return fake.GetServiceInstanceParametersStub(serviceInstanceGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getServiceInstanceParametersReturns.result1, fake.getServiceInstanceParametersReturns.result2
+}
+
+func (fake *FakeClient) GetServiceInstanceParametersCallCount() int {
+	fake.getServiceInstanceParametersMutex.RLock()
+	defer fake.getServiceInstanceParametersMutex.RUnlock()
+	return len(fake.getServiceInstanceParametersArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceInstanceParametersArgsForCall(i int) string {
+	fake.getServiceInstanceParametersMutex.RLock()
+	defer fake.getServiceInstanceParametersMutex.RUnlock()
+	return fake.getServiceInstanceParametersArgsForCall[i].serviceInstanceGuid
+}
+
+func (fake *FakeClient) GetServiceInstanceParametersReturns(result1 map[string]interface{}, result2 error) {
+	fake.GetServiceInstanceParametersStub = nil
+	fake.getServiceInstanceParametersReturns = struct {
+		result1 map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceInstanceParametersReturnsOnCall(i int, result1 map[string]interface{}, result2 error) {
+	fake.GetServiceInstanceParametersStub = nil
+	if fake.getServiceInstanceParametersReturnsOnCall == nil {
+		fake.getServiceInstanceParametersReturnsOnCall = make(map[int]struct {
+			result1 map[string]interface{}
+			result2 error
+		})
+	}
+	fake.getServiceInstanceParametersReturnsOnCall[i] = struct {
+		result1 map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceInstanceLabels(serviceInstanceGuid string) (map[string]string, error) {
+	fake.getServiceInstanceLabelsMutex.Lock()
+	ret, specificReturn := fake.getServiceInstanceLabelsReturnsOnCall[len(fake.getServiceInstanceLabelsArgsForCall)]
+	fake.getServiceInstanceLabelsArgsForCall = append(fake.getServiceInstanceLabelsArgsForCall, struct {
+		serviceInstanceGuid string
+	}{serviceInstanceGuid})
+	fake.recordInvocation("GetServiceInstanceLabels", []interface{}{serviceInstanceGuid})
+	fake.getServiceInstanceLabelsMutex.Unlock()
+	if fake.GetServiceInstanceLabelsStub != nil {
+		return fake.GetServiceInstanceLabelsStub(serviceInstanceGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getServiceInstanceLabelsReturns.result1, fake.getServiceInstanceLabelsReturns.result2
+}
+
+func (fake *FakeClient) GetServiceInstanceLabelsCallCount() int {
+	fake.getServiceInstanceLabelsMutex.RLock()
+	defer fake.getServiceInstanceLabelsMutex.RUnlock()
+	return len(fake.getServiceInstanceLabelsArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceInstanceLabelsArgsForCall(i int) string {
+	fake.getServiceInstanceLabelsMutex.RLock()
+	defer fake.getServiceInstanceLabelsMutex.RUnlock()
+	return fake.getServiceInstanceLabelsArgsForCall[i].serviceInstanceGuid
+}
+
+func (fake *FakeClient) GetServiceInstanceLabelsReturns(result1 map[string]string, result2 error) {
+	fake.GetServiceInstanceLabelsStub = nil
+	fake.getServiceInstanceLabelsReturns = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceInstanceLabelsReturnsOnCall(i int, result1 map[string]string, result2 error) {
+	fake.GetServiceInstanceLabelsStub = nil
+	if fake.getServiceInstanceLabelsReturnsOnCall == nil {
+		fake.getServiceInstanceLabelsReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+			result2 error
+		})
+	}
+	fake.getServiceInstanceLabelsReturnsOnCall[i] = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceBindings(serviceInstanceGuid string) ([]cloudfoundry.ServiceBinding, error) {
+	fake.getServiceBindingsMutex.Lock()
+	ret, specificReturn := fake.getServiceBindingsReturnsOnCall[len(fake.getServiceBindingsArgsForCall)]
+	fake.getServiceBindingsArgsForCall = append(fake.getServiceBindingsArgsForCall, struct {
+		serviceInstanceGuid string
+	}{serviceInstanceGuid})
+	fake.recordInvocation("GetServiceBindings", []interface{}{serviceInstanceGuid})
+	fake.getServiceBindingsMutex.Unlock()
+	if fake.GetServiceBindingsStub != nil {
+		return fake.GetServiceBindingsStub(serviceInstanceGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getServiceBindingsReturns.result1, fake.getServiceBindingsReturns.result2
+}
+
+func (fake *FakeClient) GetServiceBindingsCallCount() int {
+	fake.getServiceBindingsMutex.RLock()
+	defer fake.getServiceBindingsMutex.RUnlock()
+	return len(fake.getServiceBindingsArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceBindingsArgsForCall(i int) string {
+	fake.getServiceBindingsMutex.RLock()
+	defer fake.getServiceBindingsMutex.RUnlock()
+	return fake.getServiceBindingsArgsForCall[i].serviceInstanceGuid
+}
+
+func (fake *FakeClient) GetServiceBindingsReturns(result1 []cloudfoundry.ServiceBinding, result2 error) {
+	fake.GetServiceBindingsStub = nil
+	fake.getServiceBindingsReturns = struct {
+		result1 []cloudfoundry.ServiceBinding
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceBindingsReturnsOnCall(i int, result1 []cloudfoundry.ServiceBinding, result2 error) {
+	fake.GetServiceBindingsStub = nil
+	if fake.getServiceBindingsReturnsOnCall == nil {
+		fake.getServiceBindingsReturnsOnCall = make(map[int]struct {
+			result1 []cloudfoundry.ServiceBinding
+			result2 error
+		})
+	}
+	fake.getServiceBindingsReturnsOnCall[i] = struct {
+		result1 []cloudfoundry.ServiceBinding
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceKeys(serviceInstanceGuid string) ([]cloudfoundry.ServiceKey, error) {
+	fake.getServiceKeysMutex.Lock()
+	ret, specificReturn := fake.getServiceKeysReturnsOnCall[len(fake.getServiceKeysArgsForCall)]
+	fake.getServiceKeysArgsForCall = append(fake.getServiceKeysArgsForCall, struct {
+		serviceInstanceGuid string
+	}{serviceInstanceGuid})
+	fake.recordInvocation("GetServiceKeys", []interface{}{serviceInstanceGuid})
+	fake.getServiceKeysMutex.Unlock()
+	if fake.GetServiceKeysStub != nil {
+		return fake.GetServiceKeysStub(serviceInstanceGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getServiceKeysReturns.result1, fake.getServiceKeysReturns.result2
+}
+
+func (fake *FakeClient) GetServiceKeysCallCount() int {
+	fake.getServiceKeysMutex.RLock()
+	defer fake.getServiceKeysMutex.RUnlock()
+	return len(fake.getServiceKeysArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceKeysArgsForCall(i int) string {
+	fake.getServiceKeysMutex.RLock()
+	defer fake.getServiceKeysMutex.RUnlock()
+	return fake.getServiceKeysArgsForCall[i].serviceInstanceGuid
+}
+
+func (fake *FakeClient) GetServiceKeysReturns(result1 []cloudfoundry.ServiceKey, result2 error) {
+	fake.GetServiceKeysStub = nil
+	fake.getServiceKeysReturns = struct {
+		result1 []cloudfoundry.ServiceKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceKeysReturnsOnCall(i int, result1 []cloudfoundry.ServiceKey, result2 error) {
+	fake.GetServiceKeysStub = nil
+	if fake.getServiceKeysReturnsOnCall == nil {
+		fake.getServiceKeysReturnsOnCall = make(map[int]struct {
+			result1 []cloudfoundry.ServiceKey
+			result2 error
+		})
+	}
+	fake.getServiceKeysReturnsOnCall[i] = struct {
+		result1 []cloudfoundry.ServiceKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteServiceKey(serviceKeyGuid string) error {
+	fake.deleteServiceKeyMutex.Lock()
+	ret, specificReturn := fake.deleteServiceKeyReturnsOnCall[len(fake.deleteServiceKeyArgsForCall)]
+	fake.deleteServiceKeyArgsForCall = append(fake.deleteServiceKeyArgsForCall, struct {
+		serviceKeyGuid string
+	}{serviceKeyGuid})
+	fake.recordInvocation("DeleteServiceKey", []interface{}{serviceKeyGuid})
+	fake.deleteServiceKeyMutex.Unlock()
+	if fake.DeleteServiceKeyStub != nil {
+		return fake.DeleteServiceKeyStub(serviceKeyGuid)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.deleteServiceKeyReturns.result1
+}
+
+func (fake *FakeClient) DeleteServiceKeyCallCount() int {
+	fake.deleteServiceKeyMutex.RLock()
+	defer fake.deleteServiceKeyMutex.RUnlock()
+	return len(fake.deleteServiceKeyArgsForCall)
+}
+
+func (fake *FakeClient) DeleteServiceKeyArgsForCall(i int) string {
+	fake.deleteServiceKeyMutex.RLock()
+	defer fake.deleteServiceKeyMutex.RUnlock()
+	return fake.deleteServiceKeyArgsForCall[i].serviceKeyGuid
+}
+
+func (fake *FakeClient) DeleteServiceKeyReturns(result1 error) {
+	fake.DeleteServiceKeyStub = nil
+	fake.deleteServiceKeyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteServiceKeyReturnsOnCall(i int, result1 error) {
+	fake.DeleteServiceKeyStub = nil
+	if fake.deleteServiceKeyReturnsOnCall == nil {
+		fake.deleteServiceKeyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteServiceKeyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteServiceBinding(serviceBindingGuid string) (bool, error) {
+	fake.deleteServiceBindingMutex.Lock()
+	ret, specificReturn := fake.deleteServiceBindingReturnsOnCall[len(fake.deleteServiceBindingArgsForCall)]
+	fake.deleteServiceBindingArgsForCall = append(fake.deleteServiceBindingArgsForCall, struct {
+		serviceBindingGuid string
+	}{serviceBindingGuid})
+	fake.recordInvocation("DeleteServiceBinding", []interface{}{serviceBindingGuid})
+	fake.deleteServiceBindingMutex.Unlock()
+	if fake.DeleteServiceBindingStub != nil {
+		return fake.DeleteServiceBindingStub(serviceBindingGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.deleteServiceBindingReturns.result1, fake.deleteServiceBindingReturns.result2
+}
+
+func (fake *FakeClient) DeleteServiceBindingCallCount() int {
+	fake.deleteServiceBindingMutex.RLock()
+	defer fake.deleteServiceBindingMutex.RUnlock()
+	return len(fake.deleteServiceBindingArgsForCall)
+}
+
+func (fake *FakeClient) DeleteServiceBindingArgsForCall(i int) string {
+	fake.deleteServiceBindingMutex.RLock()
+	defer fake.deleteServiceBindingMutex.RUnlock()
+	return fake.deleteServiceBindingArgsForCall[i].serviceBindingGuid
+}
+
+func (fake *FakeClient) DeleteServiceBindingReturns(result1 bool, result2 error) {
+	fake.DeleteServiceBindingStub = nil
+	fake.deleteServiceBindingReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteServiceBindingReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.DeleteServiceBindingStub = nil
+	if fake.deleteServiceBindingReturnsOnCall == nil {
+		fake.deleteServiceBindingReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.deleteServiceBindingReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceBinding(serviceBindingGuid string) (cloudfoundry.ServiceBinding, bool, error) {
+	fake.getServiceBindingMutex.Lock()
+	ret, specificReturn := fake.getServiceBindingReturnsOnCall[len(fake.getServiceBindingArgsForCall)]
+	fake.getServiceBindingArgsForCall = append(fake.getServiceBindingArgsForCall, struct {
+		serviceBindingGuid string
+	}{serviceBindingGuid})
+	fake.recordInvocation("GetServiceBinding", []interface{}{serviceBindingGuid})
+	fake.getServiceBindingMutex.Unlock()
+	if fake.GetServiceBindingStub != nil {
+		return fake.GetServiceBindingStub(serviceBindingGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.getServiceBindingReturns.result1, fake.getServiceBindingReturns.result2, fake.getServiceBindingReturns.result3
+}
+
+func (fake *FakeClient) GetServiceBindingCallCount() int {
+	fake.getServiceBindingMutex.RLock()
+	defer fake.getServiceBindingMutex.RUnlock()
+	return len(fake.getServiceBindingArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceBindingArgsForCall(i int) string {
+	fake.getServiceBindingMutex.RLock()
+	defer fake.getServiceBindingMutex.RUnlock()
+	return fake.getServiceBindingArgsForCall[i].serviceBindingGuid
+}
+
+func (fake *FakeClient) GetServiceBindingReturns(result1 cloudfoundry.ServiceBinding, result2 bool, result3 error) {
+	fake.GetServiceBindingStub = nil
+	fake.getServiceBindingReturns = struct {
+		result1 cloudfoundry.ServiceBinding
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeClient) GetServiceBindingReturnsOnCall(i int, result1 cloudfoundry.ServiceBinding, result2 bool, result3 error) {
+	fake.GetServiceBindingStub = nil
+	if fake.getServiceBindingReturnsOnCall == nil {
+		fake.getServiceBindingReturnsOnCall = make(map[int]struct {
+			result1 cloudfoundry.ServiceBinding
+			result2 bool
+			result3 error
+		})
+	}
+	fake.getServiceBindingReturnsOnCall[i] = struct {
+		result1 cloudfoundry.ServiceBinding
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeClient) GetServiceInstance(serviceInstanceGuid string) (cloudfoundry.ServiceInstance, bool, error) {
+	fake.getServiceInstanceMutex.Lock()
+	ret, specificReturn := fake.getServiceInstanceReturnsOnCall[len(fake.getServiceInstanceArgsForCall)]
+	fake.getServiceInstanceArgsForCall = append(fake.getServiceInstanceArgsForCall, struct {
+		serviceInstanceGuid string
+	}{serviceInstanceGuid})
+	fake.recordInvocation("GetServiceInstance", []interface{}{serviceInstanceGuid})
+	fake.getServiceInstanceMutex.Unlock()
+	if fake.GetServiceInstanceStub != nil {
+		return fake.GetServiceInstanceStub(serviceInstanceGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.getServiceInstanceReturns.result1, fake.getServiceInstanceReturns.result2, fake.getServiceInstanceReturns.result3
+}
+
+func (fake *FakeClient) GetServiceInstanceCallCount() int {
+	fake.getServiceInstanceMutex.RLock()
+	defer fake.getServiceInstanceMutex.RUnlock()
+	return len(fake.getServiceInstanceArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceInstanceArgsForCall(i int) string {
+	fake.getServiceInstanceMutex.RLock()
+	defer fake.getServiceInstanceMutex.RUnlock()
+	return fake.getServiceInstanceArgsForCall[i].serviceInstanceGuid
+}
+
+func (fake *FakeClient) GetServiceInstanceReturns(result1 cloudfoundry.ServiceInstance, result2 bool, result3 error) {
+	fake.GetServiceInstanceStub = nil
+	fake.getServiceInstanceReturns = struct {
+		result1 cloudfoundry.ServiceInstance
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeClient) GetServiceInstanceReturnsOnCall(i int, result1 cloudfoundry.ServiceInstance, result2 bool, result3 error) {
+	fake.GetServiceInstanceStub = nil
+	if fake.getServiceInstanceReturnsOnCall == nil {
+		fake.getServiceInstanceReturnsOnCall = make(map[int]struct {
+			result1 cloudfoundry.ServiceInstance
+			result2 bool
+			result3 error
+		})
+	}
+	fake.getServiceInstanceReturnsOnCall[i] = struct {
+		result1 cloudfoundry.ServiceInstance
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeClient) GetUserProvidedServiceInstance(serviceInstanceGuid string) (cloudfoundry.ServiceInstance, bool, error) {
+	fake.getUserProvidedServiceInstanceMutex.Lock()
+	ret, specificReturn := fake.getUserProvidedServiceInstanceReturnsOnCall[len(fake.getUserProvidedServiceInstanceArgsForCall)]
+	fake.getUserProvidedServiceInstanceArgsForCall = append(fake.getUserProvidedServiceInstanceArgsForCall, struct {
+		serviceInstanceGuid string
+	}{serviceInstanceGuid})
+	fake.recordInvocation("GetUserProvidedServiceInstance", []interface{}{serviceInstanceGuid})
+	fake.getUserProvidedServiceInstanceMutex.Unlock()
+	if fake.GetUserProvidedServiceInstanceStub != nil {
+		return fake.GetUserProvidedServiceInstanceStub(serviceInstanceGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.getUserProvidedServiceInstanceReturns.result1, fake.getUserProvidedServiceInstanceReturns.result2, fake.getUserProvidedServiceInstanceReturns.result3
+}
+
+func (fake *FakeClient) GetUserProvidedServiceInstanceCallCount() int {
+	fake.getUserProvidedServiceInstanceMutex.RLock()
+	defer fake.getUserProvidedServiceInstanceMutex.RUnlock()
+	return len(fake.getUserProvidedServiceInstanceArgsForCall)
+}
+
+func (fake *FakeClient) GetUserProvidedServiceInstanceArgsForCall(i int) string {
+	fake.getUserProvidedServiceInstanceMutex.RLock()
+	defer fake.getUserProvidedServiceInstanceMutex.RUnlock()
+	return fake.getUserProvidedServiceInstanceArgsForCall[i].serviceInstanceGuid
+}
+
+func (fake *FakeClient) GetUserProvidedServiceInstanceReturns(result1 cloudfoundry.ServiceInstance, result2 bool, result3 error) {
+	fake.GetUserProvidedServiceInstanceStub = nil
+	fake.getUserProvidedServiceInstanceReturns = struct {
+		result1 cloudfoundry.ServiceInstance
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeClient) GetUserProvidedServiceInstanceReturnsOnCall(i int, result1 cloudfoundry.ServiceInstance, result2 bool, result3 error) {
+	fake.GetUserProvidedServiceInstanceStub = nil
+	if fake.getUserProvidedServiceInstanceReturnsOnCall == nil {
+		fake.getUserProvidedServiceInstanceReturnsOnCall = make(map[int]struct {
+			result1 cloudfoundry.ServiceInstance
+			result2 bool
+			result3 error
+		})
+	}
+	fake.getUserProvidedServiceInstanceReturnsOnCall[i] = struct {
+		result1 cloudfoundry.ServiceInstance
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeClient) GetServiceInstanceRoutes(serviceInstanceGuid string) ([]cloudfoundry.Route, error) {
+	fake.getServiceInstanceRoutesMutex.Lock()
+	ret, specificReturn := fake.getServiceInstanceRoutesReturnsOnCall[len(fake.getServiceInstanceRoutesArgsForCall)]
+	fake.getServiceInstanceRoutesArgsForCall = append(fake.getServiceInstanceRoutesArgsForCall, struct {
+		serviceInstanceGuid string
+	}{serviceInstanceGuid})
+	fake.recordInvocation("GetServiceInstanceRoutes", []interface{}{serviceInstanceGuid})
+	fake.getServiceInstanceRoutesMutex.Unlock()
+	if fake.GetServiceInstanceRoutesStub != nil {
+		return fake.GetServiceInstanceRoutesStub(serviceInstanceGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getServiceInstanceRoutesReturns.result1, fake.getServiceInstanceRoutesReturns.result2
+}
+
+func (fake *FakeClient) GetServiceInstanceRoutesCallCount() int {
+	fake.getServiceInstanceRoutesMutex.RLock()
+	defer fake.getServiceInstanceRoutesMutex.RUnlock()
+	return len(fake.getServiceInstanceRoutesArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceInstanceRoutesArgsForCall(i int) string {
+	fake.getServiceInstanceRoutesMutex.RLock()
+	defer fake.getServiceInstanceRoutesMutex.RUnlock()
+	return fake.getServiceInstanceRoutesArgsForCall[i].serviceInstanceGuid
+}
+
+func (fake *FakeClient) GetServiceInstanceRoutesReturns(result1 []cloudfoundry.Route, result2 error) {
+	fake.GetServiceInstanceRoutesStub = nil
+	fake.getServiceInstanceRoutesReturns = struct {
+		result1 []cloudfoundry.Route
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceInstanceRoutesReturnsOnCall(i int, result1 []cloudfoundry.Route, result2 error) {
+	fake.GetServiceInstanceRoutesStub = nil
+	if fake.getServiceInstanceRoutesReturnsOnCall == nil {
+		fake.getServiceInstanceRoutesReturnsOnCall = make(map[int]struct {
+			result1 []cloudfoundry.Route
+			result2 error
+		})
+	}
+	fake.getServiceInstanceRoutesReturnsOnCall[i] = struct {
+		result1 []cloudfoundry.Route
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) UnbindRouteService(serviceInstanceGuid string, routeGuid string) error {
+	fake.unbindRouteServiceMutex.Lock()
+	ret, specificReturn := fake.unbindRouteServiceReturnsOnCall[len(fake.unbindRouteServiceArgsForCall)]
+	fake.unbindRouteServiceArgsForCall = append(fake.unbindRouteServiceArgsForCall, struct {
+		serviceInstanceGuid string
+		routeGuid           string
+	}{serviceInstanceGuid, routeGuid})
+	fake.recordInvocation("UnbindRouteService", []interface{}{serviceInstanceGuid, routeGuid})
+	fake.unbindRouteServiceMutex.Unlock()
+	if fake.UnbindRouteServiceStub != nil {
+		return fake.UnbindRouteServiceStub(serviceInstanceGuid, routeGuid)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.unbindRouteServiceReturns.result1
+}
+
+func (fake *FakeClient) UnbindRouteServiceCallCount() int {
+	fake.unbindRouteServiceMutex.RLock()
+	defer fake.unbindRouteServiceMutex.RUnlock()
+	return len(fake.unbindRouteServiceArgsForCall)
+}
+
+func (fake *FakeClient) UnbindRouteServiceArgsForCall(i int) (string, string) {
+	fake.unbindRouteServiceMutex.RLock()
+	defer fake.unbindRouteServiceMutex.RUnlock()
+	return fake.unbindRouteServiceArgsForCall[i].serviceInstanceGuid, fake.unbindRouteServiceArgsForCall[i].routeGuid
+}
+
+func (fake *FakeClient) UnbindRouteServiceReturns(result1 error) {
+	fake.UnbindRouteServiceStub = nil
+	fake.unbindRouteServiceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) UnbindRouteServiceReturnsOnCall(i int, result1 error) {
+	fake.UnbindRouteServiceStub = nil
+	if fake.unbindRouteServiceReturnsOnCall == nil {
+		fake.unbindRouteServiceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.unbindRouteServiceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateServiceInstance(request cloudfoundry.CreateServiceInstanceRequest) (cloudfoundry.ServiceInstance, error) {
+	fake.createServiceInstanceMutex.Lock()
+	ret, specificReturn := fake.createServiceInstanceReturnsOnCall[len(fake.createServiceInstanceArgsForCall)]
+	fake.createServiceInstanceArgsForCall = append(fake.createServiceInstanceArgsForCall, struct {
+		request cloudfoundry.CreateServiceInstanceRequest
+	}{request})
+	fake.recordInvocation("CreateServiceInstance", []interface{}{request})
+	fake.createServiceInstanceMutex.Unlock()
+	if fake.CreateServiceInstanceStub != nil {
+		return fake.CreateServiceInstanceStub(request)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.createServiceInstanceReturns.result1, fake.createServiceInstanceReturns.result2
+}
+
+func (fake *FakeClient) CreateServiceInstanceCallCount() int {
+	fake.createServiceInstanceMutex.RLock()
+	defer fake.createServiceInstanceMutex.RUnlock()
+	return len(fake.createServiceInstanceArgsForCall)
+}
+
+func (fake *FakeClient) CreateServiceInstanceArgsForCall(i int) cloudfoundry.CreateServiceInstanceRequest {
+	fake.createServiceInstanceMutex.RLock()
+	defer fake.createServiceInstanceMutex.RUnlock()
+	return fake.createServiceInstanceArgsForCall[i].request
+}
+
+func (fake *FakeClient) CreateServiceInstanceReturns(result1 cloudfoundry.ServiceInstance, result2 error) {
+	fake.CreateServiceInstanceStub = nil
+	fake.createServiceInstanceReturns = struct {
+		result1 cloudfoundry.ServiceInstance
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CreateServiceInstanceReturnsOnCall(i int, result1 cloudfoundry.ServiceInstance, result2 error) {
+	fake.CreateServiceInstanceStub = nil
+	if fake.createServiceInstanceReturnsOnCall == nil {
+		fake.createServiceInstanceReturnsOnCall = make(map[int]struct {
+			result1 cloudfoundry.ServiceInstance
+			result2 error
+		})
+	}
+	fake.createServiceInstanceReturnsOnCall[i] = struct {
+		result1 cloudfoundry.ServiceInstance
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceBindingDeleteEvents(spaceGuid string) ([]cloudfoundry.Event, error) {
+	fake.getServiceBindingDeleteEventsMutex.Lock()
+	ret, specificReturn := fake.getServiceBindingDeleteEventsReturnsOnCall[len(fake.getServiceBindingDeleteEventsArgsForCall)]
+	fake.getServiceBindingDeleteEventsArgsForCall = append(fake.getServiceBindingDeleteEventsArgsForCall, struct {
+		spaceGuid string
+	}{spaceGuid})
+	fake.recordInvocation("GetServiceBindingDeleteEvents", []interface{}{spaceGuid})
+	fake.getServiceBindingDeleteEventsMutex.Unlock()
+	if fake.GetServiceBindingDeleteEventsStub != nil {
+		return fake.GetServiceBindingDeleteEventsStub(spaceGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getServiceBindingDeleteEventsReturns.result1, fake.getServiceBindingDeleteEventsReturns.result2
+}
+
+func (fake *FakeClient) GetServiceBindingDeleteEventsCallCount() int {
+	fake.getServiceBindingDeleteEventsMutex.RLock()
+	defer fake.getServiceBindingDeleteEventsMutex.RUnlock()
+	return len(fake.getServiceBindingDeleteEventsArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceBindingDeleteEventsArgsForCall(i int) string {
+	fake.getServiceBindingDeleteEventsMutex.RLock()
+	defer fake.getServiceBindingDeleteEventsMutex.RUnlock()
+	return fake.getServiceBindingDeleteEventsArgsForCall[i].spaceGuid
+}
+
+func (fake *FakeClient) GetServiceBindingDeleteEventsReturns(result1 []cloudfoundry.Event, result2 error) {
+	fake.GetServiceBindingDeleteEventsStub = nil
+	fake.getServiceBindingDeleteEventsReturns = struct {
+		result1 []cloudfoundry.Event
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceBindingDeleteEventsReturnsOnCall(i int, result1 []cloudfoundry.Event, result2 error) {
+	fake.GetServiceBindingDeleteEventsStub = nil
+	if fake.getServiceBindingDeleteEventsReturnsOnCall == nil {
+		fake.getServiceBindingDeleteEventsReturnsOnCall = make(map[int]struct {
+			result1 []cloudfoundry.Event
+			result2 error
+		})
+	}
+	fake.getServiceBindingDeleteEventsReturnsOnCall[i] = struct {
+		result1 []cloudfoundry.Event
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetApps() (chan cloudfoundry.App, chan error) {
+	fake.getAppsMutex.Lock()
+	ret, specificReturn := fake.getAppsReturnsOnCall[len(fake.getAppsArgsForCall)]
+	fake.getAppsArgsForCall = append(fake.getAppsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetApps", []interface{}{})
+	fake.getAppsMutex.Unlock()
+	if fake.GetAppsStub != nil {
+		return fake.GetAppsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getAppsReturns.result1, fake.getAppsReturns.result2
+}
+
+func (fake *FakeClient) GetAppsCallCount() int {
+	fake.getAppsMutex.RLock()
+	defer fake.getAppsMutex.RUnlock()
+	return len(fake.getAppsArgsForCall)
+}
+
+func (fake *FakeClient) GetAppsReturns(result1 chan cloudfoundry.App, result2 chan error) {
+	fake.GetAppsStub = nil
+	fake.getAppsReturns = struct {
+		result1 chan cloudfoundry.App
+		result2 chan error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetAppsReturnsOnCall(i int, result1 chan cloudfoundry.App, result2 chan error) {
+	fake.GetAppsStub = nil
+	if fake.getAppsReturnsOnCall == nil {
+		fake.getAppsReturnsOnCall = make(map[int]struct {
+			result1 chan cloudfoundry.App
+			result2 chan error
+		})
+	}
+	fake.getAppsReturnsOnCall[i] = struct {
+		result1 chan cloudfoundry.App
+		result2 chan error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteApp(appGuid string) error {
+	fake.deleteAppMutex.Lock()
+	ret, specificReturn := fake.deleteAppReturnsOnCall[len(fake.deleteAppArgsForCall)]
+	fake.deleteAppArgsForCall = append(fake.deleteAppArgsForCall, struct {
+		appGuid string
+	}{appGuid})
+	fake.recordInvocation("DeleteApp", []interface{}{appGuid})
+	fake.deleteAppMutex.Unlock()
+	if fake.DeleteAppStub != nil {
+		return fake.DeleteAppStub(appGuid)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.deleteAppReturns.result1
+}
+
+func (fake *FakeClient) DeleteAppCallCount() int {
+	fake.deleteAppMutex.RLock()
+	defer fake.deleteAppMutex.RUnlock()
+	return len(fake.deleteAppArgsForCall)
+}
+
+func (fake *FakeClient) DeleteAppArgsForCall(i int) string {
+	fake.deleteAppMutex.RLock()
+	defer fake.deleteAppMutex.RUnlock()
+	return fake.deleteAppArgsForCall[i].appGuid
+}
+
+func (fake *FakeClient) DeleteAppReturns(result1 error) {
+	fake.DeleteAppStub = nil
+	fake.deleteAppReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteAppReturnsOnCall(i int, result1 error) {
+	fake.DeleteAppStub = nil
+	if fake.deleteAppReturnsOnCall == nil {
+		fake.deleteAppReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteAppReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) GetAppRoutes(appGuid string) ([]cloudfoundry.Route, error) {
+	fake.getAppRoutesMutex.Lock()
+	ret, specificReturn := fake.getAppRoutesReturnsOnCall[len(fake.getAppRoutesArgsForCall)]
+	fake.getAppRoutesArgsForCall = append(fake.getAppRoutesArgsForCall, struct {
+		appGuid string
+	}{appGuid})
+	fake.recordInvocation("GetAppRoutes", []interface{}{appGuid})
+	fake.getAppRoutesMutex.Unlock()
+	if fake.GetAppRoutesStub != nil {
+		return fake.GetAppRoutesStub(appGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getAppRoutesReturns.result1, fake.getAppRoutesReturns.result2
+}
+
+func (fake *FakeClient) GetAppRoutesCallCount() int {
+	fake.getAppRoutesMutex.RLock()
+	defer fake.getAppRoutesMutex.RUnlock()
+	return len(fake.getAppRoutesArgsForCall)
+}
+
+func (fake *FakeClient) GetAppRoutesArgsForCall(i int) string {
+	fake.getAppRoutesMutex.RLock()
+	defer fake.getAppRoutesMutex.RUnlock()
+	return fake.getAppRoutesArgsForCall[i].appGuid
+}
+
+func (fake *FakeClient) GetAppRoutesReturns(result1 []cloudfoundry.Route, result2 error) {
+	fake.GetAppRoutesStub = nil
+	fake.getAppRoutesReturns = struct {
+		result1 []cloudfoundry.Route
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetAppRoutesReturnsOnCall(i int, result1 []cloudfoundry.Route, result2 error) {
+	fake.GetAppRoutesStub = nil
+	if fake.getAppRoutesReturnsOnCall == nil {
+		fake.getAppRoutesReturnsOnCall = make(map[int]struct {
+			result1 []cloudfoundry.Route
+			result2 error
+		})
+	}
+	fake.getAppRoutesReturnsOnCall[i] = struct {
+		result1 []cloudfoundry.Route
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetRouteApps(routeGuid string) ([]cloudfoundry.App, error) {
+	fake.getRouteAppsMutex.Lock()
+	ret, specificReturn := fake.getRouteAppsReturnsOnCall[len(fake.getRouteAppsArgsForCall)]
+	fake.getRouteAppsArgsForCall = append(fake.getRouteAppsArgsForCall, struct {
+		routeGuid string
+	}{routeGuid})
+	fake.recordInvocation("GetRouteApps", []interface{}{routeGuid})
+	fake.getRouteAppsMutex.Unlock()
+	if fake.GetRouteAppsStub != nil {
+		return fake.GetRouteAppsStub(routeGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getRouteAppsReturns.result1, fake.getRouteAppsReturns.result2
+}
+
+func (fake *FakeClient) GetRouteAppsCallCount() int {
+	fake.getRouteAppsMutex.RLock()
+	defer fake.getRouteAppsMutex.RUnlock()
+	return len(fake.getRouteAppsArgsForCall)
+}
+
+func (fake *FakeClient) GetRouteAppsArgsForCall(i int) string {
+	fake.getRouteAppsMutex.RLock()
+	defer fake.getRouteAppsMutex.RUnlock()
+	return fake.getRouteAppsArgsForCall[i].routeGuid
+}
+
+func (fake *FakeClient) GetRouteAppsReturns(result1 []cloudfoundry.App, result2 error) {
+	fake.GetRouteAppsStub = nil
+	fake.getRouteAppsReturns = struct {
+		result1 []cloudfoundry.App
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetRouteAppsReturnsOnCall(i int, result1 []cloudfoundry.App, result2 error) {
+	fake.GetRouteAppsStub = nil
+	if fake.getRouteAppsReturnsOnCall == nil {
+		fake.getRouteAppsReturnsOnCall = make(map[int]struct {
+			result1 []cloudfoundry.App
+			result2 error
+		})
+	}
+	fake.getRouteAppsReturnsOnCall[i] = struct {
+		result1 []cloudfoundry.App
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteRoute(routeGuid string) error {
+	fake.deleteRouteMutex.Lock()
+	ret, specificReturn := fake.deleteRouteReturnsOnCall[len(fake.deleteRouteArgsForCall)]
+	fake.deleteRouteArgsForCall = append(fake.deleteRouteArgsForCall, struct {
+		routeGuid string
+	}{routeGuid})
+	fake.recordInvocation("DeleteRoute", []interface{}{routeGuid})
+	fake.deleteRouteMutex.Unlock()
+	if fake.DeleteRouteStub != nil {
+		return fake.DeleteRouteStub(routeGuid)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.deleteRouteReturns.result1
+}
+
+func (fake *FakeClient) DeleteRouteCallCount() int {
+	fake.deleteRouteMutex.RLock()
+	defer fake.deleteRouteMutex.RUnlock()
+	return len(fake.deleteRouteArgsForCall)
+}
+
+func (fake *FakeClient) DeleteRouteArgsForCall(i int) string {
+	fake.deleteRouteMutex.RLock()
+	defer fake.deleteRouteMutex.RUnlock()
+	return fake.deleteRouteArgsForCall[i].routeGuid
+}
+
+func (fake *FakeClient) DeleteRouteReturns(result1 error) {
+	fake.DeleteRouteStub = nil
+	fake.deleteRouteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteRouteReturnsOnCall(i int, result1 error) {
+	fake.DeleteRouteStub = nil
+	if fake.deleteRouteReturnsOnCall == nil {
+		fake.deleteRouteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteRouteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) GetSpaces() (chan cloudfoundry.Space, chan error) {
+	fake.getSpacesMutex.Lock()
+	ret, specificReturn := fake.getSpacesReturnsOnCall[len(fake.getSpacesArgsForCall)]
+	fake.getSpacesArgsForCall = append(fake.getSpacesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetSpaces", []interface{}{})
+	fake.getSpacesMutex.Unlock()
+	if fake.GetSpacesStub != nil {
+		return fake.GetSpacesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getSpacesReturns.result1, fake.getSpacesReturns.result2
+}
+
+func (fake *FakeClient) GetSpacesCallCount() int {
+	fake.getSpacesMutex.RLock()
+	defer fake.getSpacesMutex.RUnlock()
+	return len(fake.getSpacesArgsForCall)
+}
+
+func (fake *FakeClient) GetSpacesReturns(result1 chan cloudfoundry.Space, result2 chan error) {
+	fake.GetSpacesStub = nil
+	fake.getSpacesReturns = struct {
+		result1 chan cloudfoundry.Space
+		result2 chan error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetSpacesReturnsOnCall(i int, result1 chan cloudfoundry.Space, result2 chan error) {
+	fake.GetSpacesStub = nil
+	if fake.getSpacesReturnsOnCall == nil {
+		fake.getSpacesReturnsOnCall = make(map[int]struct {
+			result1 chan cloudfoundry.Space
+			result2 chan error
+		})
+	}
+	fake.getSpacesReturnsOnCall[i] = struct {
+		result1 chan cloudfoundry.Space
+		result2 chan error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetSpaceUsage(spaceGuid string) (cloudfoundry.SpaceUsage, error) {
+	fake.getSpaceUsageMutex.Lock()
+	ret, specificReturn := fake.getSpaceUsageReturnsOnCall[len(fake.getSpaceUsageArgsForCall)]
+	fake.getSpaceUsageArgsForCall = append(fake.getSpaceUsageArgsForCall, struct {
+		spaceGuid string
+	}{spaceGuid})
+	fake.recordInvocation("GetSpaceUsage", []interface{}{spaceGuid})
+	fake.getSpaceUsageMutex.Unlock()
+	if fake.GetSpaceUsageStub != nil {
+		return fake.GetSpaceUsageStub(spaceGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getSpaceUsageReturns.result1, fake.getSpaceUsageReturns.result2
+}
+
+func (fake *FakeClient) GetSpaceUsageCallCount() int {
+	fake.getSpaceUsageMutex.RLock()
+	defer fake.getSpaceUsageMutex.RUnlock()
+	return len(fake.getSpaceUsageArgsForCall)
+}
+
+func (fake *FakeClient) GetSpaceUsageArgsForCall(i int) string {
+	fake.getSpaceUsageMutex.RLock()
+	defer fake.getSpaceUsageMutex.RUnlock()
+	return fake.getSpaceUsageArgsForCall[i].spaceGuid
+}
+
+func (fake *FakeClient) GetSpaceUsageReturns(result1 cloudfoundry.SpaceUsage, result2 error) {
+	fake.GetSpaceUsageStub = nil
+	fake.getSpaceUsageReturns = struct {
+		result1 cloudfoundry.SpaceUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetSpaceUsageReturnsOnCall(i int, result1 cloudfoundry.SpaceUsage, result2 error) {
+	fake.GetSpaceUsageStub = nil
+	if fake.getSpaceUsageReturnsOnCall == nil {
+		fake.getSpaceUsageReturnsOnCall = make(map[int]struct {
+			result1 cloudfoundry.SpaceUsage
+			result2 error
+		})
+	}
+	fake.getSpaceUsageReturnsOnCall[i] = struct {
+		result1 cloudfoundry.SpaceUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteSpace(spaceGuid string) error {
+	fake.deleteSpaceMutex.Lock()
+	ret, specificReturn := fake.deleteSpaceReturnsOnCall[len(fake.deleteSpaceArgsForCall)]
+	fake.deleteSpaceArgsForCall = append(fake.deleteSpaceArgsForCall, struct {
+		spaceGuid string
+	}{spaceGuid})
+	fake.recordInvocation("DeleteSpace", []interface{}{spaceGuid})
+	fake.deleteSpaceMutex.Unlock()
+	if fake.DeleteSpaceStub != nil {
+		return fake.DeleteSpaceStub(spaceGuid)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.deleteSpaceReturns.result1
+}
+
+func (fake *FakeClient) DeleteSpaceCallCount() int {
+	fake.deleteSpaceMutex.RLock()
+	defer fake.deleteSpaceMutex.RUnlock()
+	return len(fake.deleteSpaceArgsForCall)
+}
+
+func (fake *FakeClient) DeleteSpaceArgsForCall(i int) string {
+	fake.deleteSpaceMutex.RLock()
+	defer fake.deleteSpaceMutex.RUnlock()
+	return fake.deleteSpaceArgsForCall[i].spaceGuid
+}
+
+func (fake *FakeClient) DeleteSpaceReturns(result1 error) {
+	fake.DeleteSpaceStub = nil
+	fake.deleteSpaceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteSpaceReturnsOnCall(i int, result1 error) {
+	fake.DeleteSpaceStub = nil
+	if fake.deleteSpaceReturnsOnCall == nil {
+		fake.deleteSpaceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteSpaceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) GetSpace(spaceGuid string) (cloudfoundry.Space, error) {
+	fake.getSpaceMutex.Lock()
+	ret, specificReturn := fake.getSpaceReturnsOnCall[len(fake.getSpaceArgsForCall)]
+	fake.getSpaceArgsForCall = append(fake.getSpaceArgsForCall, struct {
+		spaceGuid string
+	}{spaceGuid})
+	fake.recordInvocation("GetSpace", []interface{}{spaceGuid})
+	fake.getSpaceMutex.Unlock()
+	if fake.GetSpaceStub != nil {
+		return fake.GetSpaceStub(spaceGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getSpaceReturns.result1, fake.getSpaceReturns.result2
+}
+
+func (fake *FakeClient) GetSpaceCallCount() int {
+	fake.getSpaceMutex.RLock()
+	defer fake.getSpaceMutex.RUnlock()
+	return len(fake.getSpaceArgsForCall)
+}
+
+func (fake *FakeClient) GetSpaceArgsForCall(i int) string {
+	fake.getSpaceMutex.RLock()
+	defer fake.getSpaceMutex.RUnlock()
+	return fake.getSpaceArgsForCall[i].spaceGuid
+}
+
+func (fake *FakeClient) GetSpaceReturns(result1 cloudfoundry.Space, result2 error) {
+	fake.GetSpaceStub = nil
+	fake.getSpaceReturns = struct {
+		result1 cloudfoundry.Space
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetSpaceReturnsOnCall(i int, result1 cloudfoundry.Space, result2 error) {
+	fake.GetSpaceStub = nil
+	if fake.getSpaceReturnsOnCall == nil {
+		fake.getSpaceReturnsOnCall = make(map[int]struct {
+			result1 cloudfoundry.Space
+			result2 error
+		})
+	}
+	fake.getSpaceReturnsOnCall[i] = struct {
+		result1 cloudfoundry.Space
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetSpaceQuotaDefinition(spaceQuotaDefinitionGuid string) (cloudfoundry.QuotaDefinition, error) {
+	fake.getSpaceQuotaDefinitionMutex.Lock()
+	ret, specificReturn := fake.getSpaceQuotaDefinitionReturnsOnCall[len(fake.getSpaceQuotaDefinitionArgsForCall)]
+	fake.getSpaceQuotaDefinitionArgsForCall = append(fake.getSpaceQuotaDefinitionArgsForCall, struct {
+		spaceQuotaDefinitionGuid string
+	}{spaceQuotaDefinitionGuid})
+	fake.recordInvocation("GetSpaceQuotaDefinition", []interface{}{spaceQuotaDefinitionGuid})
+	fake.getSpaceQuotaDefinitionMutex.Unlock()
+	if fake.GetSpaceQuotaDefinitionStub != nil {
+		return fake.GetSpaceQuotaDefinitionStub(spaceQuotaDefinitionGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getSpaceQuotaDefinitionReturns.result1, fake.getSpaceQuotaDefinitionReturns.result2
+}
+
+func (fake *FakeClient) GetSpaceQuotaDefinitionCallCount() int {
+	fake.getSpaceQuotaDefinitionMutex.RLock()
+	defer fake.getSpaceQuotaDefinitionMutex.RUnlock()
+	return len(fake.getSpaceQuotaDefinitionArgsForCall)
+}
+
+func (fake *FakeClient) GetSpaceQuotaDefinitionArgsForCall(i int) string {
+	fake.getSpaceQuotaDefinitionMutex.RLock()
+	defer fake.getSpaceQuotaDefinitionMutex.RUnlock()
+	return fake.getSpaceQuotaDefinitionArgsForCall[i].spaceQuotaDefinitionGuid
+}
+
+func (fake *FakeClient) GetSpaceQuotaDefinitionReturns(result1 cloudfoundry.QuotaDefinition, result2 error) {
+	fake.GetSpaceQuotaDefinitionStub = nil
+	fake.getSpaceQuotaDefinitionReturns = struct {
+		result1 cloudfoundry.QuotaDefinition
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetSpaceQuotaDefinitionReturnsOnCall(i int, result1 cloudfoundry.QuotaDefinition, result2 error) {
+	fake.GetSpaceQuotaDefinitionStub = nil
+	if fake.getSpaceQuotaDefinitionReturnsOnCall == nil {
+		fake.getSpaceQuotaDefinitionReturnsOnCall = make(map[int]struct {
+			result1 cloudfoundry.QuotaDefinition
+			result2 error
+		})
+	}
+	fake.getSpaceQuotaDefinitionReturnsOnCall[i] = struct {
+		result1 cloudfoundry.QuotaDefinition
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetOrganization(organizationGuid string) (cloudfoundry.Organization, error) {
+	fake.getOrganizationMutex.Lock()
+	ret, specificReturn := fake.getOrganizationReturnsOnCall[len(fake.getOrganizationArgsForCall)]
+	fake.getOrganizationArgsForCall = append(fake.getOrganizationArgsForCall, struct {
+		organizationGuid string
+	}{organizationGuid})
+	fake.recordInvocation("GetOrganization", []interface{}{organizationGuid})
+	fake.getOrganizationMutex.Unlock()
+	if fake.GetOrganizationStub != nil {
+		return fake.GetOrganizationStub(organizationGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getOrganizationReturns.result1, fake.getOrganizationReturns.result2
+}
+
+func (fake *FakeClient) GetOrganizationCallCount() int {
+	fake.getOrganizationMutex.RLock()
+	defer fake.getOrganizationMutex.RUnlock()
+	return len(fake.getOrganizationArgsForCall)
+}
+
+func (fake *FakeClient) GetOrganizationArgsForCall(i int) string {
+	fake.getOrganizationMutex.RLock()
+	defer fake.getOrganizationMutex.RUnlock()
+	return fake.getOrganizationArgsForCall[i].organizationGuid
+}
+
+func (fake *FakeClient) GetOrganizationReturns(result1 cloudfoundry.Organization, result2 error) {
+	fake.GetOrganizationStub = nil
+	fake.getOrganizationReturns = struct {
+		result1 cloudfoundry.Organization
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetOrganizationReturnsOnCall(i int, result1 cloudfoundry.Organization, result2 error) {
+	fake.GetOrganizationStub = nil
+	if fake.getOrganizationReturnsOnCall == nil {
+		fake.getOrganizationReturnsOnCall = make(map[int]struct {
+			result1 cloudfoundry.Organization
+			result2 error
+		})
+	}
+	fake.getOrganizationReturnsOnCall[i] = struct {
+		result1 cloudfoundry.Organization
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetOrganizationQuotaDefinition(quotaDefinitionGuid string) (cloudfoundry.QuotaDefinition, error) {
+	fake.getOrganizationQuotaDefinitionMutex.Lock()
+	ret, specificReturn := fake.getOrganizationQuotaDefinitionReturnsOnCall[len(fake.getOrganizationQuotaDefinitionArgsForCall)]
+	fake.getOrganizationQuotaDefinitionArgsForCall = append(fake.getOrganizationQuotaDefinitionArgsForCall, struct {
+		quotaDefinitionGuid string
+	}{quotaDefinitionGuid})
+	fake.recordInvocation("GetOrganizationQuotaDefinition", []interface{}{quotaDefinitionGuid})
+	fake.getOrganizationQuotaDefinitionMutex.Unlock()
+	if fake.GetOrganizationQuotaDefinitionStub != nil {
+		return fake.GetOrganizationQuotaDefinitionStub(quotaDefinitionGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getOrganizationQuotaDefinitionReturns.result1, fake.getOrganizationQuotaDefinitionReturns.result2
+}
+
+func (fake *FakeClient) GetOrganizationQuotaDefinitionCallCount() int {
+	fake.getOrganizationQuotaDefinitionMutex.RLock()
+	defer fake.getOrganizationQuotaDefinitionMutex.RUnlock()
+	return len(fake.getOrganizationQuotaDefinitionArgsForCall)
+}
+
+func (fake *FakeClient) GetOrganizationQuotaDefinitionArgsForCall(i int) string {
+	fake.getOrganizationQuotaDefinitionMutex.RLock()
+	defer fake.getOrganizationQuotaDefinitionMutex.RUnlock()
+	return fake.getOrganizationQuotaDefinitionArgsForCall[i].quotaDefinitionGuid
+}
+
+func (fake *FakeClient) GetOrganizationQuotaDefinitionReturns(result1 cloudfoundry.QuotaDefinition, result2 error) {
+	fake.GetOrganizationQuotaDefinitionStub = nil
+	fake.getOrganizationQuotaDefinitionReturns = struct {
+		result1 cloudfoundry.QuotaDefinition
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetOrganizationQuotaDefinitionReturnsOnCall(i int, result1 cloudfoundry.QuotaDefinition, result2 error) {
+	fake.GetOrganizationQuotaDefinitionStub = nil
+	if fake.getOrganizationQuotaDefinitionReturnsOnCall == nil {
+		fake.getOrganizationQuotaDefinitionReturnsOnCall = make(map[int]struct {
+			result1 cloudfoundry.QuotaDefinition
+			result2 error
+		})
+	}
+	fake.getOrganizationQuotaDefinitionReturnsOnCall[i] = struct {
+		result1 cloudfoundry.QuotaDefinition
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CountSpaceServiceInstances(spaceGuid string) (int, error) {
+	fake.countSpaceServiceInstancesMutex.Lock()
+	ret, specificReturn := fake.countSpaceServiceInstancesReturnsOnCall[len(fake.countSpaceServiceInstancesArgsForCall)]
+	fake.countSpaceServiceInstancesArgsForCall = append(fake.countSpaceServiceInstancesArgsForCall, struct {
+		spaceGuid string
+	}{spaceGuid})
+	fake.recordInvocation("CountSpaceServiceInstances", []interface{}{spaceGuid})
+	fake.countSpaceServiceInstancesMutex.Unlock()
+	if fake.CountSpaceServiceInstancesStub != nil {
+		return fake.CountSpaceServiceInstancesStub(spaceGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.countSpaceServiceInstancesReturns.result1, fake.countSpaceServiceInstancesReturns.result2
+}
+
+func (fake *FakeClient) CountSpaceServiceInstancesCallCount() int {
+	fake.countSpaceServiceInstancesMutex.RLock()
+	defer fake.countSpaceServiceInstancesMutex.RUnlock()
+	return len(fake.countSpaceServiceInstancesArgsForCall)
+}
+
+func (fake *FakeClient) CountSpaceServiceInstancesArgsForCall(i int) string {
+	fake.countSpaceServiceInstancesMutex.RLock()
+	defer fake.countSpaceServiceInstancesMutex.RUnlock()
+	return fake.countSpaceServiceInstancesArgsForCall[i].spaceGuid
+}
+
+func (fake *FakeClient) CountSpaceServiceInstancesReturns(result1 int, result2 error) {
+	fake.CountSpaceServiceInstancesStub = nil
+	fake.countSpaceServiceInstancesReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CountSpaceServiceInstancesReturnsOnCall(i int, result1 int, result2 error) {
+	fake.CountSpaceServiceInstancesStub = nil
+	if fake.countSpaceServiceInstancesReturnsOnCall == nil {
+		fake.countSpaceServiceInstancesReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.countSpaceServiceInstancesReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CountOrganizationServiceInstances(organizationGuid string) (int, error) {
+	fake.countOrganizationServiceInstancesMutex.Lock()
+	ret, specificReturn := fake.countOrganizationServiceInstancesReturnsOnCall[len(fake.countOrganizationServiceInstancesArgsForCall)]
+	fake.countOrganizationServiceInstancesArgsForCall = append(fake.countOrganizationServiceInstancesArgsForCall, struct {
+		organizationGuid string
+	}{organizationGuid})
+	fake.recordInvocation("CountOrganizationServiceInstances", []interface{}{organizationGuid})
+	fake.countOrganizationServiceInstancesMutex.Unlock()
+	if fake.CountOrganizationServiceInstancesStub != nil {
+		return fake.CountOrganizationServiceInstancesStub(organizationGuid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.countOrganizationServiceInstancesReturns.result1, fake.countOrganizationServiceInstancesReturns.result2
+}
+
+func (fake *FakeClient) CountOrganizationServiceInstancesCallCount() int {
+	fake.countOrganizationServiceInstancesMutex.RLock()
+	defer fake.countOrganizationServiceInstancesMutex.RUnlock()
+	return len(fake.countOrganizationServiceInstancesArgsForCall)
+}
+
+func (fake *FakeClient) CountOrganizationServiceInstancesArgsForCall(i int) string {
+	fake.countOrganizationServiceInstancesMutex.RLock()
+	defer fake.countOrganizationServiceInstancesMutex.RUnlock()
+	return fake.countOrganizationServiceInstancesArgsForCall[i].organizationGuid
+}
+
+func (fake *FakeClient) CountOrganizationServiceInstancesReturns(result1 int, result2 error) {
+	fake.CountOrganizationServiceInstancesStub = nil
+	fake.countOrganizationServiceInstancesReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CountOrganizationServiceInstancesReturnsOnCall(i int, result1 int, result2 error) {
+	fake.CountOrganizationServiceInstancesStub = nil
+	if fake.countOrganizationServiceInstancesReturnsOnCall == nil {
+		fake.countOrganizationServiceInstancesReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.countOrganizationServiceInstancesReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.getServicesMutex.RLock()
 	defer fake.getServicesMutex.RUnlock()
+	fake.getAllServicesMutex.RLock()
+	defer fake.getAllServicesMutex.RUnlock()
 	fake.getServicePlansMutex.RLock()
 	defer fake.getServicePlansMutex.RUnlock()
 	fake.getServicePlanInstancesMutex.RLock()
 	defer fake.getServicePlanInstancesMutex.RUnlock()
+	fake.getUserProvidedServiceInstancesMutex.RLock()
+	defer fake.getUserProvidedServiceInstancesMutex.RUnlock()
 	fake.deleteServiceInstanceMutex.RLock()
 	defer fake.deleteServiceInstanceMutex.RUnlock()
+	fake.deleteUserProvidedServiceInstanceMutex.RLock()
+	defer fake.deleteUserProvidedServiceInstanceMutex.RUnlock()
+	fake.purgeServiceInstanceMutex.RLock()
+	defer fake.purgeServiceInstanceMutex.RUnlock()
+	fake.getServiceInstanceParametersMutex.RLock()
+	defer fake.getServiceInstanceParametersMutex.RUnlock()
+	fake.getServiceInstanceLabelsMutex.RLock()
+	defer fake.getServiceInstanceLabelsMutex.RUnlock()
+	fake.getServiceBindingsMutex.RLock()
+	defer fake.getServiceBindingsMutex.RUnlock()
+	fake.getServiceKeysMutex.RLock()
+	defer fake.getServiceKeysMutex.RUnlock()
+	fake.deleteServiceKeyMutex.RLock()
+	defer fake.deleteServiceKeyMutex.RUnlock()
+	fake.deleteServiceBindingMutex.RLock()
+	defer fake.deleteServiceBindingMutex.RUnlock()
+	fake.getServiceBindingMutex.RLock()
+	defer fake.getServiceBindingMutex.RUnlock()
+	fake.getServiceInstanceMutex.RLock()
+	defer fake.getServiceInstanceMutex.RUnlock()
+	fake.getUserProvidedServiceInstanceMutex.RLock()
+	defer fake.getUserProvidedServiceInstanceMutex.RUnlock()
+	fake.getServiceInstanceRoutesMutex.RLock()
+	defer fake.getServiceInstanceRoutesMutex.RUnlock()
+	fake.unbindRouteServiceMutex.RLock()
+	defer fake.unbindRouteServiceMutex.RUnlock()
+	fake.createServiceInstanceMutex.RLock()
+	defer fake.createServiceInstanceMutex.RUnlock()
+	fake.getServiceBindingDeleteEventsMutex.RLock()
+	defer fake.getServiceBindingDeleteEventsMutex.RUnlock()
+	fake.getAppsMutex.RLock()
+	defer fake.getAppsMutex.RUnlock()
+	fake.deleteAppMutex.RLock()
+	defer fake.deleteAppMutex.RUnlock()
+	fake.getAppRoutesMutex.RLock()
+	defer fake.getAppRoutesMutex.RUnlock()
+	fake.getRouteAppsMutex.RLock()
+	defer fake.getRouteAppsMutex.RUnlock()
+	fake.deleteRouteMutex.RLock()
+	defer fake.deleteRouteMutex.RUnlock()
+	fake.getSpacesMutex.RLock()
+	defer fake.getSpacesMutex.RUnlock()
+	fake.getSpaceUsageMutex.RLock()
+	defer fake.getSpaceUsageMutex.RUnlock()
+	fake.deleteSpaceMutex.RLock()
+	defer fake.deleteSpaceMutex.RUnlock()
+	fake.getSpaceMutex.RLock()
+	defer fake.getSpaceMutex.RUnlock()
+	fake.getSpaceQuotaDefinitionMutex.RLock()
+	defer fake.getSpaceQuotaDefinitionMutex.RUnlock()
+	fake.getOrganizationMutex.RLock()
+	defer fake.getOrganizationMutex.RUnlock()
+	fake.getOrganizationQuotaDefinitionMutex.RLock()
+	defer fake.getOrganizationQuotaDefinitionMutex.RUnlock()
+	fake.countSpaceServiceInstancesMutex.RLock()
+	defer fake.countSpaceServiceInstancesMutex.RUnlock()
+	fake.countOrganizationServiceInstancesMutex.RLock()
+	defer fake.countOrganizationServiceInstancesMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -19,28 +19,185 @@ package cloudfoundry
 type Metadata struct {
 	Guid      string
 	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
 }
 
 type Service struct {
 	Metadata Metadata
+	Entity   ServiceEntity
+}
+
+type ServiceEntity struct {
+	Label string
 }
 
 type ServicePlan struct {
 	Metadata Metadata
-	Entity   struct {
-		Name string
-		Free bool
-	}
+	Entity   ServicePlanEntity
+}
+
+type ServicePlanEntity struct {
+	Name string
+	Free bool
 }
 
 type ServiceInstance struct {
 	Metadata Metadata
-	Entity   struct {
-		Name string
+	Entity   ServiceInstanceEntity
+}
+
+const UserProvidedServiceInstanceType = "user_provided_service_instance"
+
+func (s ServiceInstance) UserProvided() bool {
+	return s.Entity.Type == UserProvidedServiceInstanceType
+}
+
+type ServiceInstanceEntity struct {
+	Name            string
+	Type            string
+	ServicePlanGuid string `json:"service_plan_guid"`
+	SpaceGuid       string `json:"space_guid"`
+	Tags            []string
+	LastOperation   LastOperation `json:"last_operation"`
+}
+
+const (
+	LastOperationInProgress = "in progress"
+	LastOperationSucceeded  = "succeeded"
+	LastOperationFailed     = "failed"
+)
+
+type LastOperation struct {
+	Type        string
+	State       string
+	Description string
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type ServiceBinding struct {
+	Metadata Metadata
+	Entity   ServiceBindingEntity
+}
+
+type ServiceBindingEntity struct {
+	Name                string
+	AppGuid             string        `json:"app_guid"`
+	ServiceInstanceGuid string        `json:"service_instance_guid"`
+	LastOperation       LastOperation `json:"last_operation"`
+}
+
+type ServiceKey struct {
+	Metadata Metadata
+	Entity   ServiceKeyEntity
+}
+
+// Credentials are deliberately not decoded so that they are never written to a snapshot.
+type ServiceKeyEntity struct {
+	Name                string
+	ServiceInstanceGuid string `json:"service_instance_guid"`
+}
+
+type Event struct {
+	Metadata Metadata
+	Entity   EventEntity
+}
+
+type EventEntity struct {
+	Type      string
+	Actee     string
+	Timestamp string
+	Metadata  EventMetadata
+}
+
+type EventMetadata struct {
+	Request struct {
+		ServiceInstanceGuid string `json:"service_instance_guid"`
 	}
 }
 
+const (
+	AppStarted = "STARTED"
+	AppStopped = "STOPPED"
+)
+
+type App struct {
+	Metadata Metadata
+	Entity   AppEntity
+}
+
+type AppEntity struct {
+	Name      string
+	SpaceGuid string `json:"space_guid"`
+	State     string
+}
+
+type Route struct {
+	Metadata Metadata
+	Entity   RouteEntity
+}
+
+type RouteEntity struct {
+	Host       string
+	Path       string
+	DomainGuid string `json:"domain_guid"`
+	SpaceGuid  string `json:"space_guid"`
+}
+
+type Space struct {
+	Metadata Metadata
+	Entity   SpaceEntity
+}
+
+type SpaceEntity struct {
+	Name                     string
+	OrganizationGuid         string `json:"organization_guid"`
+	SpaceQuotaDefinitionGuid string `json:"space_quota_definition_guid"`
+}
+
+type Organization struct {
+	Metadata Metadata
+	Entity   OrganizationEntity
+}
+
+type OrganizationEntity struct {
+	Name                string
+	QuotaDefinitionGuid string `json:"quota_definition_guid"`
+}
+
+// UnlimitedServices is the total number of services permitted by a quota definition which imposes no limit.
+const UnlimitedServices = -1
+
+type QuotaDefinition struct {
+	Metadata Metadata
+	Entity   QuotaDefinitionEntity
+}
+
+type QuotaDefinitionEntity struct {
+	Name          string
+	TotalServices int `json:"total_services"`
+}
+
+type SpaceUsage struct {
+	Apps             int
+	ServiceInstances int
+	Routes           int
+}
+
+func (u SpaceUsage) Empty() bool {
+	return u.Apps == 0 && u.ServiceInstances == 0 && u.Routes == 0
+}
+
+type CreateServiceInstanceRequest struct {
+	Name            string                 `json:"name"`
+	SpaceGuid       string                 `json:"space_guid"`
+	ServicePlanGuid string                 `json:"service_plan_guid"`
+	Parameters      map[string]interface{} `json:"parameters,omitempty"`
+	Tags            []string               `json:"tags,omitempty"`
+}
+
 type listServicesResponse struct {
+	NextUrl   string `json:"next_url"`
 	Resources []Service
 }
 
@@ -54,6 +211,45 @@ type listServicePlanInstancesResponse struct {
 	Resources []ServiceInstance
 }
 
+type listUserProvidedServiceInstancesResponse struct {
+	NextUrl   string `json:"next_url"`
+	Resources []ServiceInstance
+}
+
+type listServiceBindingsResponse struct {
+	NextUrl   string `json:"next_url"`
+	Resources []ServiceBinding
+}
+
+type listServiceKeysResponse struct {
+	NextUrl   string `json:"next_url"`
+	Resources []ServiceKey
+}
+
+type listEventsResponse struct {
+	NextUrl   string `json:"next_url"`
+	Resources []Event
+}
+
+type listAppsResponse struct {
+	NextUrl   string `json:"next_url"`
+	Resources []App
+}
+
+type listRoutesResponse struct {
+	NextUrl   string `json:"next_url"`
+	Resources []Route
+}
+
+type listSpacesResponse struct {
+	NextUrl   string `json:"next_url"`
+	Resources []Space
+}
+
+type countResponse struct {
+	TotalResults int `json:"total_results"`
+}
+
 type infoResponse struct {
 	AuthorisationEndpoint string `json:"authorization_endpoint"`
 }
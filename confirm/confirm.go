@@ -0,0 +1,168 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package confirm
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/hako/durafmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Answer is a response to a request for confirmation.
+type Answer int
+
+const (
+	// Yes confirms the deletion of a single candidate.
+	Yes Answer = iota
+	// No declines the deletion of a single candidate.
+	No
+	// All confirms the deletion of a candidate and of all which follow it.
+	All
+	// Quit declines the deletion of a candidate and of all which follow it.
+	Quit
+)
+
+const (
+	// ServiceKeyType is the Type of a candidate which is a service key.
+	ServiceKeyType = "service key"
+	// AppType is the Type of a candidate which is an app.
+	AppType = "app"
+	// SpaceType is the Type of a candidate which is an empty space.
+	SpaceType = "space"
+)
+
+// Candidate describes a resource which is about to be deleted. Type is empty for a service instance, which is
+// described by SpaceName and ServiceBindings. A service key is described by ServiceInstanceName instead, and an app
+// by SpaceName alone.
+type Candidate struct {
+	Type                string
+	Name                string
+	Guid                string
+	Age                 time.Duration
+	SpaceName           string
+	ServiceBindings     int
+	ServiceInstanceName string
+}
+
+func (c Candidate) String() string {
+	age := durafmt.Parse(c.Age.Truncate(time.Minute))
+	switch c.Type {
+	case ServiceKeyType:
+		return fmt.Sprintf("service key %s %s (%s old, of service instance %s)", c.Name, c.Guid, age, c.ServiceInstanceName)
+	case AppType:
+		return fmt.Sprintf("app %s %s (%s old, in space %s)", c.Name, c.Guid, age, c.SpaceName)
+	case SpaceType:
+		return fmt.Sprintf("empty space %s %s (%s old)", c.Name, c.Guid, age)
+	}
+	return fmt.Sprintf("%s %s (%s old, in space %s, %d service bindings)", c.Name, c.Guid, age, c.SpaceName, c.ServiceBindings)
+}
+
+// plural names the type of the given candidates, which are all of the same type, in the plural.
+func plural(candidates []Candidate) string {
+	switch candidates[0].Type {
+	case ServiceKeyType:
+		return "service keys"
+	case AppType:
+		return "apps"
+	case SpaceType:
+		return "spaces"
+	}
+	return "service instances"
+}
+
+//go:generate counterfeiter . Confirmer
+type Confirmer interface {
+	// Confirm asks whether the given candidate should be deleted.
+	Confirm(candidate Candidate) (Answer, error)
+
+	// ConfirmBatch asks once whether all the given candidates, which must be of the same type, should be deleted,
+	// answering All or Quit.
+	ConfirmBatch(candidates []Candidate) (Answer, error)
+}
+
+type prompt struct {
+	input  *bufio.Reader
+	output io.Writer
+}
+
+// NewPrompt returns a confirmer which asks questions on the given output and reads answers, one per line, from the
+// given input. Reaching the end of the input is taken to mean Quit.
+func NewPrompt(input io.Reader, output io.Writer) Confirmer {
+	return &prompt{
+		input:  bufio.NewReader(input),
+		output: output,
+	}
+}
+
+func (p *prompt) Confirm(candidate Candidate) (Answer, error) {
+	for {
+		fmt.Fprintf(p.output, "Delete %s? [y]es, [n]o, [a]ll, [q]uit: ", candidate)
+		answer, ok, err := p.readAnswer()
+		if err != nil || !ok {
+			return Quit, err
+		}
+
+		switch answer {
+		case "y", "yes":
+			return Yes, nil
+		case "n", "no":
+			return No, nil
+		case "a", "all":
+			return All, nil
+		case "q", "quit":
+			return Quit, nil
+		}
+	}
+}
+
+func (p *prompt) ConfirmBatch(candidates []Candidate) (Answer, error) {
+	for _, candidate := range candidates {
+		fmt.Fprintln(p.output, candidate)
+	}
+
+	for {
+		fmt.Fprintf(p.output, "Delete these %d %s? [y]es, [n]o: ", len(candidates), plural(candidates))
+		answer, ok, err := p.readAnswer()
+		if err != nil || !ok {
+			return Quit, err
+		}
+
+		switch answer {
+		case "y", "yes":
+			return All, nil
+		case "n", "no":
+			return Quit, nil
+		}
+	}
+}
+
+// readAnswer reads the next line of input, reporting false if the input is exhausted.
+func (p *prompt) readAnswer() (string, bool, error) {
+	line, err := p.input.ReadString('\n')
+	if err == io.EOF {
+		if line == "" {
+			fmt.Fprintln(p.output)
+			return "", false, nil
+		}
+	} else if err != nil {
+		return "", false, err
+	}
+	return strings.ToLower(strings.TrimSpace(line)), true, nil
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package confirm_test
+
+import (
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/service-instance-reaper/confirm"
+	"strings"
+	"time"
+)
+
+type brokenReader struct{}
+
+func (brokenReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+var _ = Describe("Prompt", func() {
+	var (
+		input     string
+		output    *gbytes.Buffer
+		confirmer confirm.Confirmer
+		candidate = confirm.Candidate{
+			Name:            "instance-name",
+			Guid:            "instance-guid",
+			Age:             26*time.Hour + 30*time.Second,
+			SpaceName:       "space-name",
+			ServiceBindings: 2,
+		}
+	)
+
+	BeforeEach(func() {
+		output = gbytes.NewBuffer()
+	})
+
+	JustBeforeEach(func() {
+		confirmer = confirm.NewPrompt(strings.NewReader(input), output)
+	})
+
+	Describe("Confirm", func() {
+		It("describes the candidate", func() {
+			input = "y\n"
+			confirmer.Confirm(candidate)
+			Expect(output).To(gbytes.Say(`Delete instance-name instance-guid \(1 day 2 hours old, in space space-name, 2 service bindings\)\? \[y\]es, \[n\]o, \[a\]ll, \[q\]uit: `))
+		})
+
+		It("describes a service key", func() {
+			input = "y\n"
+			confirmer.Confirm(confirm.Candidate{Type: confirm.ServiceKeyType, Name: "key-name", Guid: "key-guid", Age: 3 * time.Hour, ServiceInstanceName: "instance-name"})
+			Expect(output).To(gbytes.Say(`Delete service key key-name key-guid \(3 hours old, of service instance instance-name\)\? `))
+		})
+
+		It("describes an app", func() {
+			input = "y\n"
+			confirmer.Confirm(confirm.Candidate{Type: confirm.AppType, Name: "app-name", Guid: "app-guid", Age: 3 * time.Hour, SpaceName: "space-name"})
+			Expect(output).To(gbytes.Say(`Delete app app-name app-guid \(3 hours old, in space space-name\)\? `))
+		})
+
+		It("describes an empty space", func() {
+			input = "y\n"
+			confirmer.Confirm(confirm.Candidate{Type: confirm.SpaceType, Name: "space-name", Guid: "space-guid", Age: 3 * time.Hour})
+			Expect(output).To(gbytes.Say(`Delete empty space space-name space-guid \(3 hours old\)\? `))
+		})
+
+		It("understands each answer", func() {
+			for text, expected := range map[string]confirm.Answer{
+				"y\n":    confirm.Yes,
+				"YES\n":  confirm.Yes,
+				"n\n":    confirm.No,
+				"no\n":   confirm.No,
+				"a\n":    confirm.All,
+				" all\n": confirm.All,
+				"q\n":    confirm.Quit,
+				"quit":   confirm.Quit,
+			} {
+				answer, err := confirm.NewPrompt(strings.NewReader(text), output).Confirm(candidate)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(answer).To(Equal(expected), text)
+			}
+		})
+
+		Context("when the answer is not understood", func() {
+			BeforeEach(func() {
+				input = "maybe\n\nn\n"
+			})
+
+			It("asks again", func() {
+				answer, err := confirmer.Confirm(candidate)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(answer).To(Equal(confirm.No))
+				Expect(strings.Count(string(output.Contents()), "Delete instance-name")).To(Equal(3))
+			})
+		})
+
+		Context("when the input is exhausted", func() {
+			BeforeEach(func() {
+				input = ""
+			})
+
+			It("quits", func() {
+				answer, err := confirmer.Confirm(candidate)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(answer).To(Equal(confirm.Quit))
+			})
+		})
+
+		Context("when the input cannot be read", func() {
+			It("quits and returns the error", func() {
+				answer, err := confirm.NewPrompt(brokenReader{}, output).Confirm(candidate)
+				Expect(err).To(MatchError("read failed"))
+				Expect(answer).To(Equal(confirm.Quit))
+			})
+		})
+	})
+
+	Describe("ConfirmBatch", func() {
+		BeforeEach(func() {
+			input = "y\n"
+		})
+
+		It("lists the candidates and asks once", func() {
+			other := candidate
+			other.Name = "other-name"
+
+			answer, err := confirmer.ConfirmBatch([]confirm.Candidate{candidate, other})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(answer).To(Equal(confirm.All))
+			Expect(output).To(gbytes.Say(`instance-name instance-guid \(1 day 2 hours old`))
+			Expect(output).To(gbytes.Say(`other-name instance-guid`))
+			Expect(output).To(gbytes.Say(`Delete these 2 service instances\? \[y\]es, \[n\]o: `))
+		})
+
+		It("names the type of the candidates", func() {
+			key := confirm.Candidate{Type: confirm.ServiceKeyType, Name: "key-name", Guid: "key-guid", ServiceInstanceName: "instance-name"}
+			confirmer.ConfirmBatch([]confirm.Candidate{key})
+			Expect(output).To(gbytes.Say(`Delete these 1 service keys\? `))
+
+			app := confirm.Candidate{Type: confirm.AppType, Name: "app-name", Guid: "app-guid", SpaceName: "space-name"}
+			confirmer.ConfirmBatch([]confirm.Candidate{app})
+			Expect(output).To(gbytes.Say(`Delete these 1 apps\? `))
+
+			space := confirm.Candidate{Type: confirm.SpaceType, Name: "space-name", Guid: "space-guid"}
+			confirmer.ConfirmBatch([]confirm.Candidate{space})
+			Expect(output).To(gbytes.Say(`Delete these 1 spaces\? `))
+		})
+
+		Context("when the batch is declined", func() {
+			BeforeEach(func() {
+				input = "all\nn\n"
+			})
+
+			It("quits", func() {
+				answer, err := confirmer.ConfirmBatch([]confirm.Candidate{candidate})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(answer).To(Equal(confirm.Quit))
+			})
+		})
+	})
+})
@@ -0,0 +1,160 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Run is the result of a single run of the reaper.
+type Run struct {
+	ID            string     `json:"id"`
+	Started       string     `json:"started"`
+	DryRun        bool       `json:"dry_run"`
+	ServiceName   string     `json:"service_name,omitempty"`
+	PlanName      string     `json:"plan_name,omitempty"`
+	Candidates    int        `json:"candidates"`
+	Reaped        int        `json:"reaped"`
+	Skipped       int        `json:"skipped"`
+	Failed        int        `json:"failed"`
+	ListingErrors int        `json:"listing_errors"`
+	Deletions     []Deletion `json:"deletions,omitempty"`
+}
+
+// Deletion is the outcome of attempting to delete a service instance, one of its service keys if ServiceKeyGuid is
+// set, an app if AppGuid is set, or an empty space if SpaceName is set. Error is set if the deletion failed.
+type Deletion struct {
+	ServiceInstanceGuid string `json:"service_instance_guid,omitempty"`
+	ServiceInstanceName string `json:"service_instance_name,omitempty"`
+	ServiceKeyGuid      string `json:"service_key_guid,omitempty"`
+	ServiceKeyName      string `json:"service_key_name,omitempty"`
+	AppGuid             string `json:"app_guid,omitempty"`
+	AppName             string `json:"app_name,omitempty"`
+	SpaceGuid           string `json:"space_guid"`
+	SpaceName           string `json:"space_name,omitempty"`
+	Error               string `json:"error,omitempty"`
+}
+
+// Reaped reports whether the service instance, service key, app, or space was deleted.
+func (d Deletion) Reaped() bool {
+	return d.Error == ""
+}
+
+//go:generate counterfeiter . Recorder
+type Recorder interface {
+	// Record adds the outcome of attempting to delete a service instance, service key, app, or space to the current
+	// run.
+	Record(deletion Deletion)
+}
+
+// RunRecorder collects the deletions of the current run. It is safe for concurrent use.
+type RunRecorder struct {
+	deletions []Deletion
+	mutex     sync.Mutex
+}
+
+func (r *RunRecorder) Record(deletion Deletion) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.deletions = append(r.deletions, deletion)
+}
+
+// Deletions returns the deletions recorded so far.
+func (r *RunRecorder) Deletions() []Deletion {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Deletion(nil), r.deletions...)
+}
+
+//go:generate counterfeiter . Store
+type Store interface {
+	// Save adds a run to the history.
+	Save(run Run) error
+
+	// Runs returns every run in the history, in the order in which they were saved.
+	Runs() ([]Run, error)
+}
+
+type fileStore struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewStore returns a store which keeps the history in the file at the given path, one JSON object per run per line,
+// so that saving a run never rewrites earlier runs. A plain file is used, rather than an embedded database such as
+// bbolt or SQLite, because neither is vendored and the reaper takes no dependencies beyond those it vendors.
+func NewStore(path string) Store {
+	return &fileStore{path: path}
+}
+
+func (s *fileStore) Save(run Run) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	line, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("cannot encode run: %s", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("cannot open history %s: %s", s.path, err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("cannot write history %s: %s", s.path, err)
+	}
+
+	return nil
+}
+
+func (s *fileStore) Runs() ([]Run, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open history %s: %s", s.path, err)
+	}
+	defer file.Close()
+
+	var runs []Run
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			return nil, fmt.Errorf("cannot parse history %s at line %d: %s", s.path, line, err)
+		}
+		runs = append(runs, run)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read history %s: %s", s.path, err)
+	}
+
+	return runs, nil
+}
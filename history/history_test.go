@@ -0,0 +1,99 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package history_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/service-instance-reaper/history"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("Store", func() {
+	var (
+		directory string
+		path      string
+		store     history.Store
+	)
+
+	BeforeEach(func() {
+		var err error
+		directory, err = ioutil.TempDir("", "history")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(directory, "history.jsonl")
+		store = history.NewStore(path)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(directory)
+	})
+
+	It("returns the runs saved in order", func() {
+		run1 := history.Run{ID: "run-1", Started: "2026-10-12T09:00:00Z", Candidates: 2, Reaped: 1, Failed: 1, Deletions: []history.Deletion{
+			{ServiceInstanceGuid: "guid-1", SpaceGuid: "space-1"},
+			{ServiceInstanceGuid: "guid-2", SpaceGuid: "space-1", Error: "broker error"},
+		}}
+		run2 := history.Run{ID: "run-2", Started: "2026-10-13T09:00:00Z", DryRun: true, Candidates: 1}
+		Expect(store.Save(run1)).To(Succeed())
+		Expect(store.Save(run2)).To(Succeed())
+
+		Expect(history.NewStore(path).Runs()).To(Equal([]history.Run{run1, run2}))
+	})
+
+	Context("when the history does not exist", func() {
+		It("returns an error", func() {
+			_, err := store.Runs()
+			Expect(err).To(MatchError(ContainSubstring("cannot open history")))
+		})
+	})
+
+	Context("when the history is corrupt", func() {
+		BeforeEach(func() {
+			Expect(ioutil.WriteFile(path, []byte("{\"id\":\"run-1\"}\n{\n"), 0600)).To(Succeed())
+		})
+
+		It("identifies the corrupt line", func() {
+			_, err := store.Runs()
+			Expect(err).To(MatchError(ContainSubstring("at line 2")))
+		})
+	})
+
+	Context("when the history cannot be written", func() {
+		BeforeEach(func() {
+			store = history.NewStore(filepath.Join(directory, "missing", "history.jsonl"))
+		})
+
+		It("returns an error", func() {
+			Expect(store.Save(history.Run{})).To(MatchError(ContainSubstring("cannot open history")))
+		})
+	})
+})
+
+var _ = Describe("RunRecorder", func() {
+	It("collects the deletions recorded", func() {
+		recorder := &history.RunRecorder{}
+		recorder.Record(history.Deletion{ServiceInstanceGuid: "guid-1"})
+		recorder.Record(history.Deletion{ServiceInstanceGuid: "guid-2", Error: "broker error"})
+
+		Expect(recorder.Deletions()).To(Equal([]history.Deletion{
+			{ServiceInstanceGuid: "guid-1"},
+			{ServiceInstanceGuid: "guid-2", Error: "broker error"},
+		}))
+	})
+})
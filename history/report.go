@@ -0,0 +1,236 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package history
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Report summarises the runs in a history to show whether the number of service instances reaped is growing or
+// shrinking and where they come from.
+type Report struct {
+	Weeks            []Week
+	Reaped           []Reaped
+	TopSpaces        []Space
+	RepeatedFailures []Failure
+}
+
+// Week totals the runs which started in the week beginning on Monday Start.
+type Week struct {
+	Start      string
+	Runs       int
+	Candidates int
+	Reaped     int
+	Failed     int
+}
+
+// Reaped counts the service instances of a plan reaped from a space in the week beginning on Monday Week.
+type Reaped struct {
+	Week        string
+	ServiceName string
+	PlanName    string
+	SpaceGuid   string
+	Count       int
+}
+
+// Space counts the service instances reaped from a space.
+type Space struct {
+	SpaceGuid string
+	Reaped    int
+}
+
+// Failure is a service instance which could not be deleted in more than one run.
+type Failure struct {
+	ServiceInstanceGuid string
+	ServiceInstanceName string
+	SpaceGuid           string
+	Failures            int
+	LastFailed          string
+	LastError           string
+}
+
+// Summarise reports on the runs which started at or after the given time, listing at most top spaces. Dry runs count
+// towards the candidates of each week but nothing else. Deletions of service keys and apps are counted in the totals
+// of each week but not otherwise reported. A service
+// instance which failed to be deleted but was reaped by a later run is not listed as a repeated failure.
+func Summarise(runs []Run, since time.Time, top int) (Report, error) {
+	var report Report
+	weeks := make(map[string]*Week)
+	reaped := make(map[Reaped]int)
+	spaces := make(map[string]int)
+	failures := make(map[string]*Failure)
+
+	for _, run := range runs {
+		started, err := time.Parse(time.RFC3339, run.Started)
+		if err != nil {
+			return report, fmt.Errorf("invalid start time of run %s: %s", run.ID, err)
+		}
+		if started.Before(since) {
+			continue
+		}
+
+		start := weekOf(started)
+		week, ok := weeks[start]
+		if !ok {
+			week = &Week{Start: start}
+			weeks[start] = week
+		}
+		week.Runs++
+		week.Candidates += run.Candidates
+		if run.DryRun {
+			continue
+		}
+		week.Reaped += run.Reaped
+		week.Failed += run.Failed
+
+		for _, deletion := range run.Deletions {
+			if deletion.ServiceKeyGuid != "" || deletion.AppGuid != "" || deletion.SpaceName != "" {
+				continue
+			}
+			if deletion.Reaped() {
+				reaped[Reaped{Week: start, ServiceName: run.ServiceName, PlanName: run.PlanName, SpaceGuid: deletion.SpaceGuid}]++
+				spaces[deletion.SpaceGuid]++
+				delete(failures, deletion.ServiceInstanceGuid)
+				continue
+			}
+
+			failure, ok := failures[deletion.ServiceInstanceGuid]
+			if !ok {
+				failure = &Failure{ServiceInstanceGuid: deletion.ServiceInstanceGuid}
+				failures[deletion.ServiceInstanceGuid] = failure
+			}
+			failure.ServiceInstanceName = deletion.ServiceInstanceName
+			failure.SpaceGuid = deletion.SpaceGuid
+			failure.Failures++
+			failure.LastFailed = run.Started
+			failure.LastError = deletion.Error
+		}
+	}
+
+	for _, week := range weeks {
+		report.Weeks = append(report.Weeks, *week)
+	}
+	sort.Slice(report.Weeks, func(i, j int) bool { return report.Weeks[i].Start < report.Weeks[j].Start })
+
+	for key, count := range reaped {
+		key.Count = count
+		report.Reaped = append(report.Reaped, key)
+	}
+	sort.Slice(report.Reaped, func(i, j int) bool {
+		a, b := report.Reaped[i], report.Reaped[j]
+		if a.Week != b.Week {
+			return a.Week < b.Week
+		}
+		if a.ServiceName != b.ServiceName {
+			return a.ServiceName < b.ServiceName
+		}
+		if a.PlanName != b.PlanName {
+			return a.PlanName < b.PlanName
+		}
+		return a.SpaceGuid < b.SpaceGuid
+	})
+
+	for spaceGuid, count := range spaces {
+		report.TopSpaces = append(report.TopSpaces, Space{SpaceGuid: spaceGuid, Reaped: count})
+	}
+	sort.Slice(report.TopSpaces, func(i, j int) bool {
+		a, b := report.TopSpaces[i], report.TopSpaces[j]
+		if a.Reaped != b.Reaped {
+			return a.Reaped > b.Reaped
+		}
+		return a.SpaceGuid < b.SpaceGuid
+	})
+	if len(report.TopSpaces) > top {
+		report.TopSpaces = report.TopSpaces[:top]
+	}
+
+	for _, failure := range failures {
+		if failure.Failures > 1 {
+			report.RepeatedFailures = append(report.RepeatedFailures, *failure)
+		}
+	}
+	sort.Slice(report.RepeatedFailures, func(i, j int) bool {
+		a, b := report.RepeatedFailures[i], report.RepeatedFailures[j]
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		return a.ServiceInstanceGuid < b.ServiceInstanceGuid
+	})
+
+	return report, nil
+}
+
+// StartOfWeeks returns the start of the given number of weeks up to and including the week containing now, for use
+// with Summarise. Zero weeks means every run.
+func StartOfWeeks(now time.Time, weeks int) time.Time {
+	if weeks == 0 {
+		return time.Time{}
+	}
+
+	monday, _ := time.Parse("2006-01-02", weekOf(now))
+	return monday.AddDate(0, 0, -7*(weeks-1))
+}
+
+// weekOf returns the date of the Monday which begins the week, in UTC, containing the given time.
+func weekOf(t time.Time) string {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+}
+
+// Write prints the report as a series of tables.
+func (r Report) Write(output io.Writer) error {
+	table := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(table, "Runs by week")
+	fmt.Fprintln(table, "WEEK\tRUNS\tCANDIDATES\tREAPED\tFAILED")
+	for _, week := range r.Weeks {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%d\n", week.Start, week.Runs, week.Candidates, week.Reaped, week.Failed)
+	}
+
+	fmt.Fprintln(table, "\nReaped by week, service, plan, and space")
+	fmt.Fprintln(table, "WEEK\tSERVICE\tPLAN\tSPACE\tREAPED")
+	for _, reaped := range r.Reaped {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%d\n", reaped.Week, orNone(reaped.ServiceName), orNone(reaped.PlanName), orNone(reaped.SpaceGuid), reaped.Count)
+	}
+
+	fmt.Fprintln(table, "\nTop spaces")
+	fmt.Fprintln(table, "SPACE\tREAPED")
+	for _, space := range r.TopSpaces {
+		fmt.Fprintf(table, "%s\t%d\n", orNone(space.SpaceGuid), space.Reaped)
+	}
+
+	fmt.Fprintln(table, "\nRepeated failures")
+	fmt.Fprintln(table, "SERVICE INSTANCE\tGUID\tSPACE\tFAILURES\tLAST FAILED\tLAST ERROR")
+	for _, failure := range r.RepeatedFailures {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%s\t%s\n", failure.ServiceInstanceName, failure.ServiceInstanceGuid, orNone(failure.SpaceGuid),
+			failure.Failures, failure.LastFailed, failure.LastError)
+	}
+
+	return table.Flush()
+}
+
+func orNone(name string) string {
+	if name == "" {
+		return "-"
+	}
+	return name
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package history_test
+
+import (
+	"bytes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/service-instance-reaper/history"
+	"time"
+)
+
+var _ = Describe("Report", func() {
+	var (
+		runs   []history.Run
+		since  time.Time
+		report history.Report
+		err    error
+	)
+
+	BeforeEach(func() {
+		since = time.Time{}
+		runs = []history.Run{
+			{ID: "old", Started: "2026-09-01T09:00:00Z", ServiceName: "db", PlanName: "small", Candidates: 1, Reaped: 1, Deletions: []history.Deletion{
+				{ServiceInstanceGuid: "guid-0", SpaceGuid: "space-1"},
+			}},
+			{ID: "monday", Started: "2026-10-12T09:00:00Z", ServiceName: "db", PlanName: "small", Candidates: 3, Reaped: 2, Failed: 1, Deletions: []history.Deletion{
+				{ServiceInstanceGuid: "guid-1", SpaceGuid: "space-1"},
+				{ServiceInstanceGuid: "guid-2", SpaceGuid: "space-2"},
+				{ServiceInstanceGuid: "guid-3", ServiceInstanceName: "stuck", SpaceGuid: "space-2", Error: "broker error"},
+			}},
+			{ID: "sunday", Started: "2026-10-18T23:00:00Z", ServiceName: "db", PlanName: "small", Candidates: 2, Reaped: 1, Failed: 1, Deletions: []history.Deletion{
+				{ServiceInstanceGuid: "guid-4", SpaceGuid: "space-1"},
+				{ServiceInstanceGuid: "guid-3", ServiceInstanceName: "stuck", SpaceGuid: "space-2", Error: "broker still failing"},
+			}},
+			{ID: "dry", Started: "2026-10-19T09:00:00Z", ServiceName: "db", PlanName: "small", DryRun: true, Candidates: 4},
+		}
+	})
+
+	JustBeforeEach(func() {
+		report, err = history.Summarise(runs, since, 1)
+	})
+
+	It("totals the runs of each week, starting on Monday", func() {
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Weeks).To(Equal([]history.Week{
+			{Start: "2026-08-31", Runs: 1, Candidates: 1, Reaped: 1},
+			{Start: "2026-10-12", Runs: 2, Candidates: 5, Reaped: 3, Failed: 2},
+			{Start: "2026-10-19", Runs: 1, Candidates: 4},
+		}))
+	})
+
+	It("counts the service instances reaped by week, service, plan, and space", func() {
+		Expect(report.Reaped).To(Equal([]history.Reaped{
+			{Week: "2026-08-31", ServiceName: "db", PlanName: "small", SpaceGuid: "space-1", Count: 1},
+			{Week: "2026-10-12", ServiceName: "db", PlanName: "small", SpaceGuid: "space-1", Count: 2},
+			{Week: "2026-10-12", ServiceName: "db", PlanName: "small", SpaceGuid: "space-2", Count: 1},
+		}))
+	})
+
+	It("lists the spaces from which the most service instances were reaped", func() {
+		Expect(report.TopSpaces).To(Equal([]history.Space{{SpaceGuid: "space-1", Reaped: 3}}))
+	})
+
+	It("lists service instances which failed to be deleted more than once", func() {
+		Expect(report.RepeatedFailures).To(Equal([]history.Failure{{
+			ServiceInstanceGuid: "guid-3",
+			ServiceInstanceName: "stuck",
+			SpaceGuid:           "space-2",
+			Failures:            2,
+			LastFailed:          "2026-10-18T23:00:00Z",
+			LastError:           "broker still failing",
+		}}))
+	})
+
+	It("prints the report as tables", func() {
+		output := &bytes.Buffer{}
+		Expect(report.Write(output)).To(Succeed())
+		Expect(output.String()).To(ContainSubstring("2026-10-12  2     5           3       2"))
+		Expect(output.String()).To(ContainSubstring("stuck             guid-3  space-2  2         2026-10-18T23:00:00Z  broker still failing"))
+	})
+
+	Context("when apps are deleted", func() {
+		BeforeEach(func() {
+			runs = append(runs, history.Run{ID: "apps", Started: "2026-10-20T09:00:00Z", Candidates: 2, Reaped: 1, Failed: 1, Deletions: []history.Deletion{
+				{AppGuid: "app-guid-1", SpaceGuid: "space-3"},
+				{AppGuid: "app-guid-2", SpaceGuid: "space-3", Error: "app failure"},
+				{AppGuid: "app-guid-2", SpaceGuid: "space-3", Error: "app failure"},
+			}})
+		})
+
+		It("counts them in the totals of the week only", func() {
+			Expect(report.Weeks[2]).To(Equal(history.Week{Start: "2026-10-19", Runs: 2, Candidates: 6, Reaped: 1, Failed: 1}))
+			Expect(report.Reaped).To(HaveLen(3))
+			Expect(report.RepeatedFailures).To(HaveLen(1))
+		})
+	})
+
+	Context("when empty spaces are deleted", func() {
+		BeforeEach(func() {
+			runs = append(runs, history.Run{ID: "spaces", Started: "2026-10-20T09:00:00Z", Deletions: []history.Deletion{
+				{SpaceGuid: "space-4", SpaceName: "space-name-4"},
+				{SpaceGuid: "space-5", SpaceName: "space-name-5", Error: "space failure"},
+				{SpaceGuid: "space-5", SpaceName: "space-name-5", Error: "space failure"},
+			}})
+		})
+
+		It("leaves them out of the reaped service instances and the failures", func() {
+			Expect(report.Weeks[2]).To(Equal(history.Week{Start: "2026-10-19", Runs: 2, Candidates: 4}))
+			Expect(report.Reaped).To(HaveLen(3))
+			Expect(report.RepeatedFailures).To(HaveLen(1))
+		})
+	})
+
+	Context("when a service instance which failed repeatedly is reaped by a later run", func() {
+		BeforeEach(func() {
+			runs = append(runs, history.Run{ID: "tuesday", Started: "2026-10-20T09:00:00Z", ServiceName: "db", PlanName: "small", Candidates: 1, Reaped: 1, Deletions: []history.Deletion{
+				{ServiceInstanceGuid: "guid-3", ServiceInstanceName: "stuck", SpaceGuid: "space-2"},
+			}})
+		})
+
+		It("no longer lists it as a repeated failure", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.RepeatedFailures).To(BeEmpty())
+		})
+	})
+
+	Context("when only recent runs are wanted", func() {
+		BeforeEach(func() {
+			since = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		})
+
+		It("ignores earlier runs", func() {
+			Expect(report.Weeks).To(HaveLen(2))
+			Expect(report.TopSpaces).To(Equal([]history.Space{{SpaceGuid: "space-1", Reaped: 2}}))
+		})
+	})
+
+	Describe("the start of a number of weeks", func() {
+		It("is the Monday which begins the earliest week", func() {
+			now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+			Expect(history.StartOfWeeks(now, 1)).To(Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)))
+			Expect(history.StartOfWeeks(now, 3)).To(Equal(time.Date(2026, 9, 28, 0, 0, 0, 0, time.UTC)))
+			Expect(history.StartOfWeeks(now, 0)).To(BeZero())
+		})
+	})
+
+	Context("when a run has an invalid start time", func() {
+		BeforeEach(func() {
+			runs[0].Started = "yesterday"
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError(ContainSubstring("invalid start time of run old")))
+		})
+	})
+})
@@ -32,7 +32,9 @@ type HttpClient interface {
 type AuthenticatedClient interface {
 	DoAuthenticatedGet(url string, accessToken string) (io.ReadCloser, int, error)
 
-	DoAuthenticatedDelete(url string, accessToken string) (int, error)
+	// DoAuthenticatedDelete returns the body of any response received, even if the request failed, so that the
+	// caller can inspect the reason for the failure. The caller must close it.
+	DoAuthenticatedDelete(url string, accessToken string) (io.ReadCloser, int, error)
 
 	DoAuthenticatedPost(url string, bodyType string, body string, accessToken string) (io.ReadCloser, int, error)
 
@@ -67,26 +69,25 @@ func (c *authenticatedClient) DoAuthenticatedGet(url string, accessToken string)
 	return resp.Body, resp.StatusCode, nil
 }
 
-func (c *authenticatedClient) DoAuthenticatedDelete(url string, accessToken string) (int, error) {
+func (c *authenticatedClient) DoAuthenticatedDelete(url string, accessToken string) (io.ReadCloser, int, error) {
 	req, err := http.NewRequest("DELETE", url, nil)
 	if err != nil {
-		return 0, fmt.Errorf("Request creation error: %s", err)
+		return nil, 0, fmt.Errorf("Request creation error: %s", err)
 	}
 
 	req.Header.Add("Accept", "application/json")
 	addAuthorizationHeader(req, accessToken)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return 0, fmt.Errorf("Authenticated delete of '%s' failed: %s", url, err)
+		return nil, 0, fmt.Errorf("Authenticated delete of '%s' failed: %s", url, err)
 	}
 
 	switch resp.StatusCode {
 	case http.StatusOK, http.StatusAccepted, http.StatusNoContent:
-		return resp.StatusCode, nil
+		return resp.Body, resp.StatusCode, nil
 	default:
-		return resp.StatusCode, fmt.Errorf("Authenticated delete of '%s' failed: %s", url, resp.Status)
+		return resp.Body, resp.StatusCode, fmt.Errorf("Authenticated delete of '%s' failed: %s", url, resp.Status)
 	}
-
 }
 
 func (c *authenticatedClient) DoAuthenticatedPost(url string, bodyType string, bodyStr string, accessToken string) (io.ReadCloser, int, error) {
@@ -101,11 +102,13 @@ func (c *authenticatedClient) DoAuthenticatedPost(url string, bodyType string, b
 	if err != nil {
 		return nil, 0, fmt.Errorf("Authenticated post to '%s' failed: %s", url, err)
 	}
-	if resp.StatusCode != http.StatusOK {
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted:
+		return resp.Body, resp.StatusCode, nil
+	default:
 		return nil, resp.StatusCode, fmt.Errorf("Authenticated post to '%s' failed: %s", url, resp.Status)
 	}
-
-	return resp.Body, resp.StatusCode, nil
 }
 
 func (c *authenticatedClient) DoAuthenticatedPut(url string, accessToken string) (int, error) {
@@ -126,6 +126,8 @@ var _ = Describe("AuthClient", func() {
 	})
 
 	Describe("DoAuthenticatedDelete", func() {
+		var body io.ReadCloser
+
 		BeforeEach(func() {
 			URL = testUrl
 			resp := &http.Response{StatusCode: http.StatusOK}
@@ -134,7 +136,7 @@ var _ = Describe("AuthClient", func() {
 
 		JustBeforeEach(func() {
 			authClient := httpclient.NewAuthenticatedClient(fakeClient)
-			status, err = authClient.DoAuthenticatedDelete(URL, testAccessToken)
+			body, status, err = authClient.DoAuthenticatedDelete(URL, testAccessToken)
 		})
 
 		Context("when the URL is invalid", func() {
@@ -200,13 +202,17 @@ var _ = Describe("AuthClient", func() {
 
 		Context("when the request returns a bad status", func() {
 			BeforeEach(func() {
-				resp := &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not found"}
+				resp := &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not found", Body: ioutil.NopCloser(strings.NewReader(`{"error_code":"CF-NotFound"}`))}
 				fakeClient.DoReturns(resp, nil)
 			})
 
 			It("returns the error", func() {
 				Expect(err).To(MatchError("Authenticated delete of 'https://eureka.pivotal.io/auth/request' failed: 404 Not found"))
 			})
+
+			It("returns the response body", func() {
+				Expect(ioutil.ReadAll(body)).To(Equal([]byte(`{"error_code":"CF-NotFound"}`)))
+			})
 		})
 	})
 
@@ -269,6 +275,30 @@ var _ = Describe("AuthClient", func() {
 			Expect(status).To(Equal(http.StatusOK))
 		})
 
+		Context("when the request returns a created status", func() {
+			BeforeEach(func() {
+				resp := &http.Response{StatusCode: http.StatusCreated, Status: "201 Created"}
+				fakeClient.DoReturns(resp, nil)
+			})
+
+			It("passes the status code back", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status).To(Equal(http.StatusCreated))
+			})
+		})
+
+		Context("when the request returns an accepted status", func() {
+			BeforeEach(func() {
+				resp := &http.Response{StatusCode: http.StatusAccepted, Status: "202 Accepted"}
+				fakeClient.DoReturns(resp, nil)
+			})
+
+			It("passes the status code back", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status).To(Equal(http.StatusAccepted))
+			})
+		})
+
 		Context("when the request fails", func() {
 			BeforeEach(func() {
 				fakeClient.DoReturns(nil, testErr)
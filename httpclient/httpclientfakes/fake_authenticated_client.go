@@ -25,19 +25,21 @@ type FakeAuthenticatedClient struct {
 		result2 int
 		result3 error
 	}
-	DoAuthenticatedDeleteStub        func(url string, accessToken string) (int, error)
+	DoAuthenticatedDeleteStub        func(url string, accessToken string) (io.ReadCloser, int, error)
 	doAuthenticatedDeleteMutex       sync.RWMutex
 	doAuthenticatedDeleteArgsForCall []struct {
 		url         string
 		accessToken string
 	}
 	doAuthenticatedDeleteReturns struct {
-		result1 int
-		result2 error
+		result1 io.ReadCloser
+		result2 int
+		result3 error
 	}
 	doAuthenticatedDeleteReturnsOnCall map[int]struct {
-		result1 int
-		result2 error
+		result1 io.ReadCloser
+		result2 int
+		result3 error
 	}
 	DoAuthenticatedPostStub        func(url string, bodyType string, body string, accessToken string) (io.ReadCloser, int, error)
 	doAuthenticatedPostMutex       sync.RWMutex
@@ -130,7 +132,7 @@ func (fake *FakeAuthenticatedClient) DoAuthenticatedGetReturnsOnCall(i int, resu
 	}{result1, result2, result3}
 }
 
-func (fake *FakeAuthenticatedClient) DoAuthenticatedDelete(url string, accessToken string) (int, error) {
+func (fake *FakeAuthenticatedClient) DoAuthenticatedDelete(url string, accessToken string) (io.ReadCloser, int, error) {
 	fake.doAuthenticatedDeleteMutex.Lock()
 	ret, specificReturn := fake.doAuthenticatedDeleteReturnsOnCall[len(fake.doAuthenticatedDeleteArgsForCall)]
 	fake.doAuthenticatedDeleteArgsForCall = append(fake.doAuthenticatedDeleteArgsForCall, struct {
@@ -143,9 +145,9 @@ func (fake *FakeAuthenticatedClient) DoAuthenticatedDelete(url string, accessTok
 		return fake.DoAuthenticatedDeleteStub(url, accessToken)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1, ret.result2, ret.result3
 	}
-	return fake.doAuthenticatedDeleteReturns.result1, fake.doAuthenticatedDeleteReturns.result2
+	return fake.doAuthenticatedDeleteReturns.result1, fake.doAuthenticatedDeleteReturns.result2, fake.doAuthenticatedDeleteReturns.result3
 }
 
 func (fake *FakeAuthenticatedClient) DoAuthenticatedDeleteCallCount() int {
@@ -160,26 +162,29 @@ func (fake *FakeAuthenticatedClient) DoAuthenticatedDeleteArgsForCall(i int) (st
 	return fake.doAuthenticatedDeleteArgsForCall[i].url, fake.doAuthenticatedDeleteArgsForCall[i].accessToken
 }
 
-func (fake *FakeAuthenticatedClient) DoAuthenticatedDeleteReturns(result1 int, result2 error) {
+func (fake *FakeAuthenticatedClient) DoAuthenticatedDeleteReturns(result1 io.ReadCloser, result2 int, result3 error) {
 	fake.DoAuthenticatedDeleteStub = nil
 	fake.doAuthenticatedDeleteReturns = struct {
-		result1 int
-		result2 error
-	}{result1, result2}
+		result1 io.ReadCloser
+		result2 int
+		result3 error
+	}{result1, result2, result3}
 }
 
-func (fake *FakeAuthenticatedClient) DoAuthenticatedDeleteReturnsOnCall(i int, result1 int, result2 error) {
+func (fake *FakeAuthenticatedClient) DoAuthenticatedDeleteReturnsOnCall(i int, result1 io.ReadCloser, result2 int, result3 error) {
 	fake.DoAuthenticatedDeleteStub = nil
 	if fake.doAuthenticatedDeleteReturnsOnCall == nil {
 		fake.doAuthenticatedDeleteReturnsOnCall = make(map[int]struct {
-			result1 int
-			result2 error
+			result1 io.ReadCloser
+			result2 int
+			result3 error
 		})
 	}
 	fake.doAuthenticatedDeleteReturnsOnCall[i] = struct {
-		result1 int
-		result2 error
-	}{result1, result2}
+		result1 io.ReadCloser
+		result2 int
+		result3 error
+	}{result1, result2, result3}
 }
 
 func (fake *FakeAuthenticatedClient) DoAuthenticatedPost(url string, bodyType string, body string, accessToken string) (io.ReadCloser, int, error) {
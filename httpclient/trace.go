@@ -0,0 +1,151 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/logging"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const redacted = "[PRIVATE DATA HIDDEN]"
+
+var (
+	sensitiveHeaders = map[string]bool{
+		"Authorization": true,
+		"Cookie":        true,
+		"Set-Cookie":    true,
+	}
+	sensitiveFields = map[string]bool{
+		"access_token":  true,
+		"refresh_token": true,
+		"id_token":      true,
+		"password":      true,
+		"client_secret": true,
+		"credentials":   true,
+	}
+	sensitiveFormFields = regexp.MustCompile(`\b(access_token|refresh_token|id_token|password|client_secret)=[^&\s]*`)
+)
+
+type tracingClient struct {
+	httpClient HttpClient
+	logger     *logging.Logger
+}
+
+// NewTracingClient returns a client which logs each request made through the given client, and the response to it,
+// at debug level. Credentials and tokens are redacted from headers and bodies.
+func NewTracingClient(httpClient HttpClient, logger *logging.Logger) HttpClient {
+	return &tracingClient{httpClient: httpClient, logger: logger}
+}
+
+func (c *tracingClient) Do(req *http.Request) (*http.Response, error) {
+	if !c.logger.Enabled(logging.Debug) {
+		return c.httpClient.Do(req)
+	}
+
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug(fmt.Sprintf("REQUEST: %s %s\n%s\n%s", req.Method, req.URL, headers(req.Header), redact(requestBody)),
+		"method", req.Method, "url", req.URL.String())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Debug(fmt.Sprintf("RESPONSE: %s %s failed: %s", req.Method, req.URL, err),
+			"method", req.Method, "url", req.URL.String(), "error", err)
+		return resp, err
+	}
+
+	responseBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug(fmt.Sprintf("RESPONSE: %s\n%s\n%s", resp.Status, headers(resp.Header), redact(responseBody)),
+		"method", req.Method, "url", req.URL.String(), "status", resp.StatusCode)
+
+	return resp, nil
+}
+
+// readBody reads the given body, if any, and replaces it with a copy so that it can be read again.
+func readBody(body *io.ReadCloser) (string, error) {
+	if *body == nil {
+		return "", nil
+	}
+
+	contents, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return "", err
+	}
+	*body = ioutil.NopCloser(bytes.NewReader(contents))
+	return string(contents), nil
+}
+
+func headers(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, len(names))
+	for i, name := range names {
+		value := strings.Join(header[name], ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			value = redacted
+		}
+		lines[i] = fmt.Sprintf("%s: %s", name, value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// redact hides the values of sensitive fields in a JSON or form-encoded body.
+func redact(body string) string {
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(body), &parsed); err == nil {
+		if redactedBody, err := json.Marshal(redactJson(parsed)); err == nil {
+			return string(redactedBody)
+		}
+	}
+	return sensitiveFormFields.ReplaceAllString(body, fmt.Sprintf("$1=%s", redacted))
+}
+
+func redactJson(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if sensitiveFields[key] {
+				value[key] = redacted
+			} else {
+				value[key] = redactJson(field)
+			}
+		}
+	case []interface{}:
+		for i, element := range value {
+			value[i] = redactJson(element)
+		}
+	}
+	return value
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package httpclient_test
+
+import (
+	"bytes"
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/service-instance-reaper/httpclient"
+	"github.com/pivotal-cf/service-instance-reaper/httpclient/httpclientfakes"
+	"github.com/pivotal-cf/service-instance-reaper/logging"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+var _ = Describe("TracingClient", func() {
+	var (
+		fakeClient *httpclientfakes.FakeHttpClient
+		output     *gbytes.Buffer
+		level      logging.Level
+		request    *http.Request
+		response   *http.Response
+		err        error
+	)
+
+	BeforeEach(func() {
+		fakeClient = &httpclientfakes.FakeHttpClient{}
+		output = gbytes.NewBuffer()
+		level = logging.Debug
+
+		request, err = http.NewRequest("POST", "https://uaa.example.com/oauth/token", strings.NewReader("grant_type=password&password=secret&username=admin"))
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Add("Authorization", "bearer some-token")
+
+		fakeClient.DoReturns(&http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"access_token":"some-token","entity":{"credentials":{"password":"secret"},"name":"visible"}}`)),
+		}, nil)
+	})
+
+	JustBeforeEach(func() {
+		client := httpclient.NewTracingClient(fakeClient, logging.New(output, level, logging.Text))
+		response, err = client.Do(request)
+	})
+
+	It("logs the request and response with credentials redacted", func() {
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(gbytes.Say("REQUEST: POST https://uaa.example.com/oauth/token"))
+		Expect(output).To(gbytes.Say(`Authorization: \[PRIVATE DATA HIDDEN\]`))
+		Expect(output).To(gbytes.Say(`grant_type=password&password=\[PRIVATE DATA HIDDEN\]&username=admin`))
+		Expect(output).To(gbytes.Say("RESPONSE: 200 OK"))
+		Expect(output).To(gbytes.Say("Content-Type: application/json"))
+		Expect(output).To(gbytes.Say(`"access_token":"\[PRIVATE DATA HIDDEN\]"`))
+		Expect(string(output.Contents())).NotTo(ContainSubstring("secret"))
+		Expect(string(output.Contents())).NotTo(ContainSubstring("some-token"))
+		Expect(string(output.Contents())).To(ContainSubstring("visible"))
+	})
+
+	It("leaves the request and response bodies intact", func() {
+		sentBody, readErr := ioutil.ReadAll(fakeClient.DoArgsForCall(0).Body)
+		Expect(readErr).NotTo(HaveOccurred())
+		Expect(string(sentBody)).To(Equal("grant_type=password&password=secret&username=admin"))
+
+		receivedBody, readErr := ioutil.ReadAll(response.Body)
+		Expect(readErr).NotTo(HaveOccurred())
+		Expect(string(receivedBody)).To(ContainSubstring(`"access_token":"some-token"`))
+	})
+
+	Context("when the request fails", func() {
+		BeforeEach(func() {
+			fakeClient.DoReturns(nil, errors.New("connection refused"))
+		})
+
+		It("logs and returns the error", func() {
+			Expect(err).To(MatchError("connection refused"))
+			Expect(output).To(gbytes.Say("RESPONSE: POST https://uaa.example.com/oauth/token failed: connection refused"))
+		})
+	})
+
+	Context("when debug messages are not logged", func() {
+		BeforeEach(func() {
+			level = logging.Info
+		})
+
+		It("logs nothing", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.Contents()).To(BeEmpty())
+		})
+	})
+})
@@ -21,11 +21,16 @@ import (
 	"fmt"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
 	. "github.com/onsi/gomega/gexec"
+	"github.com/pivotal-cf/service-instance-reaper/checkpoint"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -44,6 +49,7 @@ var (
 	args            []string
 	fakeCfApiServer *httptest.Server
 	httpHandler     http.HandlerFunc
+	serverDate      string
 )
 
 // Tests to assert end-to-end wiring, including the main function
@@ -55,7 +61,12 @@ var _ = Describe("Integration tests", func() {
 	)
 
 	BeforeEach(func() {
+		serverDate = ""
+		deletedServices = make([]string, 0)
 		httpHandler = http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if serverDate != "" {
+				rw.Header().Set("Date", serverDate)
+			}
 			switch r.URL.Path {
 			case "/v2/info":
 				handleGet(rw, r, getV2Info)
@@ -69,6 +80,8 @@ var _ = Describe("Integration tests", func() {
 				handleGet(rw, r, getServicePlans)
 			case "/v2/service_plans/service-plan-guid-0/service_instances":
 				handleGet(rw, r, getServiceInstances)
+			case "/v2/spaces", "/v2/services/service-guid-1/service_plans", "/v2/service_plans/service-plan-guid-1/service_instances":
+				handleGet(rw, r, getNoResources)
 			default:
 				if r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v2/service_instances") {
 					deletedServices = handleDeleteServiceInstance(deletedServices, rw, r)
@@ -106,6 +119,158 @@ var _ = Describe("Integration tests", func() {
 			Eventually(session, 1*time.Second).Should(Exit(0))
 			Expect(deletedServices).To(ConsistOf("service-plan-instance-guid-0", "service-plan-instance-guid-1"))
 		})
+
+		It("summarises the run", func() {
+			Eventually(session, 1*time.Second).Should(Exit(0))
+			Expect(session).To(Say("Summary: 2 candidates, 2 reaped, 0 skipped, 0 failed, 0 listing errors"))
+		})
+
+		Context("when more service instances would be deleted than the safety cap allows", func() {
+			BeforeEach(func() {
+				args = append([]string{"-max-deletions=1"}, args...)
+			})
+
+			It("exits with a distinct code", func() {
+				Eventually(session, 1*time.Second).Should(Exit(4))
+				Expect(session).To(Say("refusing to delete 2 service instances, more than the maximum of 1"))
+			})
+		})
+
+		Context("when checkpointing to a state directory", func() {
+			var stateDirectory string
+
+			BeforeEach(func() {
+				var err error
+				stateDirectory, err = ioutil.TempDir("", "state")
+				Expect(err).NotTo(HaveOccurred())
+				args = append([]string{"-state-dir=" + stateDirectory}, args...)
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(stateDirectory)
+			})
+
+			It("records the progress of the run", func() {
+				Eventually(session, 1*time.Second).Should(Exit(0))
+				Expect(session).To(Say("Run .* checkpointed to "))
+
+				stateFiles, err := filepath.Glob(filepath.Join(stateDirectory, "*.json"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stateFiles).To(HaveLen(1))
+				contents, err := ioutil.ReadFile(stateFiles[0])
+				Expect(err).NotTo(HaveOccurred())
+				var state checkpoint.State
+				Expect(json.Unmarshal(contents, &state)).To(Succeed())
+				Expect(state.Listed).To(BeTrue())
+				Expect(state.Completed).To(ConsistOf("service-plan-instance-guid-0", "service-plan-instance-guid-1"))
+			})
+
+			Context("when resuming an unknown run", func() {
+				BeforeEach(func() {
+					args = append([]string{"-resume=unknown"}, args...)
+				})
+
+				It("fails with a configuration error", func() {
+					Eventually(session, 1*time.Second).Should(Exit(2))
+					Expect(session).To(Say("cannot read checkpoint"))
+				})
+			})
+		})
+
+		Context("when recording history", func() {
+			var historyDirectory string
+
+			BeforeEach(func() {
+				var err error
+				historyDirectory, err = ioutil.TempDir("", "history")
+				Expect(err).NotTo(HaveOccurred())
+				args = append([]string{"-history=" + filepath.Join(historyDirectory, "history.jsonl")}, args...)
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(historyDirectory)
+			})
+
+			It("summarises the recorded runs with the history command", func() {
+				Eventually(session, 1*time.Second).Should(Exit(0))
+
+				historySession, err := Start(exec.Command(pathToReaper, "history", filepath.Join(historyDirectory, "history.jsonl")), GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(historySession, 1*time.Second).Should(Exit(0))
+				Expect(historySession).To(Say(`Runs by week\n`))
+				Expect(historySession).To(Say(`\d{4}-\d{2}-\d{2}\s+1\s+2\s+2\s+0\n`))
+				Expect(historySession).To(Say(fmt.Sprintf(`%s\s+%s\s+\S+\s+2\n`, serviceName, planName)))
+			})
+		})
+
+		Context("when prices are given", func() {
+			var pricesDirectory string
+
+			BeforeEach(func() {
+				var err error
+				pricesDirectory, err = ioutil.TempDir("", "prices")
+				Expect(err).NotTo(HaveOccurred())
+				pricesFile := filepath.Join(pricesDirectory, "prices.csv")
+				Expect(ioutil.WriteFile(pricesFile, []byte(serviceName+","+planName+",0.5\n"), 0600)).To(Succeed())
+				args = append([]string{"-prices=" + pricesFile}, args...)
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(pricesDirectory)
+			})
+
+			It("reports the cost of the candidates and the savings from reaping them", func() {
+				Eventually(session, 1*time.Second).Should(Exit(0))
+				Expect(session).To(Say(`\(cost \d+\.\d{2} since creation, 365\.00 per month\)`))
+				Expect(session).To(Say(`Cost: \d+\.\d{2} accumulated by candidates since creation, 730\.00 projected monthly savings, 730\.00 realised monthly savings`))
+			})
+		})
+
+		Context("when tracing is enabled", func() {
+			BeforeEach(func() {
+				args = append([]string{"-trace"}, args...)
+			})
+
+			It("logs requests and responses without credentials", func() {
+				Eventually(session, 1*time.Second).Should(Exit(0))
+				Expect(session).To(Say("REQUEST: POST https://.*/uaa/oauth/token"))
+				Expect(session).To(Say(`"access_token":"\[PRIVATE DATA HIDDEN\]"`))
+				Expect(string(session.Out.Contents())).NotTo(ContainSubstring(accessToken))
+				Expect(string(session.Out.Contents())).NotTo(ContainSubstring("password=" + password))
+			})
+		})
+
+		Context("when the local clock differs from the Cloud Controller's by more than the maximum skew", func() {
+			BeforeEach(func() {
+				serverDate = time.Now().Add(-24 * time.Hour).UTC().Format(http.TimeFormat)
+				args = append([]string{"-max-clock-skew=1h"}, args...)
+			})
+
+			It("aborts without reaping", func() {
+				Eventually(session, 1*time.Second).Should(Exit(2))
+				Expect(session).To(Say("Clock skew too large"))
+			})
+		})
+	})
+
+	Describe("Report", func() {
+		BeforeEach(func() {
+			args = []string{
+				"report",
+				"-u", username,
+				"-p", password,
+				"-skip-ssl-validation",
+				"-format=csv",
+				fakeCfApiServer.URL,
+			}
+		})
+
+		It("reports service instances by age without deleting any", func() {
+			Eventually(session, 1*time.Second).Should(Exit(0))
+			Expect(session).To(Say(`organization,space,service,plan,instances,oldest_days,`))
+			Expect(session).To(Say(`,,service-name,service-plan-name-0,2,\d+,`))
+			Expect(deletedServices).To(BeEmpty())
+		})
 	})
 })
 
@@ -162,12 +327,18 @@ func getServices(rw http.ResponseWriter, _ *http.Request) {
 			"metadata": {
 				"guid": "service-guid-0",
 				"created_at": "service-created-at-0"
+			},
+			"entity": {
+				"label": "service-name"
 			}
 		},
 		{
 			"metadata": {
 				"guid": "service-guid-1",
 				"created_at": "service-created-at-1"
+			},
+			"entity": {
+				"label": "other-service-name"
 			}
 		}
 	]
@@ -229,6 +400,10 @@ func getServiceInstances(rw http.ResponseWriter, _ *http.Request) {
 	rw.Write(jsonBytes)
 }
 
+func getNoResources(rw http.ResponseWriter, _ *http.Request) {
+	rw.Write([]byte(`{"resources": []}`))
+}
+
 func handleDeleteServiceInstance(deletedServices []string, rw http.ResponseWriter, r *http.Request) []string {
 	rw.WriteHeader(http.StatusNoContent)
 	return append(deletedServices, path.Base(r.URL.Path))
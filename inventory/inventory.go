@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package inventory
+
+import (
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"time"
+)
+
+// Instance is a managed service instance together with the names of its organization, space, service, and plan.
+type Instance struct {
+	Organization string
+	Space        string
+	Service      string
+	Plan         string
+	Name         string
+	Guid         string
+	Age          time.Duration
+}
+
+// Take lists every managed service instance, measuring its age from its creation until now. It lists everything
+// before returning, so it fails if anything cannot be listed rather than return a partial inventory.
+func Take(cf cloudfoundry.Client, now time.Time) ([]Instance, error) {
+	spaces, err := spaceNames(cf)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := cf.GetAllServices()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list services: %s", err)
+	}
+
+	var instances []Instance
+	for _, service := range services {
+		servicePlans, err := cf.GetServicePlans(service.Metadata.Guid)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list plans of service %s: %s", service.Entity.Label, err)
+		}
+
+		for _, servicePlan := range servicePlans {
+			serviceInstances, serviceInstanceErrors := cf.GetServicePlanInstances(servicePlan.Metadata.Guid)
+			for serviceInstance := range serviceInstances {
+				creationTime, err := time.Parse(time.RFC3339, serviceInstance.Metadata.CreatedAt)
+				if err != nil {
+					drain(serviceInstances)
+					return nil, fmt.Errorf("invalid service instance creation time: %s %s (%s)",
+						serviceInstance.Entity.Name, serviceInstance.Metadata.Guid, err)
+				}
+
+				space := spaces[serviceInstance.Entity.SpaceGuid]
+				instances = append(instances, Instance{
+					Organization: space.organization,
+					Space:        space.name,
+					Service:      service.Entity.Label,
+					Plan:         servicePlan.Entity.Name,
+					Name:         serviceInstance.Entity.Name,
+					Guid:         serviceInstance.Metadata.Guid,
+					Age:          now.Sub(creationTime),
+				})
+			}
+
+			for err := range serviceInstanceErrors {
+				return nil, fmt.Errorf("unable to list instances of plan %s of service %s: %s", servicePlan.Entity.Name, service.Entity.Label, err)
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+type spaceName struct {
+	organization string
+	name         string
+}
+
+// spaceNames lists every space and its organization, fetching each organization only once.
+func spaceNames(cf cloudfoundry.Client) (map[string]spaceName, error) {
+	names := make(map[string]spaceName)
+	organizations := make(map[string]string)
+
+	spaces, spaceErrors := cf.GetSpaces()
+	for space := range spaces {
+		organization, ok := organizations[space.Entity.OrganizationGuid]
+		if !ok {
+			org, err := cf.GetOrganization(space.Entity.OrganizationGuid)
+			if err != nil {
+				drainSpaces(spaces)
+				return nil, fmt.Errorf("unable to get organization: %s (%s)", space.Entity.OrganizationGuid, err)
+			}
+			organization = org.Entity.Name
+			organizations[space.Entity.OrganizationGuid] = organization
+		}
+
+		names[space.Metadata.Guid] = spaceName{organization: organization, name: space.Entity.Name}
+	}
+
+	for err := range spaceErrors {
+		return nil, fmt.Errorf("unable to list spaces: %s", err)
+	}
+
+	return names, nil
+}
+
+func drain(serviceInstances <-chan cloudfoundry.ServiceInstance) {
+	for range serviceInstances {
+	}
+}
+
+func drainSpaces(spaces <-chan cloudfoundry.Space) {
+	for range spaces {
+	}
+}
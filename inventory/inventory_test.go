@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package inventory_test
+
+import (
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry/cloudfoundryfakes"
+	"github.com/pivotal-cf/service-instance-reaper/inventory"
+	"time"
+)
+
+var _ = Describe("Take", func() {
+	var (
+		fakeCfClient *cloudfoundryfakes.FakeClient
+		now          time.Time
+		instances    []inventory.Instance
+		err          error
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+		fakeCfClient = &cloudfoundryfakes.FakeClient{}
+		fakeCfClient.GetSpacesReturns(spaceChannels([]cloudfoundry.Space{
+			{Metadata: cloudfoundry.Metadata{Guid: "space-guid-1"}, Entity: cloudfoundry.SpaceEntity{Name: "dev", OrganizationGuid: "org-guid"}},
+			{Metadata: cloudfoundry.Metadata{Guid: "space-guid-2"}, Entity: cloudfoundry.SpaceEntity{Name: "test", OrganizationGuid: "org-guid"}},
+		}, nil))
+		fakeCfClient.GetOrganizationReturns(cloudfoundry.Organization{Entity: cloudfoundry.OrganizationEntity{Name: "acme"}}, nil)
+		fakeCfClient.GetAllServicesReturns([]cloudfoundry.Service{
+			{Metadata: cloudfoundry.Metadata{Guid: "service-guid"}, Entity: cloudfoundry.ServiceEntity{Label: "db"}},
+		}, nil)
+		fakeCfClient.GetServicePlansReturns([]cloudfoundry.ServicePlan{
+			{Metadata: cloudfoundry.Metadata{Guid: "plan-guid"}, Entity: cloudfoundry.ServicePlanEntity{Name: "small"}},
+		}, nil)
+		fakeCfClient.GetServicePlanInstancesReturns(serviceInstanceChannels([]cloudfoundry.ServiceInstance{
+			{Metadata: cloudfoundry.Metadata{Guid: "guid-1", CreatedAt: "2026-10-17T12:00:00Z"}, Entity: cloudfoundry.ServiceInstanceEntity{Name: "db-1", SpaceGuid: "space-guid-1"}},
+			{Metadata: cloudfoundry.Metadata{Guid: "guid-2", CreatedAt: "2026-10-18T11:00:00Z"}, Entity: cloudfoundry.ServiceInstanceEntity{Name: "db-2", SpaceGuid: "space-guid-2"}},
+		}, nil))
+	})
+
+	JustBeforeEach(func() {
+		instances, err = inventory.Take(fakeCfClient, now)
+	})
+
+	It("lists every service instance with its organization, space, service, plan, and age", func() {
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instances).To(Equal([]inventory.Instance{
+			{Organization: "acme", Space: "dev", Service: "db", Plan: "small", Name: "db-1", Guid: "guid-1", Age: 24 * time.Hour},
+			{Organization: "acme", Space: "test", Service: "db", Plan: "small", Name: "db-2", Guid: "guid-2", Age: time.Hour},
+		}))
+	})
+
+	It("fetches each organization only once", func() {
+		Expect(fakeCfClient.GetOrganizationCallCount()).To(Equal(1))
+		Expect(fakeCfClient.GetOrganizationArgsForCall(0)).To(Equal("org-guid"))
+	})
+
+	It("deletes nothing", func() {
+		Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0))
+	})
+
+	Context("when the services cannot be listed", func() {
+		BeforeEach(func() {
+			fakeCfClient.GetAllServicesReturns(nil, errors.New("test error"))
+		})
+
+		It("fails", func() {
+			Expect(err).To(MatchError("unable to list services: test error"))
+		})
+	})
+
+	Context("when the service instances cannot be listed", func() {
+		BeforeEach(func() {
+			fakeCfClient.GetServicePlanInstancesReturns(serviceInstanceChannels(nil, errors.New("test error")))
+		})
+
+		It("fails", func() {
+			Expect(err).To(MatchError("unable to list instances of plan small of service db: test error"))
+		})
+	})
+
+	Context("when the spaces cannot be listed", func() {
+		BeforeEach(func() {
+			fakeCfClient.GetSpacesReturns(spaceChannels(nil, errors.New("test error")))
+		})
+
+		It("fails", func() {
+			Expect(err).To(MatchError("unable to list spaces: test error"))
+		})
+	})
+
+	Context("when a service instance has an invalid creation time", func() {
+		BeforeEach(func() {
+			fakeCfClient.GetServicePlanInstancesReturns(serviceInstanceChannels([]cloudfoundry.ServiceInstance{
+				{Metadata: cloudfoundry.Metadata{Guid: "guid-1", CreatedAt: "yesterday"}, Entity: cloudfoundry.ServiceInstanceEntity{Name: "db-1"}},
+			}, nil))
+		})
+
+		It("fails", func() {
+			Expect(err).To(MatchError(ContainSubstring("invalid service instance creation time: db-1 guid-1")))
+		})
+	})
+})
+
+func spaceChannels(spaces []cloudfoundry.Space, err error) (chan cloudfoundry.Space, chan error) {
+	spacesChannel := make(chan cloudfoundry.Space, len(spaces))
+	spaceErrorsChannel := make(chan error, 1)
+	defer close(spacesChannel)
+	defer close(spaceErrorsChannel)
+	for _, space := range spaces {
+		spacesChannel <- space
+	}
+	if err != nil {
+		spaceErrorsChannel <- err
+	}
+	return spacesChannel, spaceErrorsChannel
+}
+
+func serviceInstanceChannels(serviceInstances []cloudfoundry.ServiceInstance, err error) (chan cloudfoundry.ServiceInstance, chan error) {
+	serviceInstancesChannel := make(chan cloudfoundry.ServiceInstance, len(serviceInstances))
+	serviceInstanceErrorsChannel := make(chan error, 1)
+	defer close(serviceInstancesChannel)
+	defer close(serviceInstanceErrorsChannel)
+	for _, serviceInstance := range serviceInstances {
+		serviceInstancesChannel <- serviceInstance
+	}
+	if err != nil {
+		serviceInstanceErrorsChannel <- err
+	}
+	return serviceInstancesChannel, serviceInstanceErrorsChannel
+}
@@ -0,0 +1,285 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package inventory
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/pricing"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// Bucket is a range of ages from Min up to, but excluding, Max. A zero Max has no upper bound.
+type Bucket struct {
+	Label string
+	Min   time.Duration
+	Max   time.Duration
+}
+
+// Buckets are the ranges of ages into which service instances are counted.
+var Buckets = []Bucket{
+	{Label: "<1d", Min: 0, Max: day},
+	{Label: "1d-7d", Min: day, Max: 7 * day},
+	{Label: "7d-30d", Min: 7 * day, Max: 30 * day},
+	{Label: "30d-90d", Min: 30 * day, Max: 90 * day},
+	{Label: "90d-1y", Min: 90 * day, Max: 365 * day},
+	{Label: ">=1y", Min: 365 * day},
+}
+
+func (b Bucket) contains(age time.Duration) bool {
+	return age >= b.Min && (b.Max == 0 || age < b.Max)
+}
+
+// Count is the number of service instances in the bucket with the given label.
+type Count struct {
+	Age       string `json:"age"`
+	Instances int    `json:"instances"`
+}
+
+// Cost is the cost service instances have accumulated since their creation and their projected monthly cost, which
+// is what reaping them would save.
+type Cost struct {
+	Accumulated float64 `json:"accumulated"`
+	Monthly     float64 `json:"monthly"`
+}
+
+// Group is the service instances of a plan in a space. Cost is only set if the plan is priced.
+type Group struct {
+	Organization string  `json:"organization"`
+	Space        string  `json:"space"`
+	Service      string  `json:"service"`
+	Plan         string  `json:"plan"`
+	Instances    int     `json:"instances"`
+	OldestDays   int     `json:"oldest_days"`
+	Histogram    []Count `json:"histogram"`
+	Cost         *Cost   `json:"cost,omitempty"`
+}
+
+// Report is an age histogram of service instances, overall and by organization, space, and plan. Cost is only set if
+// the report is priced, in which case it totals the cost of the groups whose plans are priced.
+type Report struct {
+	GeneratedAt string  `json:"generated_at"`
+	Instances   int     `json:"instances"`
+	Histogram   []Count `json:"histogram"`
+	Groups      []Group `json:"groups"`
+	Cost        *Cost   `json:"cost,omitempty"`
+}
+
+// Summarise reports on the given service instances, grouping them by organization, space, service, and plan. If
+// prices are given, it also reports the cost of the service instances whose plans are priced. A service instance
+// which appears to have been created in the future, because of clock skew, is counted as having no age.
+func Summarise(instances []Instance, generatedAt time.Time, prices *pricing.Table) Report {
+	report := Report{
+		GeneratedAt: generatedAt.UTC().Format(time.RFC3339),
+		Instances:   len(instances),
+		Histogram:   histogram(),
+	}
+	if prices != nil {
+		report.Cost = &Cost{}
+	}
+
+	type groupKey struct{ organization, space, service, plan string }
+	groups := make(map[groupKey]*Group)
+	for _, instance := range instances {
+		key := groupKey{instance.Organization, instance.Space, instance.Service, instance.Plan}
+		group, ok := groups[key]
+		if !ok {
+			group = &Group{Organization: instance.Organization, Space: instance.Space, Service: instance.Service, Plan: instance.Plan, Histogram: histogram()}
+			groups[key] = group
+		}
+
+		age := instance.Age
+		if age < 0 {
+			age = 0
+		}
+
+		group.Instances++
+		if days := int(age / day); days > group.OldestDays {
+			group.OldestDays = days
+		}
+		for i, bucket := range Buckets {
+			if bucket.contains(age) {
+				group.Histogram[i].Instances++
+				report.Histogram[i].Instances++
+			}
+		}
+
+		if prices == nil {
+			continue
+		}
+		if accumulated, priced := prices.Cost(instance.Service, instance.Plan, age); priced {
+			monthly, _ := prices.MonthlyCost(instance.Service, instance.Plan)
+			if group.Cost == nil {
+				group.Cost = &Cost{}
+			}
+			group.Cost.Accumulated += accumulated
+			group.Cost.Monthly += monthly
+			report.Cost.Accumulated += accumulated
+			report.Cost.Monthly += monthly
+		}
+	}
+
+	for _, group := range groups {
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.Organization != b.Organization {
+			return a.Organization < b.Organization
+		}
+		if a.Space != b.Space {
+			return a.Space < b.Space
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Plan < b.Plan
+	})
+
+	return report
+}
+
+func histogram() []Count {
+	counts := make([]Count, len(Buckets))
+	for i, bucket := range Buckets {
+		counts[i].Age = bucket.Label
+	}
+	return counts
+}
+
+// Format determines how a report is written.
+type Format string
+
+const (
+	// Table writes the overall histogram and then the groups as aligned columns.
+	Table Format = "table"
+
+	// JSON writes the whole report as a single JSON object.
+	JSON Format = "json"
+
+	// CSV writes a row for each group, with a column for each bucket of the histogram.
+	CSV Format = "csv"
+)
+
+func ParseFormat(format string) (Format, error) {
+	switch Format(format) {
+	case Table, JSON, CSV:
+		return Format(format), nil
+	default:
+		return Table, fmt.Errorf("invalid report format: %s", format)
+	}
+}
+
+// Write writes the report in the given format.
+func (r Report) Write(output io.Writer, format Format) error {
+	switch format {
+	case JSON:
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case CSV:
+		return r.writeCSV(output)
+	default:
+		return r.writeTable(output)
+	}
+}
+
+func (r Report) writeTable(output io.Writer) error {
+	table := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(table, "%d service instances at %s\n", r.Instances, r.GeneratedAt)
+	if r.Cost != nil {
+		fmt.Fprintf(table, "Cost: %.2f accumulated since creation, %.2f per month\n", r.Cost.Accumulated, r.Cost.Monthly)
+	}
+	fmt.Fprintln(table, "\nAGE\tINSTANCES")
+	for _, count := range r.Histogram {
+		fmt.Fprintf(table, "%s\t%d\n", count.Age, count.Instances)
+	}
+
+	fmt.Fprint(table, "\nORGANIZATION\tSPACE\tSERVICE\tPLAN\tINSTANCES\tOLDEST (DAYS)")
+	for _, bucket := range Buckets {
+		fmt.Fprintf(table, "\t%s", bucket.Label)
+	}
+	if r.Cost != nil {
+		fmt.Fprint(table, "\tACCUMULATED COST\tMONTHLY COST")
+	}
+	fmt.Fprintln(table)
+	for _, group := range r.Groups {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%d\t%d", orNone(group.Organization), orNone(group.Space), group.Service, group.Plan,
+			group.Instances, group.OldestDays)
+		for _, count := range group.Histogram {
+			fmt.Fprintf(table, "\t%d", count.Instances)
+		}
+		if r.Cost != nil {
+			accumulated, monthly := group.costColumns()
+			fmt.Fprintf(table, "\t%s\t%s", orNone(accumulated), orNone(monthly))
+		}
+		fmt.Fprintln(table)
+	}
+
+	return table.Flush()
+}
+
+func (r Report) writeCSV(output io.Writer) error {
+	writer := csv.NewWriter(output)
+
+	header := []string{"organization", "space", "service", "plan", "instances", "oldest_days"}
+	for _, bucket := range Buckets {
+		header = append(header, bucket.Label)
+	}
+	if r.Cost != nil {
+		header = append(header, "accumulated_cost", "monthly_cost")
+	}
+	writer.Write(header)
+
+	for _, group := range r.Groups {
+		row := []string{group.Organization, group.Space, group.Service, group.Plan, strconv.Itoa(group.Instances), strconv.Itoa(group.OldestDays)}
+		for _, count := range group.Histogram {
+			row = append(row, strconv.Itoa(count.Instances))
+		}
+		if r.Cost != nil {
+			accumulated, monthly := group.costColumns()
+			row = append(row, accumulated, monthly)
+		}
+		writer.Write(row)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// costColumns formats the cost of the group, leaving it empty if the plan is not priced.
+func (g Group) costColumns() (accumulated string, monthly string) {
+	if g.Cost == nil {
+		return "", ""
+	}
+	return fmt.Sprintf("%.2f", g.Cost.Accumulated), fmt.Sprintf("%.2f", g.Cost.Monthly)
+}
+
+func orNone(name string) string {
+	if name == "" {
+		return "-"
+	}
+	return name
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package inventory_test
+
+import (
+	"bytes"
+	"encoding/json"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/service-instance-reaper/inventory"
+	"github.com/pivotal-cf/service-instance-reaper/pricing"
+	"time"
+)
+
+var _ = Describe("Report", func() {
+	const day = 24 * time.Hour
+
+	var (
+		report inventory.Report
+		prices *pricing.Table
+		output *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		prices = nil
+		output = &bytes.Buffer{}
+	})
+
+	JustBeforeEach(func() {
+		report = inventory.Summarise([]inventory.Instance{
+			{Organization: "acme", Space: "test", Service: "db", Plan: "small", Age: time.Hour},
+			{Organization: "acme", Space: "dev", Service: "db", Plan: "small", Age: 3 * day},
+			{Organization: "acme", Space: "dev", Service: "db", Plan: "small", Age: 400 * day},
+			{Organization: "acme", Space: "dev", Service: "cache", Plan: "large", Age: 7 * day},
+		}, time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC), prices)
+	})
+
+	It("counts service instances by age", func() {
+		Expect(report.Instances).To(Equal(4))
+		Expect(report.Histogram).To(Equal([]inventory.Count{
+			{Age: "<1d", Instances: 1},
+			{Age: "1d-7d", Instances: 1},
+			{Age: "7d-30d", Instances: 1},
+			{Age: "30d-90d", Instances: 0},
+			{Age: "90d-1y", Instances: 0},
+			{Age: ">=1y", Instances: 1},
+		}))
+	})
+
+	It("counts service instances which appear to be created in the future as less than a day old", func() {
+		report := inventory.Summarise([]inventory.Instance{
+			{Organization: "acme", Space: "test", Service: "db", Plan: "small", Age: -time.Minute},
+		}, time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC), nil)
+
+		Expect(report.Instances).To(Equal(1))
+		Expect(report.Histogram[0]).To(Equal(inventory.Count{Age: "<1d", Instances: 1}))
+		Expect(report.Groups[0].Histogram[0]).To(Equal(inventory.Count{Age: "<1d", Instances: 1}))
+	})
+
+	It("groups service instances by organization, space, service, and plan", func() {
+		Expect(report.Groups).To(HaveLen(3))
+		Expect(report.Groups[0].Service).To(Equal("cache"))
+		group := report.Groups[1]
+		Expect(group.Space).To(Equal("dev"))
+		Expect(group.Service).To(Equal("db"))
+		Expect(group.Instances).To(Equal(2))
+		Expect(group.OldestDays).To(Equal(400))
+		Expect(group.Histogram[1]).To(Equal(inventory.Count{Age: "1d-7d", Instances: 1}))
+		Expect(group.Histogram[5]).To(Equal(inventory.Count{Age: ">=1y", Instances: 1}))
+		Expect(report.Groups[2].Space).To(Equal("test"))
+	})
+
+	It("writes a table", func() {
+		Expect(report.Write(output, inventory.Table)).To(Succeed())
+		Expect(output.String()).To(ContainSubstring("4 service instances at 2026-10-18T12:00:00Z"))
+		Expect(output.String()).To(ContainSubstring(">=1y     1\n"))
+		Expect(output.String()).To(ContainSubstring("acme          dev    db       small  2          400            0    1      0       0        0       1\n"))
+	})
+
+	It("writes JSON", func() {
+		Expect(report.Write(output, inventory.JSON)).To(Succeed())
+		var written inventory.Report
+		Expect(json.Unmarshal(output.Bytes(), &written)).To(Succeed())
+		Expect(written).To(Equal(report))
+	})
+
+	It("writes CSV", func() {
+		Expect(report.Write(output, inventory.CSV)).To(Succeed())
+		Expect(output.String()).To(Equal(`organization,space,service,plan,instances,oldest_days,<1d,1d-7d,7d-30d,30d-90d,90d-1y,>=1y
+acme,dev,cache,large,1,7,0,0,1,0,0,0
+acme,dev,db,small,2,400,0,1,0,0,0,1
+acme,test,db,small,1,0,1,0,0,0,0,0
+`))
+	})
+
+	It("reports no cost", func() {
+		Expect(report.Cost).To(BeNil())
+		Expect(report.Groups[0].Cost).To(BeNil())
+	})
+
+	Context("when prices are given", func() {
+		BeforeEach(func() {
+			prices = pricing.NewTable(pricing.Price{Service: "db", Plan: "small", HourlyCost: 0.5})
+		})
+
+		It("reports the cost of the service instances whose plans are priced", func() {
+			Expect(report.Cost).To(Equal(&inventory.Cost{Accumulated: 0.5 + 36 + 4800, Monthly: 3 * 365}))
+			Expect(report.Groups[0].Cost).To(BeNil())
+			Expect(report.Groups[1].Cost).To(Equal(&inventory.Cost{Accumulated: 36 + 4800, Monthly: 2 * 365}))
+			Expect(report.Groups[2].Cost).To(Equal(&inventory.Cost{Accumulated: 0.5, Monthly: 365}))
+		})
+
+		It("writes the cost in a table", func() {
+			Expect(report.Write(output, inventory.Table)).To(Succeed())
+			Expect(output.String()).To(ContainSubstring("Cost: 4836.50 accumulated since creation, 1095.00 per month\n"))
+			Expect(output.String()).To(ContainSubstring("ACCUMULATED COST  MONTHLY COST\n"))
+			Expect(output.String()).To(MatchRegexp(`acme +dev +cache +large( +\d+)+ +- +-\n`))
+			Expect(output.String()).To(MatchRegexp(`acme +dev +db +small( +\d+)+ +4836.00 +730.00\n`))
+		})
+
+		It("writes the cost as CSV", func() {
+			Expect(report.Write(output, inventory.CSV)).To(Succeed())
+			Expect(output.String()).To(Equal(`organization,space,service,plan,instances,oldest_days,<1d,1d-7d,7d-30d,30d-90d,90d-1y,>=1y,accumulated_cost,monthly_cost
+acme,dev,cache,large,1,7,0,0,1,0,0,0,,
+acme,dev,db,small,2,400,0,1,0,0,0,1,4836.00,730.00
+acme,test,db,small,1,0,1,0,0,0,0,0,0.50,365.00
+`))
+		})
+	})
+
+	Describe("formats", func() {
+		It("parses the supported formats", func() {
+			Expect(inventory.ParseFormat("csv")).To(Equal(inventory.CSV))
+			_, err := inventory.ParseFormat("xml")
+			Expect(err).To(MatchError("invalid report format: xml"))
+		})
+	})
+})
@@ -0,0 +1,167 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log message. Messages below a logger's level are discarded.
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+var levelNames = map[Level]string{
+	Debug: "debug",
+	Info:  "info",
+	Warn:  "warn",
+	Error: "error",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+func ParseLevel(level string) (Level, error) {
+	for candidate, name := range levelNames {
+		if strings.ToLower(level) == name {
+			return candidate, nil
+		}
+	}
+	return Info, fmt.Errorf("invalid log level: %s", level)
+}
+
+// Format determines how a logger writes messages.
+type Format string
+
+const (
+	// Text writes each message on a line of its own, prefixed by its level unless it is informational. Fields are
+	// omitted, so the text of each message must stand alone.
+	Text Format = "text"
+
+	// JSON writes each message as a JSON object, one per line, with its time, level, message, and fields.
+	JSON Format = "json"
+)
+
+func ParseFormat(format string) (Format, error) {
+	switch Format(format) {
+	case Text, JSON:
+		return Format(format), nil
+	default:
+		return Text, fmt.Errorf("invalid log format: %s", format)
+	}
+}
+
+// Logger writes leveled messages, each with optional fields given as alternating keys and values. It is safe for
+// concurrent use.
+type Logger struct {
+	output      io.Writer
+	level       Level
+	format      Format
+	currentTime func() time.Time
+	mutex       sync.Mutex
+}
+
+func New(output io.Writer, level Level, format Format) *Logger {
+	return &Logger{
+		output:      output,
+		level:       level,
+		format:      format,
+		currentTime: time.Now,
+	}
+}
+
+// Enabled reports whether messages at the given level are written.
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.level
+}
+
+func (l *Logger) Debug(message string, keysAndValues ...interface{}) {
+	l.log(Debug, message, keysAndValues)
+}
+
+func (l *Logger) Info(message string, keysAndValues ...interface{}) {
+	l.log(Info, message, keysAndValues)
+}
+
+func (l *Logger) Warn(message string, keysAndValues ...interface{}) {
+	l.log(Warn, message, keysAndValues)
+}
+
+func (l *Logger) Error(message string, keysAndValues ...interface{}) {
+	l.log(Error, message, keysAndValues)
+}
+
+func (l *Logger) log(level Level, message string, keysAndValues []interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	message = strings.TrimSuffix(message, "\n")
+
+	var line string
+	if l.format == JSON {
+		line = l.jsonLine(level, message, keysAndValues)
+	} else if level == Info {
+		line = message
+	} else {
+		line = fmt.Sprintf("%s: %s", strings.ToUpper(level.String()), message)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	fmt.Fprintln(l.output, line)
+}
+
+// Pause holds back all messages while the given function runs, so that output it writes to the terminal, such as an
+// interactive prompt, is not interleaved with them. Messages logged meanwhile are written once it returns.
+func (l *Logger) Pause(f func()) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	f()
+}
+
+func (l *Logger) jsonLine(level Level, message string, keysAndValues []interface{}) string {
+	entry := map[string]interface{}{
+		"time":    l.currentTime().UTC().Format(time.RFC3339Nano),
+		"level":   level.String(),
+		"message": message,
+	}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key := fmt.Sprint(keysAndValues[i])
+		if value, ok := keysAndValues[i+1].(error); ok {
+			entry[key] = value.Error()
+		} else {
+			entry[key] = keysAndValues[i+1]
+		}
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Sprintf(`{"level":"error","message":"unable to marshal log message: %s"}`, err)
+	}
+	return string(line)
+}
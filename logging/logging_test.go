@@ -0,0 +1,142 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package logging_test
+
+import (
+	"encoding/json"
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/service-instance-reaper/logging"
+	"strings"
+)
+
+var _ = Describe("Logger", func() {
+	var (
+		output *gbytes.Buffer
+		level  logging.Level
+		format logging.Format
+		logger *logging.Logger
+	)
+
+	BeforeEach(func() {
+		output = gbytes.NewBuffer()
+		level = logging.Info
+		format = logging.Text
+	})
+
+	JustBeforeEach(func() {
+		logger = logging.New(output, level, format)
+	})
+
+	Context("in text format", func() {
+		It("writes informational messages alone", func() {
+			logger.Info("name guid", "service_instance_guid", "guid")
+			Expect(string(output.Contents())).To(Equal("name guid\n"))
+		})
+
+		It("prefixes other messages with their level", func() {
+			logger.Warn("careful")
+			logger.Error("unable to delete service instance: name guid (failed)\n")
+			Expect(string(output.Contents())).To(Equal("WARN: careful\nERROR: unable to delete service instance: name guid (failed)\n"))
+		})
+
+		It("discards messages below its level", func() {
+			logger.Debug("noise")
+			Expect(output.Contents()).To(BeEmpty())
+			Expect(logger.Enabled(logging.Debug)).To(BeFalse())
+			Expect(logger.Enabled(logging.Error)).To(BeTrue())
+		})
+
+		Context("at debug level", func() {
+			BeforeEach(func() {
+				level = logging.Debug
+			})
+
+			It("writes debug messages", func() {
+				logger.Debug("detail")
+				Expect(string(output.Contents())).To(Equal("DEBUG: detail\n"))
+			})
+		})
+	})
+
+	Describe("Pause", func() {
+		It("holds back messages until the paused function returns", func() {
+			logged := make(chan struct{})
+			logger.Pause(func() {
+				go func() {
+					logger.Info("meanwhile")
+					close(logged)
+				}()
+				Consistently(logged).ShouldNot(BeClosed())
+				output.Write([]byte("Delete? "))
+			})
+
+			Eventually(logged).Should(BeClosed())
+			Expect(string(output.Contents())).To(Equal("Delete? meanwhile\n"))
+		})
+	})
+
+	Context("in JSON format", func() {
+		BeforeEach(func() {
+			format = logging.JSON
+		})
+
+		It("writes each message as a JSON object with its fields", func() {
+			logger.Info("name guid", "service_instance_guid", "guid", "reaped", true)
+			logger.Error("failed", "error", errors.New("broken"))
+
+			lines := strings.Split(strings.TrimSpace(string(output.Contents())), "\n")
+			Expect(lines).To(HaveLen(2))
+
+			var entry map[string]interface{}
+			Expect(json.Unmarshal([]byte(lines[0]), &entry)).To(Succeed())
+			Expect(entry).To(HaveKey("time"))
+			Expect(entry["level"]).To(Equal("info"))
+			Expect(entry["message"]).To(Equal("name guid"))
+			Expect(entry["service_instance_guid"]).To(Equal("guid"))
+			Expect(entry["reaped"]).To(Equal(true))
+
+			Expect(json.Unmarshal([]byte(lines[1]), &entry)).To(Succeed())
+			Expect(entry["level"]).To(Equal("error"))
+			Expect(entry["error"]).To(Equal("broken"))
+		})
+	})
+
+	Describe("ParseLevel", func() {
+		It("parses each level", func() {
+			for _, expected := range []logging.Level{logging.Debug, logging.Info, logging.Warn, logging.Error} {
+				parsed, err := logging.ParseLevel(strings.ToUpper(expected.String()))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parsed).To(Equal(expected))
+			}
+		})
+
+		It("rejects unknown levels", func() {
+			_, err := logging.ParseLevel("verbose")
+			Expect(err).To(MatchError("invalid log level: verbose"))
+		})
+	})
+
+	Describe("ParseFormat", func() {
+		It("rejects unknown formats", func() {
+			_, err := logging.ParseFormat("xml")
+			Expect(err).To(MatchError("invalid log format: xml"))
+		})
+	})
+})
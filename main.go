@@ -18,47 +18,409 @@ package main
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"github.com/hako/durafmt"
 	"github.com/pivotal-cf/service-instance-reaper/arg"
+	"github.com/pivotal-cf/service-instance-reaper/audit"
+	"github.com/pivotal-cf/service-instance-reaper/calendar"
+	"github.com/pivotal-cf/service-instance-reaper/checkpoint"
 	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"github.com/pivotal-cf/service-instance-reaper/confirm"
+	"github.com/pivotal-cf/service-instance-reaper/history"
 	"github.com/pivotal-cf/service-instance-reaper/httpclient"
+	"github.com/pivotal-cf/service-instance-reaper/inventory"
+	"github.com/pivotal-cf/service-instance-reaper/logging"
+	"github.com/pivotal-cf/service-instance-reaper/pricing"
 	reaperpkg "github.com/pivotal-cf/service-instance-reaper/reaper"
+	"github.com/pivotal-cf/service-instance-reaper/snapshot"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// cascadeRetryInterval is the time to wait before reattempting a deletion made by -recursive, giving any
+// asynchronous operation on the same resource a chance to complete.
+const cascadeRetryInterval = 5 * time.Second
+
+// clockSkewWarningThreshold is the difference between the local clock and the Cloud Controller's beyond which a
+// warning is printed. The Cloud Controller's Date header has a resolution of one second.
+const clockSkewWarningThreshold = 5 * time.Second
+
+// logger is used for all output once the arguments have been parsed.
+var logger *logging.Logger
+
 func main() {
-	username, password, skipSslValidation, reap, recursive, apiUrl, serviceName, planName, expiryInterval := arg.Parse(os.Args, os.Stdout, os.Exit)
+	arguments := arg.Parse(os.Args, os.Stdout, os.Exit)
+	if os.Getenv("CF_TRACE") == "true" {
+		arguments.Trace = true
+		arguments.LogLevel = logging.Debug
+	}
+	logOutput := os.Stdout
+	if arguments.Command == arg.ReportCommand {
+		logOutput = os.Stderr
+	}
+	logger = logging.New(logOutput, arguments.LogLevel, arguments.LogFormat)
+
+	switch arguments.Command {
+	case arg.RestoreCommand:
+		restore(arguments)
+	case arg.HistoryCommand:
+		showHistory(arguments)
+	case arg.ReportCommand:
+		report(arguments)
+	default:
+		reap(arguments)
+	}
+}
+
+func reap(arguments arg.Arguments) {
+	started := time.Now()
+	runID := arguments.ResumeRunID
+	if runID == "" {
+		runID = checkpoint.NewRunID(started)
+	}
 
-	if !reap {
-		fmt.Printf("DRY RUN ONLY!\n")
+	if !arguments.Reap {
+		logger.Warn("DRY RUN ONLY!")
 	}
 
-	fmt.Printf("Reaping instances of the '%s' plan of '%s' older than %s in %s as %s...\n", planName, serviceName, durafmt.Parse(expiryInterval), apiUrl, username)
+	if arguments.Apps {
+		logger.Info(fmt.Sprintf("Reaping apps %s in %s as %s...", age(arguments), arguments.ApiUrl, arguments.Username))
+	} else if arguments.UserProvided {
+		logger.Info(fmt.Sprintf("Reaping user-provided service instances %s in %s as %s...", age(arguments), arguments.ApiUrl, arguments.Username))
+	} else {
+		logger.Info(fmt.Sprintf("Reaping instances of the '%s' plan of '%s' %s in %s as %s...", arguments.PlanName, arguments.ServiceName, age(arguments), arguments.ApiUrl, arguments.Username))
+	}
+
+	client := httpClient(arguments)
+	cf := login(client, arguments)
+	reaper := reaperpkg.NewReaper(cf, serverClock(client, arguments), logger)
+
+	options := reaperpkg.Options{
+		ServiceName:    arguments.ServiceName,
+		PlanName:       arguments.PlanName,
+		ExpiryInterval: arguments.ExpiryInterval,
+		Cutoff:         arguments.Cutoff,
+		UserProvided:   arguments.UserProvided,
+		NamePattern:    arguments.NamePattern,
+		SpaceGuid:      arguments.SpaceGuid,
+		ProtectionTag:  arguments.ProtectionTag,
+		Reap:           arguments.Reap,
+		MaxDeletions:   arguments.MaxDeletions,
+		FailFast:       arguments.FailFast,
+		AgeBasis:       arguments.AgeBasis,
+
+		Recursive:            arguments.Recursive,
+		CascadeAttempts:      arguments.CascadeAttempts,
+		CascadeRetryInterval: cascadeRetryInterval,
+
+		UnboundOnly:        arguments.UnboundOnly,
+		WithoutServiceKeys: arguments.WithoutServiceKeys,
+
+		LastOperationStates: arguments.LastOperationStates,
+		Purge:               arguments.Purge,
+		PurgeOrphans:        arguments.PurgeOrphans,
+
+		QuotaThreshold: arguments.QuotaThreshold,
+		QuotaTarget:    arguments.QuotaTarget,
+
+		KeepNewest:   arguments.KeepNewest,
+		KeepGrouping: arguments.KeepGrouping,
+
+		ServiceKeyExpiryInterval: arguments.ServiceKeyExpiryInterval,
+		ServiceKeyNamePattern:    arguments.ServiceKeyNamePattern,
+
+		Apps:         arguments.Apps,
+		AppStates:    arguments.AppStates,
+		DeleteRoutes: arguments.DeleteRoutes,
+
+		EmptySpaceNamePattern:    arguments.EmptySpaceNamePattern,
+		EmptySpaceExpiryInterval: arguments.EmptySpaceExpiryInterval,
+	}
+	if arguments.BusinessDays {
+		var holidays []time.Time
+		if arguments.HolidaysFile != "" {
+			var err error
+			holidays, err = calendar.LoadHolidays(arguments.HolidaysFile)
+			if err != nil {
+				fatalError(arg.ExitConfigurationError, "Failed", err)
+			}
+		}
+		options.Calendar = calendar.New(arguments.Timezone, holidays)
+	}
+	options.Prices = loadPrices(arguments)
+	if len(arguments.Windows) > 0 || len(arguments.Blackouts) > 0 {
+		options.Schedule = calendar.NewSchedule(arguments.Timezone, arguments.Windows, arguments.Blackouts)
+	}
+	if arguments.Interactive {
+		if !isTerminal(os.Stdin) {
+			fatalError(arg.ExitConfigurationError, "Aborting", errors.New("-interactive requires a terminal"))
+		}
+		// Prompt on stderr, so that questions reach the terminal even if the log is redirected
+		options.Confirmer = confirm.NewPrompt(os.Stdin, os.Stderr)
+		options.ConfirmBatch = arguments.InteractiveBatch
+	}
+	if arguments.AuditLog != "" {
+		options.AuditTrail = audit.NewTrail(arguments.AuditLog)
+	}
+	if arguments.SnapshotDirectory != "" {
+		options.Archive = snapshot.NewArchive(arguments.SnapshotDirectory)
+	}
+	if arguments.StateDirectory != "" {
+		options.Checkpoint, options.Resume = runCheckpoint(arguments, runID)
+	}
+	var recorder *history.RunRecorder
+	if arguments.HistoryFile != "" {
+		recorder = &history.RunRecorder{}
+		options.History = recorder
+	}
+
+	summary, err := reaper.Reap(options)
+	if recorder != nil {
+		saveHistory(arguments, runID, started, summary, recorder.Deletions())
+	}
+	if err != nil {
+		fatalError(exitCode(err), "Failed", err)
+	}
+}
+
+// runCheckpoint resumes the checkpoint of the run being resumed or else starts a checkpoint for a new run.
+func runCheckpoint(arguments arg.Arguments, runID string) (checkpoint.Checkpoint, bool) {
+	if arguments.ResumeRunID != "" {
+		runCheckpoint, err := checkpoint.Load(arguments.StateDirectory, runID)
+		if err != nil {
+			fatalError(arg.ExitConfigurationError, "Unable to resume", err)
+		}
+		if differences := runCheckpoint.State().Selection.Differences(selection(arguments)); len(differences) > 0 {
+			fatalError(arg.ExitConfigurationError, "Unable to resume",
+				fmt.Errorf("run %s chose its candidates differently: %s", runID, strings.Join(differences, ", ")))
+		}
+		logger.Info(fmt.Sprintf("Resuming run %s", runID), "run_id", runID)
+		return runCheckpoint, true
+	}
+
+	runCheckpoint, err := checkpoint.New(arguments.StateDirectory, runID, selection(arguments))
+	if err != nil {
+		fatalError(arg.ExitConfigurationError, "Unable to checkpoint", err)
+	}
+	logger.Info(fmt.Sprintf("Run %s checkpointed to %s", runID, checkpoint.Path(arguments.StateDirectory, runID)), "run_id", runID)
+	return runCheckpoint, false
+}
+
+// selection describes the arguments by which a run chooses its candidates, which a resumed run must share.
+func selection(arguments arg.Arguments) checkpoint.Selection {
+	selection := checkpoint.Selection{
+		"service":              arguments.ServiceName,
+		"plan":                 arguments.PlanName,
+		"age":                  arguments.ExpiryInterval.String(),
+		"age_basis":            string(arguments.AgeBasis),
+		"business_days":        strconv.FormatBool(arguments.BusinessDays),
+		"holidays":             arguments.HolidaysFile,
+		"timezone":             arguments.Timezone.String(),
+		"reap":                 strconv.FormatBool(arguments.Reap),
+		"max_deletions":        strconv.Itoa(arguments.MaxDeletions),
+		"user_provided":        strconv.FormatBool(arguments.UserProvided),
+		"apps":                 strconv.FormatBool(arguments.Apps),
+		"app_state":            strings.Join(arguments.AppStates, ","),
+		"space_guid":           arguments.SpaceGuid,
+		"protect_tag":          arguments.ProtectionTag,
+		"unbound_only":         strconv.FormatBool(arguments.UnboundOnly),
+		"without_service_keys": strconv.FormatBool(arguments.WithoutServiceKeys),
+		"last_operation_state": strings.Join(arguments.LastOperationStates, ","),
+		"quota_threshold":      strconv.Itoa(arguments.QuotaThreshold),
+		"quota_target":         strconv.Itoa(arguments.QuotaTarget),
+		"keep_newest":          strconv.Itoa(arguments.KeepNewest),
+		"keep_group_by":        string(arguments.KeepGrouping),
+		"service_key_age":      arguments.ServiceKeyExpiryInterval.String(),
+		"empty_space_age":      arguments.EmptySpaceExpiryInterval.String(),
+	}
+	if !arguments.Cutoff.IsZero() {
+		selection["cutoff"] = arguments.Cutoff.Format(time.RFC3339)
+	}
+	if arguments.NamePattern != nil {
+		selection["name_pattern"] = arguments.NamePattern.String()
+	}
+	if arguments.ServiceKeyNamePattern != nil {
+		selection["service_key_name_pattern"] = arguments.ServiceKeyNamePattern.String()
+	}
+	if arguments.EmptySpaceNamePattern != nil {
+		selection["empty_space_name_pattern"] = arguments.EmptySpaceNamePattern.String()
+	}
+	return selection
+}
+
+// saveHistory adds the results of the run to the history. A failure to do so is logged but does not fail the run.
+func saveHistory(arguments arg.Arguments, runID string, started time.Time, summary reaperpkg.Summary, deletions []history.Deletion) {
+	run := history.Run{
+		ID:            runID,
+		Started:       started.UTC().Format(time.RFC3339),
+		DryRun:        !arguments.Reap,
+		ServiceName:   arguments.ServiceName,
+		PlanName:      arguments.PlanName,
+		Candidates:    summary.Candidates,
+		Reaped:        summary.Reaped,
+		Skipped:       summary.Skipped,
+		Failed:        summary.Failed,
+		ListingErrors: summary.ListingErrors,
+		Deletions:     deletions,
+	}
+	if err := history.NewStore(arguments.HistoryFile).Save(run); err != nil {
+		logger.Error(fmt.Sprintf("Unable to save history: %s", err))
+	}
+}
+
+// exitCode distinguishes the most significant way in which reaping failed.
+func exitCode(err error) int {
+	var reaperErrors reaperpkg.Errors
+	if !errors.As(err, &reaperErrors) {
+		return arg.ExitFailure
+	}
 
+	switch {
+	case len(reaperErrors.OfKind(reaperpkg.SafetyCapError)) > 0:
+		return arg.ExitSafetyCapExceeded
+	case len(reaperErrors.OfKind(reaperpkg.ListingError, reaperpkg.ParseError)) > 0:
+		return arg.ExitListingError
+	default:
+		return arg.ExitFailure
+	}
+}
+
+// age describes the age beyond which resources are reaped.
+func age(arguments arg.Arguments) string {
+	if !arguments.Cutoff.IsZero() {
+		return fmt.Sprintf("whose %s was before %s", arguments.AgeBasis.Description(), arguments.Cutoff.Format(time.RFC3339))
+	}
+	if arguments.BusinessDays {
+		return fmt.Sprintf("older than %s of business days since their %s", durafmt.Parse(arguments.ExpiryInterval), arguments.AgeBasis.Description())
+	}
+	return fmt.Sprintf("older than %s since their %s", durafmt.Parse(arguments.ExpiryInterval), arguments.AgeBasis.Description())
+}
+
+func restore(arguments arg.Arguments) {
+	serviceInstanceSnapshot, err := snapshot.Load(arguments.SnapshotFile)
+	if err != nil {
+		fatalError(arg.ExitConfigurationError, "Failed", err)
+	}
+
+	logger.Info(fmt.Sprintf("Restoring service instance '%s' in %s as %s...", serviceInstanceSnapshot.ServiceInstance.Entity.Name, arguments.ApiUrl, arguments.Username))
+
+	if serviceInstanceSnapshot.ParametersError != "" && arguments.Force {
+		logger.Warn(fmt.Sprintf("Restoring without parameters, which the snapshot did not record: %s", serviceInstanceSnapshot.ParametersError))
+	}
+
+	serviceInstance, err := snapshot.Restore(login(httpClient(arguments), arguments), serviceInstanceSnapshot, arguments.Force)
+	if err != nil {
+		fatalError(arg.ExitFailure, "Failed", err)
+	}
+
+	logger.Info(fmt.Sprintf("%s %s", serviceInstance.Entity.Name, serviceInstance.Metadata.Guid),
+		"service_instance_name", serviceInstance.Entity.Name, "service_instance_guid", serviceInstance.Metadata.Guid)
+}
+
+func showHistory(arguments arg.Arguments) {
+	runs, err := history.NewStore(arguments.HistoryFile).Runs()
+	if err != nil {
+		fatalError(arg.ExitConfigurationError, "Failed", err)
+	}
+
+	report, err := history.Summarise(runs, history.StartOfWeeks(time.Now(), arguments.HistoryWeeks), arguments.HistoryTop)
+	if err != nil {
+		fatalError(arg.ExitFailure, "Failed", err)
+	}
+
+	if err := report.Write(os.Stdout); err != nil {
+		fatalError(arg.ExitFailure, "Failed", err)
+	}
+}
+
+func report(arguments arg.Arguments) {
+	logger.Info(fmt.Sprintf("Reporting service instances in %s as %s...", arguments.ApiUrl, arguments.Username))
+	prices := loadPrices(arguments)
+
+	client := httpClient(arguments)
+	cf := login(client, arguments)
+	now := serverClock(client, arguments)()
+
+	instances, err := inventory.Take(cf, now)
+	if err != nil {
+		fatalError(arg.ExitListingError, "Failed", err)
+	}
+
+	if err := inventory.Summarise(instances, now, prices).Write(os.Stdout, arguments.ReportFormat); err != nil {
+		fatalError(arg.ExitFailure, "Failed", err)
+	}
+}
+
+// loadPrices returns the price table given by the arguments, if any.
+func loadPrices(arguments arg.Arguments) *pricing.Table {
+	if arguments.PricesFile == "" {
+		return nil
+	}
+
+	prices, err := pricing.Load(arguments.PricesFile)
+	if err != nil {
+		fatalError(arg.ExitConfigurationError, "Failed", err)
+	}
+	return prices
+}
+
+func httpClient(arguments arg.Arguments) httpclient.HttpClient {
 	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: skipSslValidation},
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: arguments.SkipSslValidation},
 	}
 	client := &http.Client{Transport: transport}
+	if arguments.Trace {
+		return httpclient.NewTracingClient(client, logger)
+	}
+	return client
+}
 
-	accessToken, err := cloudfoundry.GetOauthToken(client, apiUrl, username, password)
+func login(client httpclient.HttpClient, arguments arg.Arguments) cloudfoundry.Client {
+	accessToken, err := cloudfoundry.GetOauthToken(client, arguments.ApiUrl, arguments.Username, arguments.Password)
 	if err != nil {
-		fatalError("Authentication failed", err)
+		fatalError(arg.ExitConfigurationError, "Authentication failed", err)
 	}
 
 	authClient := httpclient.NewAuthenticatedClient(client)
-	cf := cloudfoundry.NewClient(authClient, apiUrl, accessToken)
-	reaper := reaperpkg.NewReaper(cf, func() time.Time { return time.Now().UTC() }, os.Stdout)
+	return cloudfoundry.NewClient(authClient, arguments.ApiUrl, accessToken)
+}
 
-	err = reaper.Reap(serviceName, planName, expiryInterval, reap, recursive)
+// serverClock returns a clock which follows the Cloud Controller's clock rather than the local one, so that a skewed
+// local clock cannot make resources appear older than they are.
+func serverClock(client httpclient.HttpClient, arguments arg.Arguments) func() time.Time {
+	serverTime, err := cloudfoundry.GetServerTime(client, arguments.ApiUrl)
 	if err != nil {
-		fatalError("Failed", err)
+		fatalError(arg.ExitConfigurationError, "Unable to determine the Cloud Controller's time", err)
+	}
+
+	skew := serverTime.Sub(time.Now())
+	magnitude := skew
+	if magnitude < 0 {
+		magnitude = -magnitude
 	}
+	if arguments.MaxClockSkew != 0 && magnitude > arguments.MaxClockSkew {
+		fatalError(arg.ExitConfigurationError, "Clock skew too large", fmt.Errorf("the local clock differs from the Cloud Controller's by %s", magnitude))
+	}
+	if magnitude > clockSkewWarningThreshold {
+		logger.Warn(fmt.Sprintf("the local clock differs from the Cloud Controller's by %s; using the Cloud Controller's time", magnitude),
+			"clock_skew", magnitude.String())
+	}
+
+	return func() time.Time {
+		return time.Now().Add(skew).UTC()
+	}
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
-func fatalError(message string, err error) {
-	fmt.Printf("%s: %s", message, err)
-	os.Exit(1)
+func fatalError(exitCode int, message string, err error) {
+	logger.Error(fmt.Sprintf("%s: %s", message, err))
+	os.Exit(exitCode)
 }
@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package pricing
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HoursPerMonth is the average number of hours in a month, by which hourly costs are projected to monthly ones.
+const HoursPerMonth = 730
+
+// Price is the hourly cost of a service instance of a plan of a service.
+type Price struct {
+	Service    string
+	Plan       string
+	HourlyCost float64
+}
+
+type plan struct {
+	service string
+	plan    string
+}
+
+// Table looks up the prices of plans. Plans which it does not list have no price.
+type Table struct {
+	hourlyCosts map[plan]float64
+}
+
+func NewTable(prices ...Price) *Table {
+	table := &Table{hourlyCosts: make(map[plan]float64)}
+	for _, price := range prices {
+		table.hourlyCosts[plan{price.Service, price.Plan}] = price.HourlyCost
+	}
+	return table
+}
+
+// HourlyCost returns the hourly cost of a service instance of the given plan, if the plan is priced.
+func (t *Table) HourlyCost(service string, planName string) (float64, bool) {
+	hourlyCost, ok := t.hourlyCosts[plan{service, planName}]
+	return hourlyCost, ok
+}
+
+// Cost returns the cost of a service instance of the given plan for the given duration, if the plan is priced.
+func (t *Table) Cost(service string, planName string, duration time.Duration) (float64, bool) {
+	hourlyCost, ok := t.HourlyCost(service, planName)
+	return hourlyCost * duration.Hours(), ok
+}
+
+// MonthlyCost returns the projected monthly cost of a service instance of the given plan, if the plan is priced.
+func (t *Table) MonthlyCost(service string, planName string) (float64, bool) {
+	hourlyCost, ok := t.HourlyCost(service, planName)
+	return hourlyCost * HoursPerMonth, ok
+}
+
+// Load reads a price table from the file at the given path. Each line of the file gives the name of a service, the
+// name of one of its plans, and the hourly cost of a service instance of the plan, separated by commas, such as
+// p-mysql,small,0.05. Blank lines and lines starting with # are ignored.
+func Load(path string) (*Table, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open prices %s: %s", path, err)
+	}
+	defer file.Close()
+
+	var prices []Price
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid price at line %d of %s: %s", lineNumber, path, line)
+		}
+
+		hourlyCost, err := strconv.ParseFloat(strings.TrimSpace(fields[2]), 64)
+		if err != nil || hourlyCost < 0 {
+			return nil, fmt.Errorf("invalid hourly cost at line %d of %s: %s", lineNumber, path, line)
+		}
+
+		prices = append(prices, Price{Service: strings.TrimSpace(fields[0]), Plan: strings.TrimSpace(fields[1]), HourlyCost: hourlyCost})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read prices %s: %s", path, err)
+	}
+
+	return NewTable(prices...), nil
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package pricing_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/service-instance-reaper/pricing"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe("Table", func() {
+	var table *pricing.Table
+
+	BeforeEach(func() {
+		table = pricing.NewTable(pricing.Price{Service: "p-mysql", Plan: "small", HourlyCost: 0.5})
+	})
+
+	It("returns the hourly cost of a priced plan", func() {
+		hourlyCost, ok := table.HourlyCost("p-mysql", "small")
+		Expect(ok).To(BeTrue())
+		Expect(hourlyCost).To(Equal(0.5))
+	})
+
+	It("accumulates the cost over a duration", func() {
+		cost, ok := table.Cost("p-mysql", "small", 36*time.Hour)
+		Expect(ok).To(BeTrue())
+		Expect(cost).To(Equal(18.0))
+	})
+
+	It("projects the monthly cost", func() {
+		monthlyCost, ok := table.MonthlyCost("p-mysql", "small")
+		Expect(ok).To(BeTrue())
+		Expect(monthlyCost).To(Equal(365.0))
+	})
+
+	It("does not price other plans", func() {
+		_, ok := table.HourlyCost("p-mysql", "large")
+		Expect(ok).To(BeFalse())
+		_, ok = table.Cost("p-redis", "small", time.Hour)
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("Load", func() {
+	var (
+		directory string
+		path      string
+		contents  string
+		table     *pricing.Table
+		err       error
+	)
+
+	BeforeEach(func() {
+		directory, err = ioutil.TempDir("", "pricing")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(directory, "prices.csv")
+	})
+
+	JustBeforeEach(func() {
+		Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+		table, err = pricing.Load(path)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(directory)
+	})
+
+	Context("when the prices are valid", func() {
+		BeforeEach(func() {
+			contents = "# service,plan,hourly cost\np-mysql,small,0.05\n\n p-mysql , large , 0.2 \n"
+		})
+
+		It("loads them", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(table).To(Equal(pricing.NewTable(
+				pricing.Price{Service: "p-mysql", Plan: "small", HourlyCost: 0.05},
+				pricing.Price{Service: "p-mysql", Plan: "large", HourlyCost: 0.2},
+			)))
+		})
+	})
+
+	Context("when a line has the wrong number of fields", func() {
+		BeforeEach(func() {
+			contents = "p-mysql,small,0.05\np-mysql,0.2\n"
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("invalid price at line 2 of " + path + ": p-mysql,0.2"))
+		})
+	})
+
+	Context("when a cost is invalid", func() {
+		BeforeEach(func() {
+			contents = "p-mysql,small,-1\n"
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("invalid hourly cost at line 1 of " + path + ": p-mysql,small,-1"))
+		})
+	})
+
+	Context("when the file does not exist", func() {
+		JustBeforeEach(func() {
+			table, err = pricing.Load(filepath.Join(directory, "missing.csv"))
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("cannot open prices"))
+		})
+	})
+})
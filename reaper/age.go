@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reaper
+
+import (
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AgeBasis determines the point in time from which the age of a service instance is measured.
+type AgeBasis string
+
+const (
+	CreatedAt     AgeBasis = "created_at"
+	UpdatedAt     AgeBasis = "updated_at"
+	LastOperation AgeBasis = "last_operation"
+	LastBound     AgeBasis = "last_bound"
+)
+
+var AgeBases = []AgeBasis{CreatedAt, UpdatedAt, LastOperation, LastBound}
+
+func ParseAgeBasis(basis string) (AgeBasis, error) {
+	for _, ageBasis := range AgeBases {
+		if string(ageBasis) == basis {
+			return ageBasis, nil
+		}
+	}
+
+	names := make([]string, len(AgeBases))
+	for i, ageBasis := range AgeBases {
+		names[i] = string(ageBasis)
+	}
+	return "", fmt.Errorf("invalid age basis: %s (must be one of %s)", basis, strings.Join(names, ", "))
+}
+
+func (b AgeBasis) Description() string {
+	switch b {
+	case UpdatedAt:
+		return "last update"
+	case LastOperation:
+		return "last operation"
+	case LastBound:
+		return "last binding"
+	default:
+		return "creation"
+	}
+}
+
+// referenceTime returns the time from which the age of the given service instance is measured. Timestamps which the
+// Cloud Controller leaves empty, such as the update time of a service instance which has never been updated, fall
+// back to the creation time.
+func (r *Reaper) referenceTime(serviceInstance cloudfoundry.ServiceInstance) (time.Time, error) {
+	switch r.options.AgeBasis {
+	case UpdatedAt:
+		return r.parseReferenceTime(serviceInstance, serviceInstance.Metadata.UpdatedAt)
+	case LastOperation:
+		return r.parseReferenceTime(serviceInstance, serviceInstance.Entity.LastOperation.UpdatedAt)
+	case LastBound:
+		return r.lastBoundTime(serviceInstance)
+	default:
+		return r.parseReferenceTime(serviceInstance, serviceInstance.Metadata.CreatedAt)
+	}
+}
+
+func (r *Reaper) parseReferenceTime(serviceInstance cloudfoundry.ServiceInstance, timeString string) (time.Time, error) {
+	basis := r.options.AgeBasis
+	if timeString == "" {
+		timeString = serviceInstance.Metadata.CreatedAt
+		basis = CreatedAt
+	}
+
+	referenceTime, err := time.Parse(time.RFC3339, timeString)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid service instance %s time: %s", basis.Description(), err)
+	}
+	return referenceTime, nil
+}
+
+// lastBoundTime treats a service instance which is currently bound as being in use now. Otherwise it returns the time
+// its most recent binding was deleted or, if it has never been bound, its creation time.
+func (r *Reaper) lastBoundTime(serviceInstance cloudfoundry.ServiceInstance) (time.Time, error) {
+	serviceBindings, err := r.cf.GetServiceBindings(serviceInstance.Metadata.Guid)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if len(serviceBindings) > 0 {
+		return r.currentTime(), nil
+	}
+
+	events, err := r.serviceBindingDeleteEvents(serviceInstance.Entity.SpaceGuid)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, event := range events {
+		if event.Entity.Metadata.Request.ServiceInstanceGuid == serviceInstance.Metadata.Guid {
+			return r.parseReferenceTime(serviceInstance, event.Entity.Timestamp)
+		}
+	}
+
+	return r.parseReferenceTime(serviceInstance, serviceInstance.Metadata.CreatedAt)
+}
+
+// eventCache holds the service binding delete events of each space, so that they are listed at most once per run
+// however many service instances the space contains.
+type eventCache struct {
+	spaces map[string][]cloudfoundry.Event
+	mutex  sync.Mutex
+}
+
+func (r *Reaper) serviceBindingDeleteEvents(spaceGuid string) ([]cloudfoundry.Event, error) {
+	r.events.mutex.Lock()
+	defer r.events.mutex.Unlock()
+
+	if events, ok := r.events.spaces[spaceGuid]; ok {
+		return events, nil
+	}
+
+	events, err := r.cf.GetServiceBindingDeleteEvents(spaceGuid)
+	if err != nil {
+		return nil, err
+	}
+	r.events.spaces[spaceGuid] = events
+	return events, nil
+}
@@ -0,0 +1,382 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reaper
+
+import (
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/audit"
+	"github.com/pivotal-cf/service-instance-reaper/checkpoint"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"github.com/pivotal-cf/service-instance-reaper/confirm"
+	"github.com/pivotal-cf/service-instance-reaper/history"
+	"time"
+)
+
+func (r *Reaper) apps() <-chan cloudfoundry.App {
+	output := make(chan cloudfoundry.App, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		apps, appErrors := r.cf.GetApps()
+
+		for app := range apps {
+			output <- app
+		}
+
+		r.mergeListingErrors(appErrors)
+	}()
+
+	return output
+}
+
+func (r *Reaper) matchingAppsOf(apps <-chan cloudfoundry.App) <-chan cloudfoundry.App {
+	output := make(chan cloudfoundry.App, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		for app := range apps {
+			if r.appMatches(app) {
+				output <- app
+			}
+		}
+	}()
+
+	return output
+}
+
+func (r *Reaper) appMatches(app cloudfoundry.App) bool {
+	if r.options.NamePattern != nil && !r.options.NamePattern.MatchString(app.Entity.Name) {
+		return false
+	}
+
+	if r.options.SpaceGuid != "" && app.Entity.SpaceGuid != r.options.SpaceGuid {
+		return false
+	}
+
+	if len(r.options.AppStates) == 0 {
+		return true
+	}
+
+	for _, state := range r.options.AppStates {
+		if app.Entity.State == state {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Reaper) expiredAppsOf(apps <-chan cloudfoundry.App) <-chan cloudfoundry.App {
+	output := make(chan cloudfoundry.App, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		for app := range apps {
+			referenceTime, err := r.appReferenceTime(app)
+			if err != nil {
+				r.fail(newError(ParseError, appResource(app), fmt.Sprintf("invalid app %s time", r.options.AgeBasis.Description()), err))
+				continue
+			}
+
+			if r.expired(referenceTime) {
+				output <- app
+			}
+		}
+	}()
+
+	return output
+}
+
+// appReferenceTime measures the age of an app from its creation or, with the updated_at age basis, its last update.
+// Other age bases apply only to service instances.
+func (r *Reaper) appReferenceTime(app cloudfoundry.App) (time.Time, error) {
+	timeString := app.Metadata.CreatedAt
+	if r.options.AgeBasis == UpdatedAt && app.Metadata.UpdatedAt != "" {
+		timeString = app.Metadata.UpdatedAt
+	}
+	return time.Parse(time.RFC3339, timeString)
+}
+
+// uncompletedAppsOf passes on only the apps which the run being resumed did not delete.
+func (r *Reaper) uncompletedAppsOf(apps <-chan cloudfoundry.App) <-chan cloudfoundry.App {
+	if !r.options.Resume || r.options.Checkpoint == nil {
+		return apps
+	}
+
+	output := make(chan cloudfoundry.App, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		state := r.options.Checkpoint.State()
+		for app := range apps {
+			if !state.IsCompleted(app.Metadata.Guid) {
+				output <- app
+			}
+		}
+	}()
+
+	return output
+}
+
+// candidateAppsOf counts the apps to be deleted and, if MaxDeletions is set, passes them on only if there are no
+// more than that.
+func (r *Reaper) candidateAppsOf(apps <-chan cloudfoundry.App) <-chan cloudfoundry.App {
+	output := make(chan cloudfoundry.App, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		if r.options.MaxDeletions == 0 || !r.options.Reap {
+			for app := range apps {
+				r.countCandidate()
+				output <- app
+			}
+			return
+		}
+
+		var candidates []cloudfoundry.App
+		for app := range apps {
+			r.countCandidate()
+			candidates = append(candidates, app)
+		}
+
+		if len(candidates) > r.options.MaxDeletions {
+			r.tally.add(func(summary *Summary) { summary.SafetyCapExceeded = true })
+			r.fail(newError(SafetyCapError, Resource{}, "", fmt.Errorf("refusing to delete %d apps, more than the maximum of %d",
+				len(candidates), r.options.MaxDeletions)))
+			return
+		}
+
+		for _, app := range candidates {
+			output <- app
+		}
+	}()
+
+	return output
+}
+
+// confirmedAppsOf passes on only the apps whose deletion Confirmer confirms, asking about each in turn or, if
+// ConfirmBatch is set, once about them all. Nothing is asked during a dry run.
+func (r *Reaper) confirmedAppsOf(apps <-chan cloudfoundry.App) <-chan cloudfoundry.App {
+	if r.options.Confirmer == nil || !r.options.Reap {
+		return apps
+	}
+
+	output := make(chan cloudfoundry.App, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		spaceNames := make(map[string]string)
+		var confirmed []cloudfoundry.App
+		var candidates []confirm.Candidate
+		answer := confirm.No
+		for app := range apps {
+			if answer == confirm.Quit {
+				continue
+			}
+
+			candidate, err := r.describeApp(app, spaceNames)
+			if err != nil {
+				r.fail(newError(ListingError, appResource(app), "unable to describe app", err))
+				continue
+			}
+
+			if r.options.ConfirmBatch {
+				confirmed = append(confirmed, app)
+				candidates = append(candidates, candidate)
+				continue
+			}
+
+			if answer != confirm.All {
+				answer, err = r.ask(candidate)
+				if err != nil {
+					r.fail(newError(ConfirmationError, appResource(app), "unable to confirm deletion of app", err))
+				}
+			}
+			if answer == confirm.Yes || answer == confirm.All {
+				output <- app
+			}
+		}
+
+		if len(candidates) == 0 {
+			return
+		}
+		answer, err := r.askBatch(candidates)
+		if err != nil {
+			r.fail(newError(ConfirmationError, Resource{}, "unable to confirm deletion of apps", err))
+		}
+		if answer == confirm.All {
+			for _, app := range confirmed {
+				output <- app
+			}
+		}
+	}()
+
+	return output
+}
+
+// describeApp describes the given app for confirmation, looking up the names of spaces only once.
+func (r *Reaper) describeApp(app cloudfoundry.App, spaceNames map[string]string) (confirm.Candidate, error) {
+	referenceTime, err := r.appReferenceTime(app)
+	if err != nil {
+		return confirm.Candidate{}, err
+	}
+
+	spaceGuid := app.Entity.SpaceGuid
+	spaceName, ok := spaceNames[spaceGuid]
+	if !ok {
+		space, err := r.cf.GetSpace(spaceGuid)
+		if err != nil {
+			return confirm.Candidate{}, err
+		}
+		spaceName = space.Entity.Name
+		spaceNames[spaceGuid] = spaceName
+	}
+
+	return confirm.Candidate{
+		Type:      confirm.AppType,
+		Name:      app.Entity.Name,
+		Guid:      app.Metadata.Guid,
+		Age:       r.currentTime().Sub(referenceTime),
+		SpaceName: spaceName,
+	}, nil
+}
+
+func (r *Reaper) deleteApps(apps <-chan cloudfoundry.App) {
+	go func() {
+		defer r.finish()
+
+		for app := range apps {
+			if r.failingFast() || (r.options.Reap && !r.scheduled()) {
+				continue
+			}
+
+			var routes []cloudfoundry.Route
+			if r.options.DeleteRoutes {
+				var err error
+				routes, err = r.cf.GetAppRoutes(app.Metadata.Guid)
+				if err != nil {
+					r.fail(newError(ListingError, appResource(app), "unable to list routes of app", err))
+					continue
+				}
+			}
+
+			if r.options.Reap {
+				r.checkpointApp(app, checkpoint.Checkpoint.Attempted)
+				if err := r.cf.DeleteApp(app.Metadata.Guid); err != nil {
+					r.checkpointApp(app, checkpoint.Checkpoint.Failed)
+					r.reportAuditFailure(r.auditApp(app, audit.Failed, err.Error()))
+					failure := newError(DeletionError, appResource(app), "unable to delete app", err)
+					r.recordAppHistory(app, failure)
+					r.deletionFailed(failure)
+					continue
+				}
+
+				r.checkpointApp(app, checkpoint.Checkpoint.Completed)
+				r.countReaped()
+				r.reportAuditFailure(r.auditApp(app, audit.Deleted, ""))
+				r.recordAppHistory(app, nil)
+			} else {
+				r.emptying.wouldDelete(app.Entity.SpaceGuid, func(usage *cloudfoundry.SpaceUsage) { usage.Apps++ })
+			}
+
+			r.logger.Info(fmt.Sprintf("%s %s", app.Entity.Name, app.Metadata.Guid),
+				"app_name", app.Entity.Name, "app_guid", app.Metadata.Guid, "reaped", r.options.Reap)
+
+			for _, route := range routes {
+				r.deleteUnmappedRoute(route, app)
+			}
+		}
+	}()
+}
+
+// deleteUnmappedRoute deletes the given route of a reaped app unless it is also mapped to another app.
+func (r *Reaper) deleteUnmappedRoute(route cloudfoundry.Route, reapedApp cloudfoundry.App) {
+	apps, err := r.cf.GetRouteApps(route.Metadata.Guid)
+	if err != nil {
+		r.fail(newError(ListingError, routeResource(route), "unable to determine whether route is in use", err))
+		return
+	}
+
+	for _, app := range apps {
+		if app.Metadata.Guid != reapedApp.Metadata.Guid {
+			return
+		}
+	}
+
+	if r.options.Reap {
+		err = r.cf.DeleteRoute(route.Metadata.Guid)
+		if err != nil {
+			r.reportAuditFailure(r.auditRoute(route, audit.Failed, err.Error()))
+			r.fail(newError(DeletionError, routeResource(route), "unable to delete route", err))
+			return
+		}
+
+		r.reportAuditFailure(r.auditRoute(route, audit.Deleted, ""))
+	} else {
+		r.emptying.wouldDelete(route.Entity.SpaceGuid, func(usage *cloudfoundry.SpaceUsage) { usage.Routes++ })
+	}
+
+	r.logger.Info(fmt.Sprintf("%s %s (route of %s)", route.Entity.Host, route.Metadata.Guid, reapedApp.Entity.Name),
+		"route_host", route.Entity.Host, "route_guid", route.Metadata.Guid, "app_guid", reapedApp.Metadata.Guid, "reaped", r.options.Reap)
+}
+
+// checkpointApp records the progress of deleting an app.
+func (r *Reaper) checkpointApp(app cloudfoundry.App, record func(checkpoint.Checkpoint, string) error) {
+	r.checkpoint(appResource(app), func(c checkpoint.Checkpoint) error {
+		return record(c, app.Metadata.Guid)
+	})
+}
+
+func (r *Reaper) auditApp(app cloudfoundry.App, action string, detail string) *Error {
+	return r.record(appResource(app), audit.Entry{
+		Action:  action,
+		AppGuid: app.Metadata.Guid,
+		AppName: app.Entity.Name,
+		Detail:  detail,
+	})
+}
+
+func (r *Reaper) auditRoute(route cloudfoundry.Route, action string, detail string) *Error {
+	return r.record(routeResource(route), audit.Entry{
+		Action:    action,
+		RouteGuid: route.Metadata.Guid,
+		RouteHost: route.Entity.Host,
+		Detail:    detail,
+	})
+}
+
+// recordAppHistory adds the outcome of attempting to delete an app to the history of the run, if any.
+func (r *Reaper) recordAppHistory(app cloudfoundry.App, failure *Error) {
+	if r.options.History == nil {
+		return
+	}
+
+	deletion := history.Deletion{
+		AppGuid:   app.Metadata.Guid,
+		AppName:   app.Entity.Name,
+		SpaceGuid: app.Entity.SpaceGuid,
+	}
+	if failure != nil {
+		deletion.Error = failure.Err.Error()
+	}
+	r.options.History.Record(deletion)
+}
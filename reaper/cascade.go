@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reaper
+
+import (
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"strings"
+	"time"
+)
+
+// cascade unbinds apps from, deletes the service keys of, and unbinds routes from the given service instance, and
+// then deletes it. Each dependent is deleted and reported individually so that, if any of them cannot be deleted,
+// it is clear which. The service instance is deleted only once all its dependents have gone. Its deletion is
+// retried only until the broker accepts it, and inProgress reports whether the broker has yet to finish it.
+func (r *Reaper) cascade(serviceInstance cloudfoundry.ServiceInstance) (inProgress bool, err error) {
+	failures := make([]string, 0)
+
+	serviceBindings, err := r.cf.GetServiceBindings(serviceInstance.Metadata.Guid)
+	if err != nil {
+		return false, fmt.Errorf("unable to list service bindings: %s", err)
+	}
+	for _, serviceBinding := range serviceBindings {
+		guid := serviceBinding.Metadata.Guid
+		if err := r.unbind(guid); err != nil {
+			failures = append(failures, fmt.Sprintf("unable to unbind app %s: %s", serviceBinding.Entity.AppGuid, err))
+			continue
+		}
+		r.logger.Info(fmt.Sprintf("%s %s (service binding of %s)", serviceBinding.Entity.AppGuid, guid, serviceInstance.Entity.Name),
+			"service_binding_guid", guid, "app_guid", serviceBinding.Entity.AppGuid, "service_instance_guid", serviceInstance.Metadata.Guid)
+	}
+
+	serviceKeys, err := r.cf.GetServiceKeys(serviceInstance.Metadata.Guid)
+	if err != nil {
+		return false, fmt.Errorf("unable to list service keys: %s", err)
+	}
+	for _, serviceKey := range serviceKeys {
+		guid := serviceKey.Metadata.Guid
+		if err := r.retry(func() error { return r.cf.DeleteServiceKey(guid) }); err != nil {
+			failures = append(failures, fmt.Sprintf("unable to delete service key %s: %s", serviceKey.Entity.Name, err))
+			continue
+		}
+		r.logger.Info(fmt.Sprintf("%s %s (service key of %s)", serviceKey.Entity.Name, guid, serviceInstance.Entity.Name),
+			"service_key_name", serviceKey.Entity.Name, "service_key_guid", guid, "service_instance_guid", serviceInstance.Metadata.Guid)
+	}
+
+	routes, err := r.cf.GetServiceInstanceRoutes(serviceInstance.Metadata.Guid)
+	if err != nil {
+		return false, fmt.Errorf("unable to list route bindings: %s", err)
+	}
+	for _, route := range routes {
+		guid := route.Metadata.Guid
+		if err := r.retry(func() error { return r.cf.UnbindRouteService(serviceInstance.Metadata.Guid, guid) }); err != nil {
+			failures = append(failures, fmt.Sprintf("unable to unbind route %s: %s", route.Entity.Host, err))
+			continue
+		}
+		r.logger.Info(fmt.Sprintf("%s %s (route binding of %s)", route.Entity.Host, guid, serviceInstance.Entity.Name),
+			"route_host", route.Entity.Host, "route_guid", guid, "service_instance_guid", serviceInstance.Metadata.Guid)
+	}
+
+	if len(failures) > 0 {
+		return false, fmt.Errorf("cascade incomplete: %s", strings.Join(failures, "; "))
+	}
+
+	err = r.retry(func() (err error) {
+		inProgress, err = r.cf.DeleteServiceInstance(serviceInstance.Metadata.Guid, false)
+		return
+	})
+	return inProgress, err
+}
+
+// unbind deletes the given service binding and, if its broker unbinds asynchronously, waits for the service binding
+// to disappear, checking up to CascadeAttempts times, CascadeRetryInterval apart.
+func (r *Reaper) unbind(serviceBindingGuid string) error {
+	var inProgress bool
+	err := r.retry(func() (err error) {
+		inProgress, err = r.cf.DeleteServiceBinding(serviceBindingGuid)
+		return
+	})
+	if err != nil || !inProgress {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		time.Sleep(r.options.CascadeRetryInterval)
+
+		serviceBinding, found, err := r.cf.GetServiceBinding(serviceBindingGuid)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return nil
+		}
+
+		lastOperation := serviceBinding.Entity.LastOperation
+		if lastOperation.State == cloudfoundry.LastOperationFailed {
+			return fmt.Errorf("asynchronous unbinding failed: %s", lastOperation.Description)
+		}
+		if attempt >= r.options.CascadeAttempts {
+			return fmt.Errorf("asynchronous unbinding still in progress after %d checks", attempt)
+		}
+	}
+}
+
+func (r *Reaper) retry(operation func() error) (err error) {
+	for attempt := 1; ; attempt++ {
+		err = operation()
+		if err == nil || attempt >= r.options.CascadeAttempts {
+			return
+		}
+		time.Sleep(r.options.CascadeRetryInterval)
+	}
+}
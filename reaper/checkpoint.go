@@ -0,0 +1,138 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reaper
+
+import (
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/checkpoint"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+)
+
+// resumingListedRun reports whether the run being resumed found every candidate, so that they need not be listed
+// again.
+func (r *Reaper) resumingListedRun() bool {
+	return r.options.Resume && r.options.Checkpoint != nil && r.options.Checkpoint.State().Listed
+}
+
+// pendingInstances yields the candidates of the run being resumed which it did not delete and which are still
+// eligible for reaping. Each is fetched again, since it may have changed, or been deleted, since the run was
+// interrupted. Retention and quotas, which depend on the other service instances, are not applied again.
+func (r *Reaper) pendingInstances() <-chan cloudfoundry.ServiceInstance {
+	output := make(chan cloudfoundry.ServiceInstance, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		state := r.options.Checkpoint.State()
+		pending := state.Pending()
+		r.logger.Info("Resuming with the remaining candidates of the interrupted run", "pending", len(pending))
+		for _, candidate := range pending {
+			serviceInstance, found, err := r.refetch(candidate)
+			if err != nil {
+				r.fail(newError(ListingError, serviceInstanceResource(candidate), "unable to re-check service instance", err))
+				if r.failingFast() {
+					return
+				}
+				continue
+			}
+
+			if !found {
+				if state.IsAttempted(candidate.Metadata.Guid) {
+					r.logger.Info(fmt.Sprintf("Service instance %s %s was deleted by the interrupted run", candidate.Metadata.Guid, candidate.Entity.Name),
+						"service_instance_guid", candidate.Metadata.Guid, "service_instance_name", candidate.Entity.Name)
+					r.checkpointProgress(candidate, checkpoint.Checkpoint.Completed)
+				} else {
+					r.logger.Info(fmt.Sprintf("Service instance %s %s no longer exists", candidate.Metadata.Guid, candidate.Entity.Name),
+						"service_instance_guid", candidate.Metadata.Guid, "service_instance_name", candidate.Entity.Name)
+				}
+				continue
+			}
+
+			if serviceInstance.Entity.ServicePlanGuid != candidate.Entity.ServicePlanGuid {
+				r.logger.Info(fmt.Sprintf("Service instance %s %s has changed plan", candidate.Metadata.Guid, candidate.Entity.Name),
+					"service_instance_guid", candidate.Metadata.Guid, "service_instance_name", candidate.Entity.Name)
+				continue
+			}
+
+			output <- serviceInstance
+		}
+	}()
+
+	return r.unusedInstancesOf(r.expiredInstancesOf(r.matchingInstancesOf(output)))
+}
+
+// refetch fetches a candidate of the run being resumed again, reporting whether it still exists.
+func (r *Reaper) refetch(candidate cloudfoundry.ServiceInstance) (cloudfoundry.ServiceInstance, bool, error) {
+	if candidate.UserProvided() {
+		return r.cf.GetUserProvidedServiceInstance(candidate.Metadata.Guid)
+	}
+	return r.cf.GetServiceInstance(candidate.Metadata.Guid)
+}
+
+// uncompletedInstancesOf passes on only the service instances which the run being resumed did not delete.
+func (r *Reaper) uncompletedInstancesOf(serviceInstances <-chan cloudfoundry.ServiceInstance) <-chan cloudfoundry.ServiceInstance {
+	if !r.options.Resume || r.options.Checkpoint == nil {
+		return serviceInstances
+	}
+
+	output := make(chan cloudfoundry.ServiceInstance, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		state := r.options.Checkpoint.State()
+		for serviceInstance := range serviceInstances {
+			if !state.IsCompleted(serviceInstance.Metadata.Guid) {
+				output <- serviceInstance
+			}
+		}
+	}()
+
+	return output
+}
+
+// checkpointCandidate records a service instance which is to be deleted.
+func (r *Reaper) checkpointCandidate(serviceInstance cloudfoundry.ServiceInstance) {
+	r.checkpoint(serviceInstanceResource(serviceInstance), func(c checkpoint.Checkpoint) error { return c.Candidate(serviceInstance) })
+}
+
+// checkpointListed records that every candidate has been found, unless some may have been missed because resources
+// could not be listed or inspected.
+func (r *Reaper) checkpointListed() {
+	if r.errors.contains(ListingError, ParseError) {
+		return
+	}
+	r.checkpoint(Resource{}, checkpoint.Checkpoint.Listed)
+}
+
+// checkpointProgress records the progress of deleting a service instance.
+func (r *Reaper) checkpointProgress(serviceInstance cloudfoundry.ServiceInstance, record func(checkpoint.Checkpoint, string) error) {
+	r.checkpoint(serviceInstanceResource(serviceInstance), func(c checkpoint.Checkpoint) error {
+		return record(c, serviceInstance.Metadata.Guid)
+	})
+}
+
+// checkpoint records progress in the checkpoint, if any. A failure to do so does not stop reaping.
+func (r *Reaper) checkpoint(resource Resource, record func(checkpoint.Checkpoint) error) {
+	if r.options.Checkpoint == nil {
+		return
+	}
+
+	if err := record(r.options.Checkpoint); err != nil {
+		r.fail(newError(CheckpointError, resource, "unable to checkpoint progress", err))
+	}
+}
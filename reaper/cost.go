@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reaper
+
+import (
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"time"
+)
+
+// warnIfUnpriced warns, once per run, that no costs will be reported if Prices is set but does not price the plan
+// being reaped.
+func (r *Reaper) warnIfUnpriced() {
+	if r.options.Prices == nil || r.options.UserProvided {
+		return
+	}
+
+	if _, priced := r.options.Prices.HourlyCost(r.options.ServiceName, r.options.PlanName); !priced {
+		r.logger.Warn(fmt.Sprintf("No price for plan '%s' of service '%s': costs will not be reported", r.options.PlanName, r.options.ServiceName),
+			"service_name", r.options.ServiceName, "plan_name", r.options.PlanName)
+	}
+}
+
+// costOf returns the cost the given service instance has accumulated since its creation and its projected monthly
+// cost, if Prices is set and prices its plan. User-provided service instances have no plan and so are never priced.
+// A creation time in the future, as clock skew can produce, accumulates no cost.
+func (r *Reaper) costOf(serviceInstance cloudfoundry.ServiceInstance) (accumulated float64, monthly float64, priced bool) {
+	if r.options.Prices == nil || serviceInstance.UserProvided() {
+		return 0, 0, false
+	}
+
+	creationTime, err := time.Parse(time.RFC3339, serviceInstance.Metadata.CreatedAt)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	age := r.currentTime().Sub(creationTime)
+	if age < 0 {
+		age = 0
+	}
+
+	accumulated, priced = r.options.Prices.Cost(r.options.ServiceName, r.options.PlanName, age)
+	monthly, _ = r.options.Prices.MonthlyCost(r.options.ServiceName, r.options.PlanName)
+	return accumulated, monthly, priced
+}
+
+// describeCost returns a description of the cost of the given service instance, to be appended to a log message,
+// and the corresponding log fields. Both are empty if the service instance is not priced.
+func (r *Reaper) describeCost(serviceInstance cloudfoundry.ServiceInstance) (string, []interface{}) {
+	accumulated, monthly, priced := r.costOf(serviceInstance)
+	if !priced {
+		return "", nil
+	}
+	return fmt.Sprintf(" (cost %.2f since creation, %.2f per month)", accumulated, monthly),
+		[]interface{}{"accumulated_cost", accumulated, "monthly_cost", monthly}
+}
+
+func (r *Reaper) costCandidate(serviceInstance cloudfoundry.ServiceInstance) {
+	if accumulated, monthly, priced := r.costOf(serviceInstance); priced {
+		r.tally.add(func(summary *Summary) {
+			summary.AccumulatedCost += accumulated
+			summary.MonthlySavings += monthly
+		})
+	}
+}
+
+func (r *Reaper) countSavings(serviceInstance cloudfoundry.ServiceInstance) {
+	if _, monthly, priced := r.costOf(serviceInstance); priced {
+		r.tally.add(func(summary *Summary) { summary.RealisedMonthlySavings += monthly })
+	}
+}
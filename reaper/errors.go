@@ -0,0 +1,172 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reaper
+
+import (
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"strings"
+	"sync"
+)
+
+// ErrorKind classifies the errors which occur during reaping.
+type ErrorKind string
+
+const (
+	// ListingError indicates that resources could not be listed or inspected, so may not have been reaped.
+	ListingError ErrorKind = "listing"
+
+	// ParseError indicates that a resource had a time which could not be parsed, so its age is unknown.
+	ParseError ErrorKind = "parse"
+
+	// DeletionError indicates that a resource could not be deleted, or could not be prepared for deletion.
+	DeletionError ErrorKind = "deletion"
+
+	// AuditError indicates that a resource was reaped but its reaping could not be audited.
+	AuditError ErrorKind = "audit"
+
+	// ConfirmationError indicates that the deletion of a resource could not be confirmed, so it was not deleted.
+	ConfirmationError ErrorKind = "confirmation"
+
+	// CheckpointError indicates that the progress of reaping could not be saved, so could not be resumed.
+	CheckpointError ErrorKind = "checkpoint"
+
+	// SafetyCapError indicates that nothing was deleted because more would have been deleted than MaxDeletions.
+	SafetyCapError ErrorKind = "safety cap"
+)
+
+// Resource identifies the resource affected by an error. Any of its fields may be empty.
+type Resource struct {
+	Type string
+	Name string
+	Guid string
+}
+
+func serviceInstanceResource(serviceInstance cloudfoundry.ServiceInstance) Resource {
+	return Resource{Type: "service instance", Name: serviceInstance.Entity.Name, Guid: serviceInstance.Metadata.Guid}
+}
+
+func serviceKeyResource(serviceKey cloudfoundry.ServiceKey) Resource {
+	return Resource{Type: "service key", Name: serviceKey.Entity.Name, Guid: serviceKey.Metadata.Guid}
+}
+
+func appResource(app cloudfoundry.App) Resource {
+	return Resource{Type: "app", Name: app.Entity.Name, Guid: app.Metadata.Guid}
+}
+
+func routeResource(route cloudfoundry.Route) Resource {
+	return Resource{Type: "route", Name: route.Entity.Host, Guid: route.Metadata.Guid}
+}
+
+func spaceResource(space cloudfoundry.Space) Resource {
+	return Resource{Type: "space", Name: space.Entity.Name, Guid: space.Metadata.Guid}
+}
+
+// Error is an error which occurred during reaping, together with its kind and the resource it affected.
+type Error struct {
+	Kind     ErrorKind
+	Resource Resource
+	Err      error
+	message  string
+}
+
+// newError describes an error in the form "description: name guid (err)", omitting whichever parts are empty.
+func newError(kind ErrorKind, resource Resource, description string, err error) *Error {
+	message := description
+	if identity := strings.TrimSpace(resource.Name + " " + resource.Guid); identity != "" {
+		message = fmt.Sprintf("%s: %s", message, identity)
+	}
+	if err != nil {
+		if message == "" {
+			message = err.Error()
+		} else {
+			message = fmt.Sprintf("%s (%s)", message, err)
+		}
+	}
+
+	return &Error{Kind: kind, Resource: resource, Err: err, message: message}
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Errors is every error which occurred during reaping, in the order in which they occurred.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	if len(e) == 1 {
+		return fmt.Sprintf("1 error occurred whilst reaping: %s", messages[0])
+	}
+	return fmt.Sprintf("%d errors occurred whilst reaping: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the individual errors, so that errors.Is and errors.As examine each of them.
+func (e Errors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// OfKind returns the errors of the given kinds.
+func (e Errors) OfKind(kinds ...ErrorKind) Errors {
+	var matching Errors
+	for _, err := range e {
+		for _, kind := range kinds {
+			if err.Kind == kind {
+				matching = append(matching, err)
+				break
+			}
+		}
+	}
+	return matching
+}
+
+// errorCollector collects errors from the concurrent stages of the pipeline.
+type errorCollector struct {
+	errors Errors
+	mutex  sync.Mutex
+}
+
+func (c *errorCollector) add(err *Error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.errors = append(c.errors, err)
+}
+
+func (c *errorCollector) contains(kinds ...ErrorKind) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.errors.OfKind(kinds...)) > 0
+}
+
+func (c *errorCollector) collected() Errors {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append(Errors(nil), c.errors...)
+}
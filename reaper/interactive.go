@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reaper
+
+import (
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"github.com/pivotal-cf/service-instance-reaper/confirm"
+)
+
+// confirmedInstancesOf passes on only the service instances whose deletion Confirmer confirms, asking about each in
+// turn or, if ConfirmBatch is set, once about them all. Nothing is asked during a dry run.
+func (r *Reaper) confirmedInstancesOf(serviceInstances <-chan cloudfoundry.ServiceInstance) <-chan cloudfoundry.ServiceInstance {
+	if r.options.Confirmer == nil || !r.options.Reap {
+		return serviceInstances
+	}
+
+	output := make(chan cloudfoundry.ServiceInstance, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		spaceNames := make(map[string]string)
+		var confirmed []cloudfoundry.ServiceInstance
+		var candidates []confirm.Candidate
+		answer := confirm.No
+		for serviceInstance := range serviceInstances {
+			if answer == confirm.Quit {
+				continue
+			}
+
+			candidate, err := r.describe(serviceInstance, spaceNames)
+			if err != nil {
+				r.fail(newError(ListingError, serviceInstanceResource(serviceInstance), "unable to describe service instance", err))
+				continue
+			}
+
+			if r.options.ConfirmBatch {
+				confirmed = append(confirmed, serviceInstance)
+				candidates = append(candidates, candidate)
+				continue
+			}
+
+			if answer != confirm.All {
+				answer, err = r.ask(candidate)
+				if err != nil {
+					r.fail(newError(ConfirmationError, serviceInstanceResource(serviceInstance), "unable to confirm deletion of service instance", err))
+				}
+			}
+			if answer == confirm.Yes || answer == confirm.All {
+				output <- serviceInstance
+			}
+		}
+
+		if len(candidates) == 0 {
+			return
+		}
+		answer, err := r.askBatch(candidates)
+		if err != nil {
+			r.fail(newError(ConfirmationError, Resource{}, "unable to confirm deletion of service instances", err))
+		}
+		if answer == confirm.All {
+			for _, serviceInstance := range confirmed {
+				output <- serviceInstance
+			}
+		}
+	}()
+
+	return output
+}
+
+// ask asks Confirmer whether the given candidate should be deleted, holding back log messages meanwhile so that they
+// are not interleaved with the question.
+func (r *Reaper) ask(candidate confirm.Candidate) (answer confirm.Answer, err error) {
+	r.logger.Pause(func() { answer, err = r.options.Confirmer.Confirm(candidate) })
+	return
+}
+
+// askBatch asks Confirmer once whether all the given candidates should be deleted, holding back log messages
+// meanwhile.
+func (r *Reaper) askBatch(candidates []confirm.Candidate) (answer confirm.Answer, err error) {
+	r.logger.Pause(func() { answer, err = r.options.Confirmer.ConfirmBatch(candidates) })
+	return
+}
+
+// describe describes the given service instance for confirmation, looking up the names of spaces only once.
+func (r *Reaper) describe(serviceInstance cloudfoundry.ServiceInstance, spaceNames map[string]string) (confirm.Candidate, error) {
+	referenceTime, err := r.referenceTime(serviceInstance)
+	if err != nil {
+		return confirm.Candidate{}, err
+	}
+
+	spaceGuid := serviceInstance.Entity.SpaceGuid
+	spaceName, ok := spaceNames[spaceGuid]
+	if !ok {
+		space, err := r.cf.GetSpace(spaceGuid)
+		if err != nil {
+			return confirm.Candidate{}, err
+		}
+		spaceName = space.Entity.Name
+		spaceNames[spaceGuid] = spaceName
+	}
+
+	serviceBindings, err := r.cf.GetServiceBindings(serviceInstance.Metadata.Guid)
+	if err != nil {
+		return confirm.Candidate{}, err
+	}
+
+	return confirm.Candidate{
+		Name:            serviceInstance.Entity.Name,
+		Guid:            serviceInstance.Metadata.Guid,
+		Age:             r.currentTime().Sub(referenceTime),
+		SpaceName:       spaceName,
+		ServiceBindings: len(serviceBindings),
+	}, nil
+}
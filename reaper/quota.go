@@ -0,0 +1,176 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reaper
+
+import (
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"sort"
+)
+
+// overQuotaInstancesOf passes on, from each space whose service instance usage exceeds QuotaThreshold percent of
+// its quota, the oldest service instances whose deletion brings usage down to QuotaTarget percent. Every space is
+// limited by its organization's quota, shared by all the organization's spaces, so the oldest service instances
+// across an organization's spaces are passed on; a space with a space quota is limited by that quota as well, and
+// the service instances already passed on for it count towards its organization's usage. Service instances in
+// other spaces are not passed on. It must see every service instance before it can pass any on.
+func (r *Reaper) overQuotaInstancesOf(serviceInstances <-chan cloudfoundry.ServiceInstance) <-chan cloudfoundry.ServiceInstance {
+	if r.options.QuotaThreshold == 0 {
+		return serviceInstances
+	}
+
+	output := make(chan cloudfoundry.ServiceInstance, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		spaces := make(map[string][]retentionCandidate)
+		spaceGuids := make([]string, 0)
+		for serviceInstance := range serviceInstances {
+			referenceTime, err := r.referenceTime(serviceInstance)
+			if err != nil {
+				r.fail(newError(ParseError, serviceInstanceResource(serviceInstance), "unable to determine age of service instance", err))
+				continue
+			}
+
+			spaceGuid := serviceInstance.Entity.SpaceGuid
+			if _, ok := spaces[spaceGuid]; !ok {
+				spaceGuids = append(spaceGuids, spaceGuid)
+			}
+			spaces[spaceGuid] = append(spaces[spaceGuid], retentionCandidate{serviceInstance: serviceInstance, referenceTime: referenceTime})
+		}
+
+		quotas := make(map[string]quota)
+		scopes := make(map[string][]retentionCandidate)
+		var spaceQuotas, organizationQuotas []quota
+		for _, spaceGuid := range spaceGuids {
+			spaceQuota, organizationQuota, err := r.quotasOf(spaceGuid, quotas)
+			if err != nil {
+				r.fail(newError(ListingError, Resource{Type: "space", Guid: spaceGuid}, "unable to determine service instance quota usage of space", err))
+				continue
+			}
+
+			if spaceQuota != nil {
+				spaceQuotas = append(spaceQuotas, *spaceQuota)
+				scopes[spaceQuota.scope] = spaces[spaceGuid]
+			}
+			if _, ok := scopes[organizationQuota.scope]; !ok {
+				organizationQuotas = append(organizationQuotas, organizationQuota)
+			}
+			scopes[organizationQuota.scope] = append(scopes[organizationQuota.scope], spaces[spaceGuid]...)
+		}
+
+		passed := make(map[string]bool)
+		for _, q := range append(spaceQuotas, organizationQuotas...) {
+			candidates := scopes[q.scope]
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return candidates[i].referenceTime.Before(candidates[j].referenceTime)
+			})
+
+			excess := r.quotaExcess(q)
+			for _, candidate := range candidates {
+				if passed[candidate.serviceInstance.Metadata.Guid] {
+					excess--
+				}
+			}
+			for _, candidate := range candidates {
+				if excess <= 0 {
+					break
+				}
+				if passed[candidate.serviceInstance.Metadata.Guid] {
+					continue
+				}
+				passed[candidate.serviceInstance.Metadata.Guid] = true
+				output <- candidate.serviceInstance
+				excess--
+			}
+		}
+	}()
+
+	return output
+}
+
+// quota is a service instance quota which limits a space, together with the usage counted against it. scope
+// identifies the quota: a space's own quota applies to that space alone, while an organization's quota applies to
+// all its spaces.
+type quota struct {
+	scope         string
+	totalServices int
+	usage         int
+}
+
+// quotasOf returns the quotas which limit the given space: its own quota, or nil if it has none or that quota is
+// unlimited, and its organization's quota. It remembers organization quotas in the given map so that the usage of
+// each organization is counted only once.
+func (r *Reaper) quotasOf(spaceGuid string, organizationQuotas map[string]quota) (*quota, quota, error) {
+	space, err := r.cf.GetSpace(spaceGuid)
+	if err != nil {
+		return nil, quota{}, err
+	}
+
+	var spaceQuota *quota
+	if space.Entity.SpaceQuotaDefinitionGuid != "" {
+		spaceQuotaDefinition, err := r.cf.GetSpaceQuotaDefinition(space.Entity.SpaceQuotaDefinitionGuid)
+		if err != nil {
+			return nil, quota{}, err
+		}
+		if spaceQuotaDefinition.Entity.TotalServices != cloudfoundry.UnlimitedServices {
+			usage, err := r.cf.CountSpaceServiceInstances(spaceGuid)
+			if err != nil {
+				return nil, quota{}, err
+			}
+			spaceQuota = &quota{scope: "space/" + spaceGuid, totalServices: spaceQuotaDefinition.Entity.TotalServices, usage: usage}
+		}
+	}
+
+	organizationGuid := space.Entity.OrganizationGuid
+	if q, ok := organizationQuotas[organizationGuid]; ok {
+		return spaceQuota, q, nil
+	}
+
+	organization, err := r.cf.GetOrganization(organizationGuid)
+	if err != nil {
+		return nil, quota{}, err
+	}
+
+	quotaDefinition, err := r.cf.GetOrganizationQuotaDefinition(organization.Entity.QuotaDefinitionGuid)
+	if err != nil {
+		return nil, quota{}, err
+	}
+
+	usage, err := r.cf.CountOrganizationServiceInstances(organizationGuid)
+	if err != nil {
+		return nil, quota{}, err
+	}
+
+	q := quota{scope: "organization/" + organizationGuid, totalServices: quotaDefinition.Entity.TotalServices, usage: usage}
+	organizationQuotas[organizationGuid] = q
+	return spaceQuota, q, nil
+}
+
+// quotaExcess returns the number of service instances which must be deleted to bring usage down to QuotaTarget
+// percent of the given quota, or zero if usage does not exceed QuotaThreshold percent or the quota is unlimited.
+func (r *Reaper) quotaExcess(q quota) int {
+	if q.totalServices <= 0 {
+		return 0
+	}
+
+	if q.usage*100 <= q.totalServices*r.options.QuotaThreshold {
+		return 0
+	}
+
+	return q.usage - q.totalServices*r.options.QuotaTarget/100
+}
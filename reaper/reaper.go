@@ -17,70 +17,299 @@
 package reaper
 
 import (
-	"errors"
 	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/audit"
+	"github.com/pivotal-cf/service-instance-reaper/calendar"
+	"github.com/pivotal-cf/service-instance-reaper/checkpoint"
 	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
-	"io"
+	"github.com/pivotal-cf/service-instance-reaper/confirm"
+	"github.com/pivotal-cf/service-instance-reaper/history"
+	"github.com/pivotal-cf/service-instance-reaper/logging"
+	"github.com/pivotal-cf/service-instance-reaper/pricing"
+	"github.com/pivotal-cf/service-instance-reaper/snapshot"
+	"regexp"
+	"sync"
 	"time"
 )
 
 type Reaper struct {
-	cf             cloudfoundry.Client
-	expiryInterval time.Duration
-	serviceName    string
-	planName       string
-	reap           bool
-	recursive      bool
-	currentTime    func() time.Time
-	output         io.Writer
-	errorChannel   chan error
-}
-
-func NewReaper(cf cloudfoundry.Client, currentTime func() time.Time, output io.Writer) Reaper {
+	cf          cloudfoundry.Client
+	options     Options
+	currentTime func() time.Time
+	logger      *logging.Logger
+	errors      *errorCollector
+	done        chan struct{}
+	tally       *tally
+	events      *eventCache
+	schedule    *scheduleGate
+	emptying    *spaceLedger
+}
+
+type Options struct {
+	ServiceName    string
+	PlanName       string
+	ExpiryInterval time.Duration
+
+	// Cutoff, if set, expires service instances and apps whose age is measured from a time before the cutoff,
+	// regardless of ExpiryInterval.
+	Cutoff time.Time
+
+	// Calendar, if set, measures age against ExpiryInterval in business days only, excluding weekends and holidays.
+	Calendar *calendar.Calendar
+
+	// Schedule, if set, determines when deletions are permitted. At any other time, Reap performs a dry run
+	// instead and reports why. It is checked again before each deletion, so that a run which outlasts its window
+	// stops deleting when the window closes and skips the remaining candidates.
+	Schedule *calendar.Schedule
+
+	// UserProvided reaps user-provided service instances, rather than instances of ServiceName and PlanName.
+	UserProvided bool
+
+	// NamePattern and SpaceGuid, if set, restrict reaping to service instances with a matching name or in the
+	// given space.
+	NamePattern *regexp.Regexp
+	SpaceGuid   string
+
+	// ProtectionTag, if set, protects service instances with the given tag from ever being reaped.
+	ProtectionTag string
+
+	Reap     bool
+	AgeBasis AgeBasis
+
+	// Recursive deletes the service bindings, service keys, and route bindings of each service instance one at a
+	// time before deleting the service instance itself. Each step is attempted up to CascadeAttempts times, waiting
+	// CascadeRetryInterval between attempts. A service binding which its broker deletes asynchronously is checked
+	// as many times, as far apart, for having gone.
+	Recursive            bool
+	CascadeAttempts      int
+	CascadeRetryInterval time.Duration
+
+	// UnboundOnly restricts reaping to service instances with no service bindings and, if WithoutServiceKeys is
+	// also set, no service keys. Bound service instances are never deleted, even when Recursive is set.
+	UnboundOnly        bool
+	WithoutServiceKeys bool
+
+	// LastOperationStates, if non-empty, restricts reaping to service instances whose last operation is in one of
+	// the given states, such as failed or in progress. Combine with the last_operation age basis to find service
+	// instances which have been stuck in a state for longer than the expiry interval.
+	LastOperationStates []string
+
+	// Purge removes a service instance from the Cloud Controller without involving its broker if the broker fails
+	// to delete it. Purge is only permitted together with LastOperationStates limited to failed and in progress.
+	Purge bool
+
+	// PurgeOrphans purges any service instance whose deletion fails because its broker cannot be reached.
+	PurgeOrphans bool
+
+	// AuditTrail, if set, records every deletion, purge, and failed deletion.
+	AuditTrail audit.Trail
+
+	// KeepNewest, if non-zero, spares the newest KeepNewest service instances of each group, as determined by
+	// KeepGrouping, from reaping, however old they are. Set ExpiryInterval to zero to reap the rest regardless of age.
+	KeepNewest   int
+	KeepGrouping Grouping
+
+	// QuotaThreshold, if non-zero, restricts reaping to spaces whose service instance usage exceeds the given
+	// percentage of their quota, and reaps only the oldest eligible service instances in each such space which
+	// need to be deleted to bring usage down to QuotaTarget percent. A space with its own quota is checked against
+	// both that quota and its organization's.
+	QuotaThreshold int
+	QuotaTarget    int
+
+	// ServiceKeyExpiryInterval, if non-zero, reaps service keys older than the interval, and with names matching
+	// ServiceKeyNamePattern if set, from every service instance in scope. The service instances themselves are
+	// not affected. Service keys are confirmed, checkpointed, and recorded like service instances, and capped by
+	// MaxDeletions separately from them.
+	ServiceKeyExpiryInterval time.Duration
+	ServiceKeyNamePattern    *regexp.Regexp
+
+	// Apps reaps apps, rather than service instances, which match NamePattern and SpaceGuid, are in one of
+	// AppStates if given, and are older than ExpiryInterval according to AgeBasis. DeleteRoutes also deletes the
+	// routes of each reaped app which are then mapped to no other app. Apps are capped, confirmed, and
+	// checkpointed like service instances.
+	Apps         bool
+	AppStates    []string
+	DeleteRoutes bool
+
+	// EmptySpaceNamePattern, if set, deletes spaces with matching names which are older than EmptySpaceExpiryInterval
+	// and contain no apps, service instances, or routes once reaping is complete. A dry run counts the resources it
+	// would have deleted as gone. Spaces are capped, confirmed, checkpointed, and recorded like service keys.
+	EmptySpaceNamePattern    *regexp.Regexp
+	EmptySpaceExpiryInterval time.Duration
+
+	// MaxDeletions, if non-zero, is a safety cap: if more than MaxDeletions service instances would be deleted,
+	// none are. Likewise, if more than MaxDeletions service keys, apps, or empty spaces would be deleted, none are.
+	MaxDeletions int
+
+	// FailFast stops reaping at the first listing or parse error, so that nothing further is deleted. Otherwise
+	// such errors are recorded and the remaining services, service plans, and service instances are still reaped.
+	FailFast bool
+
+	// Confirmer, if set, is asked to confirm the deletion of each service instance or, if ConfirmBatch is set, of
+	// all the service instances at once. Service keys, apps, and empty spaces are confirmed in the same way.
+	Confirmer    confirm.Confirmer
+	ConfirmBatch bool
+
+	// Checkpoint, if set, records the progress of reaping service instances so that an interrupted run can be
+	// resumed. Resume continues the run it recorded: service instances already deleted are skipped and, if every
+	// candidate had been found, they are not listed again but each remaining candidate is fetched and checked again,
+	// except against retention and quotas. Apps are always listed again, skipping those deleted.
+	Checkpoint checkpoint.Checkpoint
+	Resume     bool
+
+	// History, if set, records the outcome of each attempt to delete a service instance, service key, app, or empty
+	// space.
+	History history.Recorder
+
+	// Prices, if set, prices the plan of ServiceName and PlanName so that the cost each candidate has accumulated
+	// since its creation, the monthly savings projected from reaping every candidate, and those realised by the
+	// service instances actually reaped, are reported.
+	Prices *pricing.Table
+
+	// Archive, if set, receives a snapshot of each service instance before it is deleted. A service instance
+	// is not deleted if its snapshot cannot be taken.
+	Archive snapshot.Archive
+}
+
+func NewReaper(cf cloudfoundry.Client, currentTime func() time.Time, logger *logging.Logger) Reaper {
 	return Reaper{
 		cf:          cf,
 		currentTime: currentTime,
-		output:      output,
+		logger:      logger,
 	}
 }
 
-func (r Reaper) Reap(serviceName string, planName string, expiryInterval time.Duration, reap bool, recursive bool) error {
-	r.expiryInterval = expiryInterval
-	r.serviceName = serviceName
-	r.planName = planName
-	r.reap = reap
-	r.recursive = recursive
-	r.errorChannel = make(chan error, 100)
+// Reap reaps according to the given options and summarises the outcome. If any errors occurred, even if reaping was
+// otherwise successful, it returns them all as Errors.
+func (r Reaper) Reap(options Options) (Summary, error) {
+	r.options = options
+	r.errors = &errorCollector{}
+	r.done = make(chan struct{})
+	r.tally = &tally{}
+	r.events = &eventCache{spaces: make(map[string][]cloudfoundry.Event)}
+	r.schedule = &scheduleGate{}
+	r.emptying = &spaceLedger{dryRun: make(map[string]cloudfoundry.SpaceUsage), inProgress: make(map[string][]string)}
+	started := r.currentTime()
 
-	r.delete(r.expiredInstancesOf(r.eligibleServicePlansFrom(r.eligibleServices())))
+	if r.options.Reap && r.options.Schedule != nil {
+		if permitted, reason := r.options.Schedule.Permits(r.currentTime()); !permitted {
+			r.logger.Warn(fmt.Sprintf("DRY RUN ONLY: %s", reason), "reason", reason)
+			r.options.Reap = false
+		}
+	}
 
-	errorsFound := false
-	for err := range r.errorChannel {
-		errorsFound = true
-		fmt.Fprintln(r.output, err)
+	var serviceInstances <-chan cloudfoundry.ServiceInstance
+	if r.options.Apps {
+		r.deleteApps(r.confirmedAppsOf(r.candidateAppsOf(r.uncompletedAppsOf(r.expiredAppsOf(r.matchingAppsOf(r.apps()))))))
+		err := r.reportErrors()
+		return r.summarise(started), err
 	}
 
-	if errorsFound {
-		return errors.New("errors occurred whilst reaping")
+	r.warnIfUnpriced()
+	if r.resumingListedRun() {
+		serviceInstances = r.pendingInstances()
+	} else {
+		serviceInstances = r.eligibleInstances()
+	}
+
+	r.delete(r.confirmedInstancesOf(r.candidatesOf(r.uncompletedInstancesOf(serviceInstances))))
+
+	err := r.reportErrors()
+	return r.summarise(started), err
+}
+
+// eligibleInstances lists the service instances in scope and passes on those which are eligible for reaping.
+func (r *Reaper) eligibleInstances() <-chan cloudfoundry.ServiceInstance {
+	var serviceInstances <-chan cloudfoundry.ServiceInstance
+	if r.options.UserProvided {
+		serviceInstances = r.userProvidedInstances()
+	} else {
+		serviceInstances = r.instancesOf(r.eligibleServicePlansFrom(r.eligibleServices()))
+	}
+
+	serviceInstances = r.reapServiceKeysOf(r.matchingInstancesOf(serviceInstances))
+	serviceInstances = r.unusedInstancesOf(r.expiredInstancesOf(r.unretainedInstancesOf(serviceInstances)))
+	return r.overQuotaInstancesOf(serviceInstances)
+}
+
+// finish runs the phases which follow reaping and then signals that reaping is done.
+func (r *Reaper) finish() {
+	if r.options.EmptySpaceNamePattern != nil && !r.failingFast() {
+		r.deleteEmptySpaces()
+	}
+
+	close(r.done)
+}
+
+// reportErrors waits for reaping to be done and then prints the errors which occurred, returning them as Errors.
+func (r *Reaper) reportErrors() error {
+	<-r.done
+
+	collected := r.errors.collected()
+	for _, err := range collected {
+		r.logger.Error(err.Error(), "kind", string(err.Kind), "resource_type", err.Resource.Type,
+			"resource_name", err.Resource.Name, "resource_guid", err.Resource.Guid)
+	}
+
+	if len(collected) > 0 {
+		return collected
 	}
 
 	return nil
 }
 
+// fail records an error which occurred during reaping.
+func (r *Reaper) fail(err *Error) {
+	r.errors.add(err)
+}
+
+// failingFast reports whether reaping should stop because FailFast is set and a listing or parse error has occurred.
+func (r *Reaper) failingFast() bool {
+	return r.options.FailFast && r.errors.contains(ListingError, ParseError)
+}
+
+// scheduleGate records that the schedule stopped permitting deletions during a run.
+type scheduleGate struct {
+	closed bool
+	mutex  sync.Mutex
+}
+
+// scheduled reports whether the schedule, if any, still permits deletions. Once it stops doing so, it reports why
+// and permits no further deletions for the rest of the run.
+func (r *Reaper) scheduled() bool {
+	if r.options.Schedule == nil {
+		return true
+	}
+
+	r.schedule.mutex.Lock()
+	defer r.schedule.mutex.Unlock()
+
+	if r.schedule.closed {
+		return false
+	}
+
+	if permitted, reason := r.options.Schedule.Permits(r.currentTime()); !permitted {
+		r.schedule.closed = true
+		r.logger.Warn(fmt.Sprintf("Stopping deletions: %s", reason), "reason", reason)
+		return false
+	}
+	return true
+}
+
 func (r *Reaper) eligibleServices() <-chan cloudfoundry.Service {
 	output := make(chan cloudfoundry.Service, 1)
 
 	go func() {
 		defer close(output)
 
-		services, err := r.cf.GetServices(r.serviceName)
+		services, err := r.cf.GetServices(r.options.ServiceName)
 		if err != nil {
-			r.errorChannel <- err
+			r.fail(newError(ListingError, Resource{Type: "service", Name: r.options.ServiceName}, "", err))
 			return
 		}
 
 		if len(services) == 0 {
-			fmt.Fprintf(r.output, "No services of type '%s' found", r.serviceName)
+			r.logger.Warn(fmt.Sprintf("No services of type '%s' found", r.options.ServiceName), "service_name", r.options.ServiceName)
 			return
 		}
 
@@ -99,12 +328,15 @@ func (r *Reaper) eligibleServicePlansFrom(services <-chan cloudfoundry.Service)
 		for service := range services {
 			servicePlans, err := r.cf.GetServicePlans(service.Metadata.Guid)
 			if err != nil {
-				r.errorChannel <- err
-				return
+				r.fail(newError(ListingError, Resource{Type: "service", Guid: service.Metadata.Guid}, "", err))
+				if r.failingFast() {
+					return
+				}
+				continue
 			}
 
 			for _, servicePlan := range servicePlans {
-				if servicePlan.Entity.Name == r.planName {
+				if servicePlan.Entity.Name == r.options.PlanName {
 					output <- servicePlan
 				}
 			}
@@ -114,7 +346,7 @@ func (r *Reaper) eligibleServicePlansFrom(services <-chan cloudfoundry.Service)
 	return output
 }
 
-func (r *Reaper) expiredInstancesOf(servicePlans <-chan cloudfoundry.ServicePlan) <-chan cloudfoundry.ServiceInstance {
+func (r *Reaper) instancesOf(servicePlans <-chan cloudfoundry.ServicePlan) <-chan cloudfoundry.ServiceInstance {
 	output := make(chan cloudfoundry.ServiceInstance, cloudfoundry.MaximumResultsPerPage)
 
 	go func() {
@@ -124,53 +356,339 @@ func (r *Reaper) expiredInstancesOf(servicePlans <-chan cloudfoundry.ServicePlan
 			serviceInstances, serviceInstanceErrors := r.cf.GetServicePlanInstances(servicePlan.Metadata.Guid)
 
 			for serviceInstance := range serviceInstances {
-				serviceInstanceExpired, err := expired(serviceInstance.Metadata.CreatedAt, r.expiryInterval, r.currentTime)
-				if err != nil {
-					r.errorChannel <- err
+				output <- serviceInstance
+			}
+
+			r.mergeListingErrors(serviceInstanceErrors)
+		}
+	}()
+
+	return output
+}
+
+func (r *Reaper) userProvidedInstances() <-chan cloudfoundry.ServiceInstance {
+	output := make(chan cloudfoundry.ServiceInstance, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		serviceInstances, serviceInstanceErrors := r.cf.GetUserProvidedServiceInstances()
+
+		for serviceInstance := range serviceInstances {
+			output <- serviceInstance
+		}
+
+		r.mergeListingErrors(serviceInstanceErrors)
+	}()
+
+	return output
+}
+
+func (r *Reaper) matchingInstancesOf(serviceInstances <-chan cloudfoundry.ServiceInstance) <-chan cloudfoundry.ServiceInstance {
+	output := make(chan cloudfoundry.ServiceInstance, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		for serviceInstance := range serviceInstances {
+			if r.matches(serviceInstance) {
+				output <- serviceInstance
+			}
+		}
+	}()
+
+	return output
+}
+
+func (r *Reaper) expiredInstancesOf(serviceInstances <-chan cloudfoundry.ServiceInstance) <-chan cloudfoundry.ServiceInstance {
+	output := make(chan cloudfoundry.ServiceInstance, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+		defer drain(serviceInstances)
+
+		for serviceInstance := range serviceInstances {
+			referenceTime, err := r.referenceTime(serviceInstance)
+			if err != nil {
+				r.fail(newError(ParseError, serviceInstanceResource(serviceInstance), "unable to determine age of service instance", err))
+				if r.failingFast() {
 					return
 				}
+				continue
+			}
 
-				if serviceInstanceExpired {
-					output <- serviceInstance
-				}
+			if r.expired(referenceTime) {
+				output <- serviceInstance
+			}
+		}
+	}()
+
+	return output
+}
+
+func (r *Reaper) unusedInstancesOf(serviceInstances <-chan cloudfoundry.ServiceInstance) <-chan cloudfoundry.ServiceInstance {
+	if !r.options.UnboundOnly && !r.options.WithoutServiceKeys {
+		return serviceInstances
+	}
+
+	output := make(chan cloudfoundry.ServiceInstance, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		for serviceInstance := range serviceInstances {
+			unused, err := r.unused(serviceInstance)
+			if err != nil {
+				r.fail(newError(ListingError, serviceInstanceResource(serviceInstance), "unable to determine whether service instance is in use", err))
+				continue
 			}
 
-			mergeErrors(serviceInstanceErrors, r.errorChannel)
+			if unused {
+				output <- serviceInstance
+			}
 		}
 	}()
 
 	return output
 }
 
+func (r *Reaper) unused(serviceInstance cloudfoundry.ServiceInstance) (bool, error) {
+	if r.options.UnboundOnly {
+		serviceBindings, err := r.cf.GetServiceBindings(serviceInstance.Metadata.Guid)
+		if err != nil {
+			return false, err
+		}
+		if len(serviceBindings) > 0 {
+			return false, nil
+		}
+	}
+
+	if r.options.WithoutServiceKeys {
+		serviceKeys, err := r.cf.GetServiceKeys(serviceInstance.Metadata.Guid)
+		if err != nil {
+			return false, err
+		}
+		if len(serviceKeys) > 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func (r *Reaper) delete(serviceInstances <-chan cloudfoundry.ServiceInstance) {
 	go func() {
-		defer close(r.errorChannel)
+		defer r.finish()
 
 		for serviceInstance := range serviceInstances {
-			if r.reap {
-				err := r.cf.DeleteServiceInstance(serviceInstance.Metadata.Guid, r.recursive)
+			if r.failingFast() || (r.options.Reap && !r.scheduled()) {
+				continue
+			}
+
+			if r.options.Reap {
+				r.checkpointProgress(serviceInstance, checkpoint.Checkpoint.Attempted)
+
+				if err := r.snapshot(serviceInstance); err != nil {
+					failure := newError(DeletionError, serviceInstanceResource(serviceInstance), "unable to snapshot service instance", err)
+					r.checkpointProgress(serviceInstance, checkpoint.Checkpoint.Failed)
+					r.recordHistory(serviceInstance, failure)
+					r.deletionFailed(failure)
+					continue
+				}
+
+				purged, inProgress, err := r.deleteServiceInstance(serviceInstance)
 				if err != nil {
-					r.errorChannel <- fmt.Errorf("unable to delete service instance: %s %s (%s)\n",
-						serviceInstance.Entity.Name, serviceInstance.Metadata.Guid, err)
+					failure := newError(DeletionError, serviceInstanceResource(serviceInstance), "unable to delete service instance", err)
+					r.checkpointProgress(serviceInstance, checkpoint.Checkpoint.Failed)
+					r.recordHistory(serviceInstance, failure)
+					r.deletionFailed(failure)
+				} else {
+					r.checkpointProgress(serviceInstance, checkpoint.Checkpoint.Completed)
+					r.recordHistory(serviceInstance, nil)
+					r.countReaped()
+					r.countSavings(serviceInstance)
+				}
+				if purged {
+					cost, costFields := r.describeCost(serviceInstance)
+					r.logger.Info(fmt.Sprintf("%s %s purged%s", serviceInstance.Entity.Name, serviceInstance.Metadata.Guid, cost),
+						append([]interface{}{"service_instance_name", serviceInstance.Entity.Name, "service_instance_guid", serviceInstance.Metadata.Guid, "purged", true}, costFields...)...)
+					continue
 				}
+				if inProgress {
+					r.emptying.deleting(serviceInstance.Entity.SpaceGuid, serviceInstance.Metadata.Guid)
+					r.logger.Info(fmt.Sprintf("%s %s deletion in progress", serviceInstance.Entity.Name, serviceInstance.Metadata.Guid),
+						"service_instance_name", serviceInstance.Entity.Name, "service_instance_guid", serviceInstance.Metadata.Guid, "in_progress", true)
+				}
+			} else {
+				r.emptying.wouldDelete(serviceInstance.Entity.SpaceGuid, func(usage *cloudfoundry.SpaceUsage) { usage.ServiceInstances++ })
 			}
 
-			fmt.Fprintf(r.output, "%s %s\n", serviceInstance.Entity.Name, serviceInstance.Metadata.Guid)
+			cost, costFields := r.describeCost(serviceInstance)
+			r.logger.Info(fmt.Sprintf("%s %s%s", serviceInstance.Entity.Name, serviceInstance.Metadata.Guid, cost),
+				append([]interface{}{"service_instance_name", serviceInstance.Entity.Name, "service_instance_guid", serviceInstance.Metadata.Guid, "reaped", r.options.Reap}, costFields...)...)
 		}
 	}()
 }
 
-func mergeErrors(from <-chan error, to chan<- error) {
-	for err := range from {
-		to <- err
+// deleteServiceInstance deletes the given service instance via its broker, falling back to purging it if permitted,
+// and records the outcome in the audit trail. It returns an error only if the service instance was not deleted.
+// inProgress is true if the broker accepted the deletion but has yet to finish it.
+func (r *Reaper) deleteServiceInstance(serviceInstance cloudfoundry.ServiceInstance) (purged bool, inProgress bool, err error) {
+	if serviceInstance.UserProvided() {
+		err = r.cf.DeleteUserProvidedServiceInstance(serviceInstance.Metadata.Guid)
+	} else if r.options.Recursive {
+		inProgress, err = r.cascade(serviceInstance)
+	} else {
+		inProgress, err = r.cf.DeleteServiceInstance(serviceInstance.Metadata.Guid, false)
+	}
+	if err == nil {
+		detail := ""
+		if inProgress {
+			detail = "deletion in progress"
+		}
+		r.reportAuditFailure(r.audit(serviceInstance, audit.Deleted, detail))
+		return false, inProgress, nil
+	}
+
+	if !r.purgeable(err) {
+		r.reportAuditFailure(r.audit(serviceInstance, audit.Failed, err.Error()))
+		return false, false, err
+	}
+
+	purgeErr := r.cf.PurgeServiceInstance(serviceInstance.Metadata.Guid)
+	if purgeErr != nil {
+		err = fmt.Errorf("%s; purge also failed: %s", err, purgeErr)
+		r.reportAuditFailure(r.audit(serviceInstance, audit.Failed, err.Error()))
+		return false, false, err
 	}
+
+	r.reportAuditFailure(r.audit(serviceInstance, audit.Purged, err.Error()))
+	return true, false, nil
 }
 
-func expired(creationTimeString string, expiryInterval time.Duration, currentTime func() time.Time) (bool, error) {
-	creationTime, err := time.Parse(time.RFC3339, creationTimeString)
+func (r *Reaper) reportAuditFailure(err *Error) {
 	if err != nil {
-		return false, fmt.Errorf("invalid service instance creation time: %s", err)
+		r.fail(err)
 	}
-	expiryTime := creationTime.Add(expiryInterval)
-	return currentTime().After(expiryTime), nil
+}
+
+func (r *Reaper) purgeable(deleteErr error) bool {
+	return r.options.Purge || (r.options.PurgeOrphans && cloudfoundry.IsBrokerUnreachable(deleteErr))
+}
+
+func (r *Reaper) audit(serviceInstance cloudfoundry.ServiceInstance, action string, detail string) *Error {
+	return r.record(serviceInstanceResource(serviceInstance), audit.Entry{
+		Action:              action,
+		ServiceInstanceGuid: serviceInstance.Metadata.Guid,
+		ServiceInstanceName: serviceInstance.Entity.Name,
+		Detail:              detail,
+	})
+}
+
+// recordHistory adds the outcome of attempting to delete a service instance to the history of the run, if any.
+func (r *Reaper) recordHistory(serviceInstance cloudfoundry.ServiceInstance, failure *Error) {
+	if r.options.History == nil {
+		return
+	}
+
+	deletion := history.Deletion{
+		ServiceInstanceGuid: serviceInstance.Metadata.Guid,
+		ServiceInstanceName: serviceInstance.Entity.Name,
+		SpaceGuid:           serviceInstance.Entity.SpaceGuid,
+	}
+	if failure != nil {
+		deletion.Error = failure.Err.Error()
+	}
+	r.options.History.Record(deletion)
+}
+
+// record timestamps the given entry and adds it to the audit trail, if any.
+func (r *Reaper) record(resource Resource, entry audit.Entry) *Error {
+	if r.options.AuditTrail == nil {
+		return nil
+	}
+
+	entry.Time = r.currentTime().UTC().Format(time.RFC3339)
+	err := r.options.AuditTrail.Record(entry)
+	if err != nil {
+		outcome := "was " + entry.Action
+		if entry.Action == audit.Failed {
+			outcome = "could not be deleted"
+		}
+		return &Error{Kind: AuditError, Resource: resource, Err: err,
+			message: fmt.Sprintf("%s %s and could not be audited: %s", resource.Type, outcome, err)}
+	}
+	return nil
+}
+
+// matches applies the filters which need nothing more than the listed service instance.
+func (r *Reaper) matches(serviceInstance cloudfoundry.ServiceInstance) bool {
+	if r.options.NamePattern != nil && !r.options.NamePattern.MatchString(serviceInstance.Entity.Name) {
+		return false
+	}
+
+	if r.options.SpaceGuid != "" && serviceInstance.Entity.SpaceGuid != r.options.SpaceGuid {
+		return false
+	}
+
+	if r.options.ProtectionTag != "" {
+		for _, tag := range serviceInstance.Entity.Tags {
+			if tag == r.options.ProtectionTag {
+				return false
+			}
+		}
+	}
+
+	if len(r.options.LastOperationStates) == 0 {
+		return true
+	}
+
+	for _, state := range r.options.LastOperationStates {
+		if serviceInstance.Entity.LastOperation.State == state {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Reaper) snapshot(serviceInstance cloudfoundry.ServiceInstance) error {
+	if r.options.Archive == nil {
+		return nil
+	}
+
+	serviceInstanceSnapshot, err := snapshot.Take(r.cf, serviceInstance, r.currentTime())
+	if err != nil {
+		return err
+	}
+
+	_, err = r.options.Archive.Save(serviceInstanceSnapshot)
+	return err
+}
+
+func (r *Reaper) mergeListingErrors(errors <-chan error) {
+	for err := range errors {
+		r.fail(newError(ListingError, Resource{}, "", err))
+	}
+}
+
+func drain(serviceInstances <-chan cloudfoundry.ServiceInstance) {
+	for range serviceInstances {
+	}
+}
+
+// expired determines whether a service instance or app has expired, either by being older than the expiry interval,
+// in business days if a calendar is given, or, if a cutoff is given, by having a reference time before the cutoff.
+func (r *Reaper) expired(referenceTime time.Time) bool {
+	if !r.options.Cutoff.IsZero() {
+		return referenceTime.Before(r.options.Cutoff)
+	}
+	if r.options.Calendar != nil {
+		return r.options.Calendar.BusinessTimeBetween(referenceTime, r.currentTime()) > r.options.ExpiryInterval
+	}
+	return expired(referenceTime, r.options.ExpiryInterval, r.currentTime)
+}
+
+func expired(referenceTime time.Time, expiryInterval time.Duration, currentTime func() time.Time) bool {
+	expiryTime := referenceTime.Add(expiryInterval)
+	return currentTime().After(expiryTime)
 }
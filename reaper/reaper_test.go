@@ -22,9 +22,23 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/service-instance-reaper/audit"
+	"github.com/pivotal-cf/service-instance-reaper/audit/auditfakes"
+	"github.com/pivotal-cf/service-instance-reaper/calendar"
+	"github.com/pivotal-cf/service-instance-reaper/checkpoint"
+	"github.com/pivotal-cf/service-instance-reaper/checkpoint/checkpointfakes"
 	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
 	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry/cloudfoundryfakes"
+	"github.com/pivotal-cf/service-instance-reaper/confirm"
+	"github.com/pivotal-cf/service-instance-reaper/confirm/confirmfakes"
+	"github.com/pivotal-cf/service-instance-reaper/history"
+	"github.com/pivotal-cf/service-instance-reaper/history/historyfakes"
+	"github.com/pivotal-cf/service-instance-reaper/logging"
+	"github.com/pivotal-cf/service-instance-reaper/pricing"
 	reaperpkg "github.com/pivotal-cf/service-instance-reaper/reaper"
+	"github.com/pivotal-cf/service-instance-reaper/snapshot"
+	"github.com/pivotal-cf/service-instance-reaper/snapshot/snapshotfakes"
+	"regexp"
 	"time"
 )
 
@@ -43,193 +57,2462 @@ const (
 	testExpiredFreePlanServiceInstanceName2   = "test-expired-free-plan-service-instance-name-2"
 	testNotExpiredFreePlanServiceInstanceGuid = "test-not-expired-free-plan-service-instance-guid"
 	testNotExpiredFreePlanServiceInstanceName = "test-not-expired-free-plan-service-instance-name"
+	testSpaceGuid                             = "test-space-guid"
 )
 
 var _ = Describe("Reaper", func() {
 	var (
-		fakeCfClient       *cloudfoundryfakes.FakeClient
-		expireAfter10Hours = 10 * time.Hour
-		reap               = true
-		recursive          = false
-		testError          = errors.New("test error")
-		reaper             reaperpkg.Reaper
-		reaperOutput       *gbytes.Buffer
-		reaperError        error
+		fakeCfClient        *cloudfoundryfakes.FakeClient
+		expireAfter10Hours  = 10 * time.Hour
+		reap                = true
+		cutoff              time.Time
+		businessCalendar    *calendar.Calendar
+		schedule            *calendar.Schedule
+		clock               func() time.Time
+		recursive           = false
+		cascadeAttempts     int
+		testError           = errors.New("test error")
+		reaper              reaperpkg.Reaper
+		reaperOutput        *gbytes.Buffer
+		reaperError         error
+		summary             reaperpkg.Summary
+		archive             snapshot.Archive
+		confirmer           confirm.Confirmer
+		confirmBatch        bool
+		maxDeletions        int
+		failFast            bool
+		checkpointer        checkpoint.Checkpoint
+		resume              bool
+		historyRecorder     history.Recorder
+		prices              *pricing.Table
+		ageBasis            reaperpkg.AgeBasis
+		unboundOnly         bool
+		withoutServiceKeys  bool
+		lastOperationStates []string
+		purge               bool
+		purgeOrphans        bool
+		auditTrail          audit.Trail
+		userProvided        bool
+		namePattern         *regexp.Regexp
+		spaceGuid           string
+
+		serviceKeyExpiryInterval time.Duration
+		serviceKeyNamePattern    *regexp.Regexp
+
+		apps         bool
+		appStates    []string
+		deleteRoutes bool
+
+		protectionTag  string
+		quotaThreshold int
+		quotaTarget    int
+
+		keepNewest   int
+		keepGrouping reaperpkg.Grouping
+
+		emptySpaceNamePattern    *regexp.Regexp
+		emptySpaceExpiryInterval time.Duration
+
+		serviceBindings            []cloudfoundry.ServiceBinding
+		serviceBindingsError       error
+		serviceBindingDeleteEvents []cloudfoundry.Event
 	)
 
-	BeforeEach(func() {
-		fakeCfClient = fakeCfClientFactory(
-			successfulGetServicesResponse(),
-			successfulGetServicePlansResponse(),
-			successfulGetServicePlanInstancesResponse(),
-		)
-		reaperOutput = gbytes.NewBuffer()
-		reap = true
-		recursive = false
-	})
+	BeforeEach(func() {
+		fakeCfClient = fakeCfClientFactory(
+			successfulGetServicesResponse(),
+			successfulGetServicePlansResponse(),
+			successfulGetServicePlanInstancesResponse(),
+		)
+		reaperOutput = gbytes.NewBuffer()
+		reap = true
+		cutoff = time.Time{}
+		businessCalendar = nil
+		schedule = nil
+		clock = frozenTime
+		recursive = false
+		cascadeAttempts = 0
+		archive = nil
+		confirmer = nil
+		confirmBatch = false
+		maxDeletions = 0
+		failFast = false
+		checkpointer = nil
+		resume = false
+		historyRecorder = nil
+		prices = nil
+		ageBasis = reaperpkg.CreatedAt
+		unboundOnly = false
+		withoutServiceKeys = false
+		lastOperationStates = nil
+		purge = false
+		purgeOrphans = false
+		auditTrail = nil
+		userProvided = false
+		namePattern = nil
+		spaceGuid = ""
+		serviceKeyExpiryInterval = 0
+		serviceKeyNamePattern = nil
+		apps = false
+		appStates = nil
+		deleteRoutes = false
+		protectionTag = ""
+		quotaThreshold = 0
+		quotaTarget = 0
+		keepNewest = 0
+		keepGrouping = reaperpkg.BySpace
+		emptySpaceNamePattern = nil
+		emptySpaceExpiryInterval = 0
+		serviceBindings = nil
+		serviceBindingsError = nil
+		serviceBindingDeleteEvents = nil
+	})
+
+	JustBeforeEach(func() {
+		reaper = reaperpkg.NewReaper(fakeCfClient, clock, logging.New(reaperOutput, logging.Info, logging.Text))
+		summary, reaperError = reaper.Reap(reaperpkg.Options{
+			ServiceName:     testServiceName,
+			PlanName:        testFreeServicePlanName,
+			ExpiryInterval:  expireAfter10Hours,
+			Cutoff:          cutoff,
+			Calendar:        businessCalendar,
+			Schedule:        schedule,
+			UserProvided:    userProvided,
+			NamePattern:     namePattern,
+			SpaceGuid:       spaceGuid,
+			Reap:            reap,
+			Recursive:       recursive,
+			CascadeAttempts: cascadeAttempts,
+			Archive:         archive,
+			AgeBasis:        ageBasis,
+			Confirmer:       confirmer,
+			MaxDeletions:    maxDeletions,
+			FailFast:        failFast,
+			Checkpoint:      checkpointer,
+			Resume:          resume,
+			History:         historyRecorder,
+			Prices:          prices,
+			ConfirmBatch:    confirmBatch,
+
+			UnboundOnly:        unboundOnly,
+			WithoutServiceKeys: withoutServiceKeys,
+
+			LastOperationStates: lastOperationStates,
+			Purge:               purge,
+			PurgeOrphans:        purgeOrphans,
+			AuditTrail:          auditTrail,
+
+			ProtectionTag:  protectionTag,
+			QuotaThreshold: quotaThreshold,
+			QuotaTarget:    quotaTarget,
+
+			KeepNewest:   keepNewest,
+			KeepGrouping: keepGrouping,
+
+			ServiceKeyExpiryInterval: serviceKeyExpiryInterval,
+			ServiceKeyNamePattern:    serviceKeyNamePattern,
+
+			Apps:         apps,
+			AppStates:    appStates,
+			DeleteRoutes: deleteRoutes,
+
+			EmptySpaceNamePattern:    emptySpaceNamePattern,
+			EmptySpaceExpiryInterval: emptySpaceExpiryInterval,
+		})
+	})
+
+	Describe("fetching services", func() {
+		It("fetches a list of services with the given name", func() {
+			Expect(reaperError).NotTo(HaveOccurred())
+			Expect(fakeCfClient.GetServicesCallCount()).To(Equal(1), "Unexpected number of calls to GetServices")
+			serviceName := fakeCfClient.GetServicesArgsForCall(0)
+			Expect(serviceName).To(Equal(testServiceName))
+		})
+
+		Context("when fetching the list of services fails", func() {
+			BeforeEach(func() {
+				fakeCfClient.GetServicesReturns([]cloudfoundry.Service{}, testError)
+			})
+
+			It("logs the error and fails", func() {
+				expectErrors(reaperError, reaperOutput, testError)
+			})
+		})
+
+		Context("when the list of services is empty", func() {
+			BeforeEach(func() {
+				fakeCfClient.GetServicesReturns([]cloudfoundry.Service{}, nil)
+			})
+
+			It("reports that it has no work to do", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(reaperOutput).To(gbytes.Say("No services of type '%s' found", testServiceName))
+			})
+		})
+	})
+
+	Describe("fetching service plans", func() {
+		It("fetches a list of service plans for the service with the given guid", func() {
+			Expect(reaperError).NotTo(HaveOccurred())
+			Expect(fakeCfClient.GetServicePlansCallCount()).To(Equal(1), "Unexpected number of calls to GetServicePlans")
+			serviceGuid := fakeCfClient.GetServicePlansArgsForCall(0)
+			Expect(serviceGuid).To(Equal(testServiceGuid))
+		})
+
+		Context("when fetching the list of service plans fails", func() {
+			BeforeEach(func() {
+				fakeCfClient = fakeCfClientFactory(
+					successfulGetServicesResponse(),
+					servicePlanResult{nil, testError},
+					successfulGetServicePlanInstancesResponse(),
+				)
+			})
+
+			It("logs the error and fails", func() {
+				expectErrors(reaperError, reaperOutput, testError)
+			})
+		})
+	})
+
+	Describe("fetching service plan instances", func() {
+		It("fetches a list of instances of the given plan", func() {
+			Expect(reaperError).NotTo(HaveOccurred())
+			Expect(fakeCfClient.GetServicePlanInstancesCallCount()).To(Equal(1), "Unexpected number of calls to GetServicePlanInstances")
+			servicePlanGuid := fakeCfClient.GetServicePlanInstancesArgsForCall(0)
+			Expect(servicePlanGuid).To(Equal(testFreeServicePlanGuid))
+		})
+
+		Context("when fetching the list of service plan instances fails", func() {
+			BeforeEach(func() {
+				fakeCfClient = fakeCfClientFactory(
+					successfulGetServicesResponse(),
+					successfulGetServicePlansResponse(),
+					serviceInstanceResult{nil, testError},
+				)
+			})
+
+			It("logs the error and fails", func() {
+				expectErrors(reaperError, reaperOutput, testError)
+			})
+		})
+	})
+
+	Describe("reaping", func() {
+		Context("if the service plan instance response contains a malformed time string", func() {
+			BeforeEach(func() {
+				servicePlanInstancesResponseWithInvalidTime := successfulGetServicePlanInstancesResponse()
+				servicePlanInstancesResponseWithInvalidTime.serviceInstances[0].Metadata.CreatedAt = "rubbish"
+
+				fakeCfClient = fakeCfClientFactory(
+					successfulGetServicesResponse(),
+					successfulGetServicePlansResponse(),
+					servicePlanInstancesResponseWithInvalidTime,
+				)
+			})
+
+			It("logs the error and fails", func() {
+				expectErrorsMatching(reaperError, reaperOutput, "invalid service instance creation time")
+			})
+
+			It("still reaps the other service instances", func() {
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+				deletedServiceInstanceGuid, _ := fakeCfClient.DeleteServiceInstanceArgsForCall(0)
+				Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
+			})
+
+			Context("when failing fast", func() {
+				BeforeEach(func() { failFast = true })
+
+				It("reaps nothing further and fails", func() {
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+					expectErrorsMatching(reaperError, reaperOutput, "invalid service instance creation time")
+				})
+			})
+		})
+
+		Describe("age basis", func() {
+			var serviceInstance cloudfoundry.ServiceInstance
+
+			BeforeEach(func() {
+				serviceInstance = cloudfoundry.ServiceInstance{
+					Metadata: cloudfoundry.Metadata{
+						Guid:      testExpiredFreePlanServiceInstanceGuid1,
+						CreatedAt: fifteenHoursAgo().Format(time.RFC3339),
+					},
+					Entity: cloudfoundry.ServiceInstanceEntity{
+						Name:      testExpiredFreePlanServiceInstanceName1,
+						SpaceGuid: testSpaceGuid,
+					},
+				}
+			})
+
+			BeforeEach(func() {
+				// Stubs are evaluated lazily so that nested contexts can customise the data they return
+				fakeCfClient.GetServicePlanInstancesStub = func(string) (chan cloudfoundry.ServiceInstance, chan error) {
+					return serviceInstanceChannels([]cloudfoundry.ServiceInstance{serviceInstance}, nil)
+				}
+				fakeCfClient.GetServiceBindingsStub = func(string) ([]cloudfoundry.ServiceBinding, error) {
+					return serviceBindings, serviceBindingsError
+				}
+				fakeCfClient.GetServiceBindingDeleteEventsStub = func(string) ([]cloudfoundry.Event, error) {
+					return serviceBindingDeleteEvents, nil
+				}
+			})
+
+			Context("when the age basis is updated_at", func() {
+				BeforeEach(func() { ageBasis = reaperpkg.UpdatedAt })
+
+				Context("when the service instance was updated recently", func() {
+					BeforeEach(func() {
+						serviceInstance.Metadata.UpdatedAt = tenHoursAgo().Format(time.RFC3339)
+					})
+
+					It("does not treat it as expired", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(reaperOutput).NotTo(gbytes.Say(testExpiredFreePlanServiceInstanceGuid1))
+					})
+				})
+
+				Context("when the service instance has never been updated", func() {
+					It("falls back to the creation time", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(reaperOutput).To(gbytes.Say(testExpiredFreePlanServiceInstanceGuid1))
+					})
+				})
+
+				Context("when the update time is malformed", func() {
+					BeforeEach(func() {
+						serviceInstance.Metadata.UpdatedAt = "rubbish"
+					})
+
+					It("logs the error and fails", func() {
+						expectErrorsMatching(reaperError, reaperOutput, "invalid service instance last update time")
+					})
+				})
+			})
+
+			Context("when the age basis is last_operation", func() {
+				BeforeEach(func() { ageBasis = reaperpkg.LastOperation })
+
+				Context("when the last operation happened recently", func() {
+					BeforeEach(func() {
+						serviceInstance.Entity.LastOperation.UpdatedAt = tenHoursAgo().Format(time.RFC3339)
+					})
+
+					It("does not treat it as expired", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(reaperOutput).NotTo(gbytes.Say(testExpiredFreePlanServiceInstanceGuid1))
+					})
+				})
+
+				Context("when the last operation happened long ago", func() {
+					BeforeEach(func() {
+						serviceInstance.Entity.LastOperation.UpdatedAt = tenHoursOneSecondAgo().Format(time.RFC3339)
+					})
+
+					It("treats it as expired", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(reaperOutput).To(gbytes.Say(testExpiredFreePlanServiceInstanceGuid1))
+					})
+				})
+			})
+
+			Context("when the age basis is last_bound", func() {
+				BeforeEach(func() { ageBasis = reaperpkg.LastBound })
+
+				Context("when the service instance is currently bound", func() {
+					BeforeEach(func() {
+						serviceBindings = []cloudfoundry.ServiceBinding{{Metadata: cloudfoundry.Metadata{Guid: "binding-guid"}}}
+					})
+
+					It("does not treat it as expired", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(fakeCfClient.GetServiceBindingsArgsForCall(0)).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
+						Expect(reaperOutput).NotTo(gbytes.Say(testExpiredFreePlanServiceInstanceGuid1))
+					})
+				})
+
+				Context("when the service instance was unbound recently", func() {
+					BeforeEach(func() {
+						serviceBindingDeleteEvents = []cloudfoundry.Event{
+							serviceBindingDeleteEvent("another-service-instance-guid", tenHoursOneSecondAgo()),
+							serviceBindingDeleteEvent(testExpiredFreePlanServiceInstanceGuid1, tenHoursAgo()),
+						}
+					})
+
+					It("does not treat it as expired", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(fakeCfClient.GetServiceBindingDeleteEventsArgsForCall(0)).To(Equal(testSpaceGuid))
+						Expect(reaperOutput).NotTo(gbytes.Say(testExpiredFreePlanServiceInstanceGuid1))
+					})
+				})
+
+				Context("when the service instance was unbound long ago", func() {
+					BeforeEach(func() {
+						serviceBindingDeleteEvents = []cloudfoundry.Event{
+							serviceBindingDeleteEvent(testExpiredFreePlanServiceInstanceGuid1, tenHoursOneSecondAgo()),
+						}
+					})
+
+					It("treats it as expired", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(reaperOutput).To(gbytes.Say(testExpiredFreePlanServiceInstanceGuid1))
+					})
+				})
+
+				Context("when the service instance has never been bound", func() {
+					It("measures its age from its creation time", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(reaperOutput).To(gbytes.Say(testExpiredFreePlanServiceInstanceGuid1))
+					})
+				})
+
+				Context("when several service instances are in the same space", func() {
+					BeforeEach(func() {
+						otherServiceInstance := serviceInstance
+						otherServiceInstance.Metadata.Guid = testExpiredFreePlanServiceInstanceGuid2
+						otherServiceInstance.Entity.Name = testExpiredFreePlanServiceInstanceName2
+						fakeCfClient.GetServicePlanInstancesStub = func(string) (chan cloudfoundry.ServiceInstance, chan error) {
+							return serviceInstanceChannels([]cloudfoundry.ServiceInstance{serviceInstance, otherServiceInstance}, nil)
+						}
+					})
+
+					It("lists the events of the space only once", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(fakeCfClient.GetServiceBindingDeleteEventsCallCount()).To(Equal(1))
+						Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2))
+					})
+				})
+
+				Context("when fetching the service bindings fails", func() {
+					BeforeEach(func() {
+						serviceBindingsError = testError
+					})
+
+					It("logs the error and fails", func() {
+						expectErrors(reaperError, reaperOutput, testError)
+						Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+					})
+				})
+			})
+		})
+
+		Context("when only unbound service instances are to be reaped", func() {
+			BeforeEach(func() {
+				unboundOnly = true
+				recursive = true
+				fakeCfClient.GetServiceBindingsStub = func(serviceInstanceGuid string) ([]cloudfoundry.ServiceBinding, error) {
+					if serviceInstanceGuid == testExpiredFreePlanServiceInstanceGuid1 {
+						return []cloudfoundry.ServiceBinding{{Metadata: cloudfoundry.Metadata{Guid: "binding-guid"}}}, nil
+					}
+					return []cloudfoundry.ServiceBinding{}, nil
+				}
+			})
+
+			It("deletes only the expired service instances without bindings, even when recursive", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(fakeCfClient.GetServiceKeysCallCount()).To(Equal(1), "Unexpected number of GetServiceKeys invocations")
+				Expect(fakeCfClient.GetServiceKeysArgsForCall(0)).To(Equal(testExpiredFreePlanServiceInstanceGuid2), "Service keys should only be listed to delete them")
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+				deletedServiceInstanceGuid, _ := fakeCfClient.DeleteServiceInstanceArgsForCall(0)
+				Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
+			})
+
+			Context("when service instances without keys are also required", func() {
+				BeforeEach(func() {
+					withoutServiceKeys = true
+					fakeCfClient.GetServiceKeysReturns([]cloudfoundry.ServiceKey{{Metadata: cloudfoundry.Metadata{Guid: "key-guid"}}}, nil)
+				})
+
+				It("does not delete service instances with service keys", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+				})
+			})
+
+			Context("when the service bindings cannot be fetched", func() {
+				BeforeEach(func() {
+					fakeCfClient.GetServiceBindingsStub = nil
+					fakeCfClient.GetServiceBindingsReturns(nil, testError)
+				})
+
+				It("does not delete the service instances and fails", func() {
+					const errorMessage = "unable to determine whether service instance is in use: %s %s \\(%s\\)\n"
+					expectErrorsMatching(reaperError, reaperOutput,
+						fmt.Sprintf(errorMessage, testExpiredFreePlanServiceInstanceName1, testExpiredFreePlanServiceInstanceGuid1, testError),
+						fmt.Sprintf(errorMessage, testExpiredFreePlanServiceInstanceName2, testExpiredFreePlanServiceInstanceGuid2, testError),
+					)
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+				})
+			})
+		})
+
+		Context("when only service instances in certain last operation states are to be reaped", func() {
+			BeforeEach(func() {
+				lastOperationStates = []string{cloudfoundry.LastOperationFailed}
+
+				serviceInstances := successfulGetServicePlanInstancesResponse()
+				serviceInstances.serviceInstances[0].Entity.LastOperation.State = cloudfoundry.LastOperationSucceeded
+				serviceInstances.serviceInstances[1].Entity.LastOperation.State = cloudfoundry.LastOperationFailed
+				serviceInstances.serviceInstances[2].Entity.LastOperation.State = cloudfoundry.LastOperationFailed
+				fakeCfClient = fakeCfClientFactory(
+					successfulGetServicesResponse(),
+					successfulGetServicePlansResponse(),
+					serviceInstances,
+				)
+			})
+
+			It("deletes only the expired service instances in those states", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+				deletedServiceInstanceGuid, _ := fakeCfClient.DeleteServiceInstanceArgsForCall(0)
+				Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
+			})
+
+			Context("when the broker fails to delete a service instance", func() {
+				BeforeEach(func() {
+					fakeCfClient.DeleteServiceInstanceReturns(false, testError)
+				})
+
+				It("does not purge it unless asked to", func() {
+					expectErrors(reaperError, reaperOutput, testError)
+					Expect(fakeCfClient.PurgeServiceInstanceCallCount()).To(Equal(0), "Unexpected call to PurgeServiceInstance!")
+				})
+
+				Context("when purging is enabled", func() {
+					BeforeEach(func() { purge = true })
+
+					It("purges the service instance", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(fakeCfClient.PurgeServiceInstanceCallCount()).To(Equal(1), "Unexpected number of PurgeServiceInstance invocations")
+						Expect(fakeCfClient.PurgeServiceInstanceArgsForCall(0)).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
+						Expect(reaperOutput).To(gbytes.Say("%s %s purged\n", testExpiredFreePlanServiceInstanceName2, testExpiredFreePlanServiceInstanceGuid2))
+					})
+
+					Context("when purging fails", func() {
+						BeforeEach(func() {
+							fakeCfClient.PurgeServiceInstanceReturns(errors.New("purge error"))
+						})
+
+						It("logs both errors and fails", func() {
+							expectErrorsMatching(reaperError, reaperOutput, "test error; purge also failed: purge error")
+						})
+					})
+				})
+			})
+		})
+
+		Context("when user-provided service instances are to be reaped", func() {
+			BeforeEach(func() {
+				userProvided = true
+				serviceInstances := successfulGetServicePlanInstancesResponse()
+				for i := range serviceInstances.serviceInstances {
+					serviceInstances.serviceInstances[i].Entity.Type = cloudfoundry.UserProvidedServiceInstanceType
+				}
+				fakeCfClient.GetUserProvidedServiceInstancesReturns(serviceInstanceChannels(serviceInstances.serviceInstances, nil))
+			})
+
+			It("deletes only the expired user-provided service instances", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(fakeCfClient.GetServicesCallCount()).To(Equal(0), "Unexpected call to GetServices!")
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+				Expect(fakeCfClient.DeleteUserProvidedServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteUserProvidedServiceInstance invocations")
+				Expect(fakeCfClient.DeleteUserProvidedServiceInstanceArgsForCall(0)).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
+				Expect(fakeCfClient.DeleteUserProvidedServiceInstanceArgsForCall(1)).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
+			})
+
+			Context("when listing user-provided service instances fails", func() {
+				BeforeEach(func() {
+					fakeCfClient.GetUserProvidedServiceInstancesReturns(serviceInstanceChannels(nil, testError))
+				})
+
+				It("logs the error and fails", func() {
+					expectErrors(reaperError, reaperOutput, testError)
+				})
+			})
+		})
+
+		Context("when a name pattern is given", func() {
+			BeforeEach(func() {
+				namePattern = regexp.MustCompile("-name-2$")
+			})
+
+			It("deletes only the expired service instances with matching names", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+				deletedServiceInstanceGuid, _ := fakeCfClient.DeleteServiceInstanceArgsForCall(0)
+				Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
+			})
+		})
+
+		Context("when a space is given", func() {
+			BeforeEach(func() {
+				spaceGuid = testSpaceGuid
+				serviceInstances := successfulGetServicePlanInstancesResponse()
+				serviceInstances.serviceInstances[0].Entity.SpaceGuid = testSpaceGuid
+				fakeCfClient = fakeCfClientFactory(
+					successfulGetServicesResponse(),
+					successfulGetServicePlansResponse(),
+					serviceInstances,
+				)
+			})
+
+			It("deletes only the expired service instances in that space", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+				deletedServiceInstanceGuid, _ := fakeCfClient.DeleteServiceInstanceArgsForCall(0)
+				Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
+			})
+		})
+
+		Context("when a cutoff time is given", func() {
+			BeforeEach(func() {
+				cutoff = frozenTime().Add(-12 * time.Hour)
+			})
+
+			It("deletes only the service instances created before the cutoff, regardless of the expiry interval", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+				deletedServiceInstanceGuid, _ := fakeCfClient.DeleteServiceInstanceArgsForCall(0)
+				Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
+			})
+		})
+
+		Context("when age is measured in business days", func() {
+			BeforeEach(func() {
+				businessCalendar = calendar.New(time.UTC, nil)
+			})
+
+			It("deletes the service instances which are old enough on a weekday", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+			})
+
+			Context("when today is a holiday", func() {
+				BeforeEach(func() {
+					businessCalendar = calendar.New(time.UTC, []time.Time{frozenTime()})
+				})
+
+				It("does not count today towards their age", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+				})
+			})
+		})
+
+		Context("when a maintenance schedule is given", func() {
+			Context("when the current time is within a maintenance window", func() {
+				BeforeEach(func() {
+					window, err := calendar.ParseWindow("Mon-Fri 18:00-22:00")
+					Expect(err).NotTo(HaveOccurred())
+					schedule = calendar.NewSchedule(time.UTC, []calendar.Window{window}, nil)
+				})
+
+				It("deletes the expired service instances", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+					Expect(reaperOutput).NotTo(gbytes.Say("DRY RUN ONLY"))
+				})
+			})
+
+			Context("when the current time is outside every maintenance window", func() {
+				BeforeEach(func() {
+					window, err := calendar.ParseWindow("Mon-Fri 09:00-17:00")
+					Expect(err).NotTo(HaveOccurred())
+					schedule = calendar.NewSchedule(time.UTC, []calendar.Window{window}, nil)
+				})
+
+				It("performs a dry run and reports why", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+					Expect(reaperOutput).To(gbytes.Say("DRY RUN ONLY: 2018-01-24T20:00:00Z is outside the maintenance windows Mon-Fri 09:00-17:00"))
+					Expect(reaperOutput).To(gbytes.Say(testExpiredFreePlanServiceInstanceName1))
+				})
+			})
+
+			Context("when the maintenance window closes during the run", func() {
+				BeforeEach(func() {
+					window, err := calendar.ParseWindow("Mon-Fri 18:00-20:30")
+					Expect(err).NotTo(HaveOccurred())
+					schedule = calendar.NewSchedule(time.UTC, []calendar.Window{window}, nil)
+
+					// Each deletion takes an hour.
+					clock = func() time.Time {
+						return frozenTime().Add(time.Duration(fakeCfClient.DeleteServiceInstanceCallCount()) * time.Hour)
+					}
+				})
+
+				It("stops deleting once the window has closed and skips the remaining candidates", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+					Expect(reaperOutput).To(gbytes.Say("Stopping deletions: 2018-01-24T21:00:00Z is outside the maintenance windows Mon-Fri 18:00-20:30"))
+					Expect(summary.Reaped).To(Equal(1))
+					Expect(summary.Skipped).To(Equal(1))
+				})
+			})
+
+			Context("when the current time is during a blackout", func() {
+				BeforeEach(func() {
+					blackout, err := calendar.ParseBlackout("2018-01-20/2018-01-28")
+					Expect(err).NotTo(HaveOccurred())
+					schedule = calendar.NewSchedule(time.UTC, nil, []calendar.Blackout{blackout})
+				})
+
+				It("performs a dry run and reports why", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+					Expect(reaperOutput).To(gbytes.Say("DRY RUN ONLY: 2018-01-24T20:00:00Z is during the blackout 2018-01-20/2018-01-28"))
+				})
+			})
+		})
+
+		Context("when deletions must be confirmed", func() {
+			var fakeConfirmer *confirmfakes.FakeConfirmer
+
+			BeforeEach(func() {
+				fakeConfirmer = &confirmfakes.FakeConfirmer{}
+				confirmer = fakeConfirmer
+				fakeCfClient.GetSpaceReturns(cloudfoundry.Space{Entity: cloudfoundry.SpaceEntity{Name: "test-space-name"}}, nil)
+				fakeCfClient.GetServiceBindingsReturns([]cloudfoundry.ServiceBinding{{}}, nil)
+			})
+
+			Context("when some are confirmed and some are not", func() {
+				BeforeEach(func() {
+					fakeConfirmer.ConfirmReturnsOnCall(0, confirm.No, nil)
+					fakeConfirmer.ConfirmReturnsOnCall(1, confirm.Yes, nil)
+				})
+
+				It("asks about each service instance and deletes only those confirmed", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeConfirmer.ConfirmCallCount()).To(Equal(2))
+
+					candidate := fakeConfirmer.ConfirmArgsForCall(0)
+					Expect(candidate.Name).To(Equal(testExpiredFreePlanServiceInstanceName1))
+					Expect(candidate.Guid).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
+					Expect(candidate.Age).To(BeNumerically(">", expireAfter10Hours))
+					Expect(candidate.SpaceName).To(Equal("test-space-name"))
+					Expect(candidate.ServiceBindings).To(Equal(1))
+
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+					deletedServiceInstanceGuid, _ := fakeCfClient.DeleteServiceInstanceArgsForCall(0)
+					Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
+				})
+
+				It("looks up each space only once", func() {
+					Expect(fakeCfClient.GetSpaceCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when all are confirmed at once", func() {
+				BeforeEach(func() {
+					fakeConfirmer.ConfirmReturns(confirm.All, nil)
+				})
+
+				It("deletes the rest without asking again", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeConfirmer.ConfirmCallCount()).To(Equal(1))
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+				})
+			})
+
+			Context("when the user quits", func() {
+				BeforeEach(func() {
+					fakeConfirmer.ConfirmReturns(confirm.Quit, nil)
+				})
+
+				It("deletes nothing and asks nothing more", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeConfirmer.ConfirmCallCount()).To(Equal(1))
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+				})
+			})
+
+			Context("when confirmation fails", func() {
+				BeforeEach(func() {
+					fakeConfirmer.ConfirmReturns(confirm.Quit, testError)
+				})
+
+				It("deletes nothing and reports the error", func() {
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+					Expect(reaperError).To(HaveOccurred())
+					Expect(reaperOutput).To(gbytes.Say("unable to confirm deletion of service instance: %s %s \\(%s\\)", testExpiredFreePlanServiceInstanceName1, testExpiredFreePlanServiceInstanceGuid1, testError))
+				})
+			})
+
+			Context("when the space cannot be looked up", func() {
+				BeforeEach(func() {
+					fakeCfClient.GetSpaceReturns(cloudfoundry.Space{}, testError)
+				})
+
+				It("does not delete the service instances and reports the errors", func() {
+					Expect(fakeConfirmer.ConfirmCallCount()).To(Equal(0))
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+					Expect(reaperOutput).To(gbytes.Say("unable to describe service instance: %s %s", testExpiredFreePlanServiceInstanceName1, testExpiredFreePlanServiceInstanceGuid1))
+				})
+			})
+
+			Context("when deletions are confirmed as a batch", func() {
+				BeforeEach(func() {
+					confirmBatch = true
+					fakeConfirmer.ConfirmBatchReturns(confirm.All, nil)
+				})
+
+				It("asks once about all the service instances", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeConfirmer.ConfirmCallCount()).To(Equal(0))
+					Expect(fakeConfirmer.ConfirmBatchCallCount()).To(Equal(1))
+					Expect(fakeConfirmer.ConfirmBatchArgsForCall(0)).To(HaveLen(2))
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+				})
+
+				Context("when the batch is declined", func() {
+					BeforeEach(func() {
+						fakeConfirmer.ConfirmBatchReturns(confirm.Quit, nil)
+					})
+
+					It("deletes nothing", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+					})
+				})
+			})
+
+			Context("when performing a dry run", func() {
+				BeforeEach(func() {
+					reap = false
+				})
+
+				It("asks nothing", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeConfirmer.ConfirmCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when a protection tag is given", func() {
+			BeforeEach(func() {
+				protectionTag = "do-not-reap"
+				serviceInstances := successfulGetServicePlanInstancesResponse()
+				serviceInstances.serviceInstances[0].Entity.Tags = []string{"mysql", "do-not-reap"}
+				fakeCfClient.GetServicePlanInstancesReturns(serviceInstanceChannels(serviceInstances.serviceInstances, nil))
+			})
+
+			It("does not reap the service instances with that tag", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+				deletedServiceInstanceGuid, _ := fakeCfClient.DeleteServiceInstanceArgsForCall(0)
+				Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
+			})
+		})
+
+		Context("when reaping is driven by quota", func() {
+			serviceInstance := func(guid string, spaceGuid string, hoursAgo int) cloudfoundry.ServiceInstance {
+				return cloudfoundry.ServiceInstance{
+					Metadata: cloudfoundry.Metadata{Guid: guid, CreatedAt: frozenTime().Add(-time.Duration(hoursAgo) * time.Hour).Format(time.RFC3339)},
+					Entity:   cloudfoundry.ServiceInstanceEntity{Name: guid, SpaceGuid: spaceGuid},
+				}
+			}
+
+			BeforeEach(func() {
+				quotaThreshold = 90
+				quotaTarget = 70
+				fakeCfClient.GetServicePlanInstancesReturns(serviceInstanceChannels([]cloudfoundry.ServiceInstance{
+					serviceInstance("full-2", "full-space", 20),
+					serviceInstance("full-1", "full-space", 30),
+					serviceInstance("full-3", "full-space", 11),
+					serviceInstance("full-4", "full-space", 40),
+					serviceInstance("roomy-1", "roomy-space", 50),
+					serviceInstance("org-1", "org-limited-space", 50),
+				}, nil))
+				fakeCfClient.GetSpaceStub = func(spaceGuid string) (cloudfoundry.Space, error) {
+					space := cloudfoundry.Space{Entity: cloudfoundry.SpaceEntity{OrganizationGuid: "org-guid"}}
+					if spaceGuid != "org-limited-space" {
+						space.Entity.SpaceQuotaDefinitionGuid = spaceGuid + "-quota"
+					}
+					return space, nil
+				}
+				fakeCfClient.GetSpaceQuotaDefinitionReturns(cloudfoundry.QuotaDefinition{Entity: cloudfoundry.QuotaDefinitionEntity{TotalServices: 10}}, nil)
+				fakeCfClient.GetOrganizationReturns(cloudfoundry.Organization{Entity: cloudfoundry.OrganizationEntity{QuotaDefinitionGuid: "org-quota"}}, nil)
+				fakeCfClient.GetOrganizationQuotaDefinitionReturns(cloudfoundry.QuotaDefinition{Entity: cloudfoundry.QuotaDefinitionEntity{TotalServices: 100}}, nil)
+				fakeCfClient.CountSpaceServiceInstancesStub = func(spaceGuid string) (int, error) {
+					if spaceGuid == "full-space" {
+						return 10, nil
+					}
+					return 9, nil
+				}
+			})
+
+			deletedGuids := func() []string {
+				guids := make([]string, fakeCfClient.DeleteServiceInstanceCallCount())
+				for i := range guids {
+					guids[i], _ = fakeCfClient.DeleteServiceInstanceArgsForCall(i)
+				}
+				return guids
+			}
+
+			It("reaps the oldest service instances in spaces over the threshold until usage reaches the target", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(deletedGuids()).To(Equal([]string{"full-4", "full-1", "full-2"}))
+			})
+
+			It("falls back to the organization quota for spaces without a space quota", func() {
+				Expect(fakeCfClient.GetOrganizationQuotaDefinitionCallCount()).To(Equal(1))
+				Expect(fakeCfClient.GetOrganizationQuotaDefinitionArgsForCall(0)).To(Equal("org-quota"))
+				Expect(fakeCfClient.CountOrganizationServiceInstancesCallCount()).To(Equal(1))
+				Expect(fakeCfClient.CountOrganizationServiceInstancesArgsForCall(0)).To(Equal("org-guid"))
+			})
+
+			Context("when the organization quota is also exceeded", func() {
+				BeforeEach(func() {
+					fakeCfClient.GetOrganizationQuotaDefinitionReturns(cloudfoundry.QuotaDefinition{Entity: cloudfoundry.QuotaDefinitionEntity{TotalServices: 20}}, nil)
+					fakeCfClient.CountOrganizationServiceInstancesReturns(19, nil)
+				})
+
+				It("also reaps the oldest service instances across the organization, counting those reaped for space quotas", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(deletedGuids()).To(Equal([]string{"full-4", "full-1", "full-2", "roomy-1", "org-1"}))
+				})
+			})
+
+			Context("when spaces without a space quota share their organization's quota", func() {
+				BeforeEach(func() {
+					fakeCfClient.GetServicePlanInstancesReturns(serviceInstanceChannels([]cloudfoundry.ServiceInstance{
+						serviceInstance("a-2", "space-a", 45),
+						serviceInstance("b-1", "space-b", 55),
+						serviceInstance("a-1", "space-a", 60),
+						serviceInstance("b-2", "space-b", 35),
+					}, nil))
+					fakeCfClient.GetSpaceStub = nil
+					fakeCfClient.GetSpaceReturns(cloudfoundry.Space{Entity: cloudfoundry.SpaceEntity{OrganizationGuid: "org-guid"}}, nil)
+					fakeCfClient.GetOrganizationQuotaDefinitionReturns(cloudfoundry.QuotaDefinition{Entity: cloudfoundry.QuotaDefinitionEntity{TotalServices: 10}}, nil)
+					fakeCfClient.CountOrganizationServiceInstancesReturns(10, nil)
+				})
+
+				It("counts the usage of the whole organization once and reaps its oldest service instances across the spaces", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeCfClient.CountOrganizationServiceInstancesCallCount()).To(Equal(1))
+					Expect(fakeCfClient.CountSpaceServiceInstancesCallCount()).To(Equal(0), "Unexpected call to CountSpaceServiceInstances!")
+					Expect(deletedGuids()).To(Equal([]string{"a-1", "b-1", "a-2"}))
+				})
+			})
+
+			Context("when the quota is unlimited", func() {
+				BeforeEach(func() {
+					fakeCfClient.GetSpaceQuotaDefinitionReturns(cloudfoundry.QuotaDefinition{Entity: cloudfoundry.QuotaDefinitionEntity{TotalServices: cloudfoundry.UnlimitedServices}}, nil)
+					fakeCfClient.GetOrganizationQuotaDefinitionReturns(cloudfoundry.QuotaDefinition{Entity: cloudfoundry.QuotaDefinitionEntity{TotalServices: cloudfoundry.UnlimitedServices}}, nil)
+				})
+
+				It("reaps nothing", func() {
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+				})
+			})
+
+			Context("when the quota cannot be determined", func() {
+				BeforeEach(func() {
+					fakeCfClient.GetSpaceStub = nil
+					fakeCfClient.GetSpaceReturns(cloudfoundry.Space{}, testError)
+				})
+
+				It("logs the error, fails, and reaps nothing", func() {
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+					expectErrorsMatching(reaperError, reaperOutput, "unable to determine service instance quota usage of space: full-space \\(test error\\)")
+				})
+			})
+		})
+
+		Context("when the newest service instances of each group are to be kept", func() {
+			serviceInstance := func(guid string, name string, spaceGuid string, hoursAgo int, tags ...string) cloudfoundry.ServiceInstance {
+				return cloudfoundry.ServiceInstance{
+					Metadata: cloudfoundry.Metadata{Guid: guid, CreatedAt: frozenTime().Add(-time.Duration(hoursAgo) * time.Hour).Format(time.RFC3339)},
+					Entity:   cloudfoundry.ServiceInstanceEntity{Name: name, SpaceGuid: spaceGuid, Tags: tags},
+				}
+			}
+
+			BeforeEach(func() {
+				keepNewest = 2
+				fakeCfClient.GetServicePlanInstancesReturns(serviceInstanceChannels([]cloudfoundry.ServiceInstance{
+					serviceInstance("perf-db-1-guid", "perf-db-1", "space-1", 40, "perf"),
+					serviceInstance("perf-db-3-guid", "perf-db-3", "space-1", 20, "perf"),
+					serviceInstance("perf-db-2-guid", "perf-db-2", "space-1", 30, "perf"),
+					serviceInstance("cache-1-guid", "cache-1", "space-1", 50),
+					serviceInstance("perf-db-4-guid", "perf-db-4", "space-2", 60, "perf"),
+				}, nil))
+			})
+
+			deletedGuids := func() []string {
+				guids := make([]string, fakeCfClient.DeleteServiceInstanceCallCount())
+				for i := range guids {
+					guids[i], _ = fakeCfClient.DeleteServiceInstanceArgsForCall(i)
+				}
+				return guids
+			}
+
+			It("reaps all but the newest service instances in each space", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(deletedGuids()).To(ConsistOf("perf-db-1-guid", "cache-1-guid"))
+			})
+
+			Context("when grouping by name prefix", func() {
+				BeforeEach(func() {
+					keepNewest = 1
+					keepGrouping = reaperpkg.ByNamePrefix
+				})
+
+				It("reaps all but the newest service instances with each name prefix in each space", func() {
+					Expect(deletedGuids()).To(ConsistOf("perf-db-1-guid", "perf-db-2-guid"))
+				})
+			})
+
+			Context("when grouping by tags", func() {
+				BeforeEach(func() {
+					keepNewest = 1
+					keepGrouping = reaperpkg.ByTags
+				})
+
+				It("reaps all but the newest service instances with each set of tags in each space", func() {
+					Expect(deletedGuids()).To(ConsistOf("perf-db-1-guid", "perf-db-2-guid"))
+				})
+			})
+
+			Context("when grouping by a label", func() {
+				BeforeEach(func() {
+					keepNewest = 1
+					keepGrouping = reaperpkg.ByLabel("team")
+					fakeCfClient.GetServiceInstanceLabelsStub = func(serviceInstanceGuid string) (map[string]string, error) {
+						switch serviceInstanceGuid {
+						case "perf-db-1-guid", "cache-1-guid":
+							return map[string]string{"team": "a"}, nil
+						case "perf-db-2-guid":
+							return map[string]string{"team": "b"}, nil
+						}
+						return map[string]string{}, nil
+					}
+				})
+
+				It("reaps all but the newest service instances with each value of the label in each space", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(deletedGuids()).To(ConsistOf("cache-1-guid"))
+				})
+
+				Context("when the labels cannot be listed", func() {
+					BeforeEach(func() {
+						fakeCfClient.GetServiceInstanceLabelsStub = nil
+						fakeCfClient.GetServiceInstanceLabelsReturns(nil, testError)
+					})
+
+					It("logs the error and reaps nothing", func() {
+						Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+						expectErrorsMatching(reaperError, reaperOutput, "unable to list labels of service instance")
+					})
+				})
+			})
+
+			Context("when a service instance which would be reaped has not expired", func() {
+				BeforeEach(func() {
+					keepNewest = 1
+					fakeCfClient.GetServicePlanInstancesReturns(serviceInstanceChannels([]cloudfoundry.ServiceInstance{
+						serviceInstance("new-guid", "new", "space-1", 1),
+						serviceInstance("newer-guid", "newer", "space-1", 0),
+					}, nil))
+				})
+
+				It("keeps it", func() {
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+				})
+			})
+		})
+
+		Context("when expired service keys are to be reaped", func() {
+			BeforeEach(func() {
+				serviceKeyExpiryInterval = 12 * time.Hour
+				fakeCfClient.GetServiceKeysReturns([]cloudfoundry.ServiceKey{
+					{
+						Metadata: cloudfoundry.Metadata{Guid: "old-key-guid", CreatedAt: fifteenHoursAgo().Format(time.RFC3339)},
+						Entity:   cloudfoundry.ServiceKeyEntity{Name: "old-key"},
+					},
+					{
+						Metadata: cloudfoundry.Metadata{Guid: "new-key-guid", CreatedAt: tenHoursAgo().Format(time.RFC3339)},
+						Entity:   cloudfoundry.ServiceKeyEntity{Name: "new-key"},
+					},
+					{
+						Metadata: cloudfoundry.Metadata{Guid: "ci-key-guid", CreatedAt: fifteenHoursAgo().Format(time.RFC3339)},
+						Entity:   cloudfoundry.ServiceKeyEntity{Name: "ci-key"},
+					},
+				}, nil)
+			})
+
+			It("deletes the expired service keys of every service instance in scope", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(fakeCfClient.GetServiceKeysCallCount()).To(Equal(3))
+				Expect(fakeCfClient.DeleteServiceKeyCallCount()).To(Equal(6))
+				for i := 0; i < fakeCfClient.DeleteServiceKeyCallCount(); i++ {
+					Expect(fakeCfClient.DeleteServiceKeyArgsForCall(i)).NotTo(Equal("new-key-guid"))
+				}
+				Expect(reaperOutput).To(gbytes.Say("old-key old-key-guid \\(service key of %s\\)\n", testExpiredFreePlanServiceInstanceName1))
+			})
+
+			It("still reaps only the expired service instances", func() {
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+			})
+
+			Context("when a service key name pattern is given", func() {
+				BeforeEach(func() {
+					serviceKeyNamePattern = regexp.MustCompile("^ci-")
+				})
+
+				It("deletes only the expired service keys with matching names", func() {
+					Expect(fakeCfClient.DeleteServiceKeyCallCount()).To(Equal(3))
+					for i := 0; i < fakeCfClient.DeleteServiceKeyCallCount(); i++ {
+						Expect(fakeCfClient.DeleteServiceKeyArgsForCall(i)).To(Equal("ci-key-guid"))
+					}
+				})
+			})
+
+			Context("when the 'reap' flag is false", func() {
+				BeforeEach(func() { reap = false })
+
+				It("logs the expired service keys without deleting them", func() {
+					Expect(reaperOutput).To(gbytes.Say("old-key old-key-guid"))
+					Expect(fakeCfClient.DeleteServiceKeyCallCount()).To(Equal(0), "Unexpected call to DeleteServiceKey!")
+				})
+			})
+
+			Context("when more service keys would be deleted than the safety cap", func() {
+				BeforeEach(func() {
+					maxDeletions = 5
+				})
+
+				It("deletes none of them and fails, but still reaps the service instances", func() {
+					Expect(fakeCfClient.DeleteServiceKeyCallCount()).To(Equal(0), "Unexpected call to DeleteServiceKey!")
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+					expectErrorsMatching(reaperError, reaperOutput, "refusing to delete 6 service keys, more than the maximum of 5")
+					Expect(summary.SafetyCapExceeded).To(BeTrue())
+				})
+			})
+
+			Context("when deletion is to be confirmed", func() {
+				var fakeConfirmer *confirmfakes.FakeConfirmer
+
+				BeforeEach(func() {
+					fakeConfirmer = &confirmfakes.FakeConfirmer{}
+					fakeConfirmer.ConfirmStub = func(candidate confirm.Candidate) (confirm.Answer, error) {
+						if candidate.Type == confirm.ServiceKeyType {
+							return confirm.No, nil
+						}
+						return confirm.Yes, nil
+					}
+					confirmer = fakeConfirmer
+				})
+
+				It("asks about each service key and deletes only those confirmed", func() {
+					candidate := fakeConfirmer.ConfirmArgsForCall(0)
+					Expect(candidate.Type).To(Equal(confirm.ServiceKeyType))
+					Expect(candidate.Name).To(Equal("old-key"))
+					Expect(candidate.ServiceInstanceName).To(Equal(testExpiredFreePlanServiceInstanceName1))
+					Expect(fakeCfClient.DeleteServiceKeyCallCount()).To(Equal(0), "Unexpected call to DeleteServiceKey!")
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+				})
+			})
+
+			Context("when an audit trail is given", func() {
+				var fakeTrail *auditfakes.FakeTrail
+
+				BeforeEach(func() {
+					fakeTrail = &auditfakes.FakeTrail{}
+					auditTrail = fakeTrail
+				})
+
+				It("records the deletion of each service key", func() {
+					entry := fakeTrail.RecordArgsForCall(0)
+					Expect(entry.Action).To(Equal(audit.Deleted))
+					Expect(entry.ServiceKeyGuid).To(Equal("old-key-guid"))
+					Expect(entry.ServiceKeyName).To(Equal("old-key"))
+					Expect(entry.ServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
+				})
+			})
+
+			Context("when checkpointing", func() {
+				var fakeCheckpoint *checkpointfakes.FakeCheckpoint
+
+				BeforeEach(func() {
+					fakeCheckpoint = &checkpointfakes.FakeCheckpoint{}
+					checkpointer = fakeCheckpoint
+				})
+
+				It("records the progress of deleting each service key", func() {
+					Expect(fakeCheckpoint.AttemptedArgsForCall(0)).To(Equal("old-key-guid"))
+					Expect(fakeCheckpoint.CompletedArgsForCall(0)).To(Equal("old-key-guid"))
+				})
+			})
+
+			Context("when a service key cannot be deleted", func() {
+				BeforeEach(func() {
+					fakeCfClient.DeleteServiceKeyReturns(testError)
+				})
+
+				It("logs the error and fails", func() {
+					expectErrorsMatching(reaperError, reaperOutput, "unable to delete service key: old-key old-key-guid \\(test error\\)")
+				})
+			})
+
+			Context("when the service keys cannot be fetched", func() {
+				BeforeEach(func() {
+					fakeCfClient.GetServiceKeysReturns(nil, testError)
+				})
+
+				It("logs the error, fails, and still reaps the service instances", func() {
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+					expectErrorsMatching(reaperError, reaperOutput, "unable to list service keys of service instance: ")
+				})
+			})
+		})
+
+		Context("when the 'reap' flag is true", func() {
+			BeforeEach(func() { reap = true })
+
+			Context("when the 'recursive' flag is false", func() {
+				BeforeEach(func() { recursive = false })
+
+				It("deletes only the expired service instances", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+
+					deletedServiceInstanceGuid, deletedRecursively := fakeCfClient.DeleteServiceInstanceArgsForCall(0)
+					Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
+					Expect(deletedRecursively).To(BeFalse())
+
+					deletedServiceInstanceGuid, deletedRecursively = fakeCfClient.DeleteServiceInstanceArgsForCall(1)
+					Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
+					Expect(deletedRecursively).To(BeFalse())
+				})
+			})
+
+			Context("when the 'recursive' flag is true", func() {
+				BeforeEach(func() {
+					recursive = true
+					namePattern = regexp.MustCompile("-name-1$")
+					fakeCfClient.GetServiceBindingsReturns([]cloudfoundry.ServiceBinding{
+						{Metadata: cloudfoundry.Metadata{Guid: "binding-guid"}, Entity: cloudfoundry.ServiceBindingEntity{AppGuid: "app-guid"}},
+					}, nil)
+					fakeCfClient.GetServiceKeysReturns([]cloudfoundry.ServiceKey{
+						{Metadata: cloudfoundry.Metadata{Guid: "key-guid"}, Entity: cloudfoundry.ServiceKeyEntity{Name: "key-name"}},
+					}, nil)
+					fakeCfClient.GetServiceInstanceRoutesReturns([]cloudfoundry.Route{
+						{Metadata: cloudfoundry.Metadata{Guid: "route-guid"}, Entity: cloudfoundry.RouteEntity{Host: "route-host"}},
+					}, nil)
+				})
+
+				It("deletes the dependents of each expired service instance, one at a time, and then the service instance", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+
+					Expect(fakeCfClient.DeleteServiceBindingArgsForCall(0)).To(Equal("binding-guid"))
+					Expect(fakeCfClient.DeleteServiceKeyArgsForCall(0)).To(Equal("key-guid"))
+					unboundServiceInstanceGuid, unboundRouteGuid := fakeCfClient.UnbindRouteServiceArgsForCall(0)
+					Expect(unboundServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
+					Expect(unboundRouteGuid).To(Equal("route-guid"))
+
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+					deletedServiceInstanceGuid, deletedRecursively := fakeCfClient.DeleteServiceInstanceArgsForCall(0)
+					Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
+					Expect(deletedRecursively).To(BeFalse())
+				})
+
+				It("reports each deletion", func() {
+					Expect(reaperOutput).To(gbytes.Say("app-guid binding-guid \\(service binding of %s\\)\n", testExpiredFreePlanServiceInstanceName1))
+					Expect(reaperOutput).To(gbytes.Say("key-name key-guid \\(service key of %s\\)\n", testExpiredFreePlanServiceInstanceName1))
+					Expect(reaperOutput).To(gbytes.Say("route-host route-guid \\(route binding of %s\\)\n", testExpiredFreePlanServiceInstanceName1))
+					Expect(reaperOutput).To(gbytes.Say("%s %s\n", testExpiredFreePlanServiceInstanceName1, testExpiredFreePlanServiceInstanceGuid1))
+				})
+
+				Context("when a dependent cannot be deleted", func() {
+					BeforeEach(func() {
+						cascadeAttempts = 3
+						fakeCfClient.DeleteServiceKeyReturns(testError)
+					})
+
+					It("retries it, carries on with the other dependents, and does not delete the service instance", func() {
+						Expect(fakeCfClient.DeleteServiceKeyCallCount()).To(Equal(3), "Unexpected number of DeleteServiceKey invocations")
+						Expect(fakeCfClient.UnbindRouteServiceCallCount()).To(Equal(1), "Unexpected number of UnbindRouteService invocations")
+						Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+						expectErrorsMatching(reaperError, reaperOutput, fmt.Sprintf(
+							"unable to delete service instance: %s %s \\(cascade incomplete: unable to delete service key key-name: %s\\)",
+							testExpiredFreePlanServiceInstanceName1, testExpiredFreePlanServiceInstanceGuid1, testError))
+					})
+				})
+
+				Context("when a dependent can be deleted on a later attempt", func() {
+					BeforeEach(func() {
+						cascadeAttempts = 3
+						fakeCfClient.DeleteServiceBindingReturnsOnCall(0, false, testError)
+					})
+
+					It("deletes the service instance", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(fakeCfClient.DeleteServiceBindingCallCount()).To(Equal(2), "Unexpected number of DeleteServiceBinding invocations")
+						Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+					})
+				})
+
+				Context("when a service binding is deleted asynchronously", func() {
+					BeforeEach(func() {
+						cascadeAttempts = 3
+						fakeCfClient.DeleteServiceBindingReturns(true, nil)
+						fakeCfClient.GetServiceBindingReturns(cloudfoundry.ServiceBinding{}, true, nil)
+						fakeCfClient.GetServiceBindingReturnsOnCall(1, cloudfoundry.ServiceBinding{}, false, nil)
+					})
+
+					It("waits until it has gone before deleting the service instance", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(fakeCfClient.DeleteServiceBindingCallCount()).To(Equal(1), "Unexpected number of DeleteServiceBinding invocations")
+						Expect(fakeCfClient.GetServiceBindingCallCount()).To(Equal(2), "Unexpected number of GetServiceBinding invocations")
+						Expect(fakeCfClient.GetServiceBindingArgsForCall(0)).To(Equal("binding-guid"))
+						Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+					})
+
+					Context("when it does not go", func() {
+						BeforeEach(func() {
+							fakeCfClient.GetServiceBindingReturnsOnCall(1, cloudfoundry.ServiceBinding{}, true, nil)
+						})
+
+						It("does not delete the service instance", func() {
+							Expect(fakeCfClient.GetServiceBindingCallCount()).To(Equal(3), "Unexpected number of GetServiceBinding invocations")
+							Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+							expectErrorsMatching(reaperError, reaperOutput, "unable to unbind app app-guid: asynchronous unbinding still in progress after 3 checks")
+						})
+					})
+
+					Context("when the unbinding fails", func() {
+						BeforeEach(func() {
+							fakeCfClient.GetServiceBindingReturnsOnCall(0, cloudfoundry.ServiceBinding{Entity: cloudfoundry.ServiceBindingEntity{
+								LastOperation: cloudfoundry.LastOperation{State: cloudfoundry.LastOperationFailed, Description: "broker error"},
+							}}, true, nil)
+						})
+
+						It("stops waiting and does not delete the service instance", func() {
+							Expect(fakeCfClient.GetServiceBindingCallCount()).To(Equal(1), "Unexpected number of GetServiceBinding invocations")
+							Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+							expectErrorsMatching(reaperError, reaperOutput, "unable to unbind app app-guid: asynchronous unbinding failed: broker error")
+						})
+					})
+				})
+
+				Context("when the broker accepts the deletion of the service instance asynchronously", func() {
+					BeforeEach(func() {
+						cascadeAttempts = 3
+						fakeCfClient.DeleteServiceInstanceReturns(true, nil)
+					})
+
+					It("does not delete it again and reports that its deletion is in progress", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+						Expect(summary.Reaped).To(Equal(1))
+						Expect(reaperOutput).To(gbytes.Say("%s %s deletion in progress\n", testExpiredFreePlanServiceInstanceName1, testExpiredFreePlanServiceInstanceGuid1))
+					})
+				})
+
+				Context("when the dependents cannot be listed", func() {
+					BeforeEach(func() {
+						fakeCfClient.GetServiceInstanceRoutesReturns(nil, testError)
+					})
+
+					It("logs the error and does not delete the service instance", func() {
+						Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+						expectErrorsMatching(reaperError, reaperOutput, "unable to list route bindings: test error")
+					})
+				})
+			})
+
+			Context("when service instance deletion fails", func() {
+				BeforeEach(func() {
+					fakeCfClient.DeleteServiceInstanceReturns(false, testError)
+				})
+
+				It("logs the error and fails", func() {
+					const errorMessage = "unable to delete service instance: %s %s \\(%s\\)\n"
+					expectErrorsMatching(reaperError, reaperOutput,
+						fmt.Sprintf(errorMessage, testExpiredFreePlanServiceInstanceName1, testExpiredFreePlanServiceInstanceGuid1, testError),
+						fmt.Sprintf(errorMessage, testExpiredFreePlanServiceInstanceName2, testExpiredFreePlanServiceInstanceGuid2, testError),
+					)
+				})
+			})
+		})
+
+		Context("when an archive is provided", func() {
+			var fakeArchive *snapshotfakes.FakeArchive
+
+			BeforeEach(func() {
+				fakeArchive = &snapshotfakes.FakeArchive{}
+				archive = fakeArchive
+				fakeCfClient.GetServiceInstanceParametersReturns(map[string]interface{}{"key": "value"}, nil)
+			})
+
+			It("snapshots each expired service instance before deleting it", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+
+				Expect(fakeArchive.SaveCallCount()).To(Equal(2), "Unexpected number of snapshots saved")
+				savedSnapshot := fakeArchive.SaveArgsForCall(0)
+				Expect(savedSnapshot.ServiceInstance.Metadata.Guid).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
+				Expect(savedSnapshot.Parameters).To(Equal(map[string]interface{}{"key": "value"}))
+				Expect(savedSnapshot.TakenAt).To(Equal(frozenTime().Format(time.RFC3339)))
+
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+			})
+
+			Context("when the snapshot cannot be saved", func() {
+				BeforeEach(func() {
+					fakeArchive.SaveReturns("", testError)
+				})
+
+				It("does not delete the service instances and fails", func() {
+					const errorMessage = "unable to snapshot service instance: %s %s \\(%s\\)\n"
+					expectErrorsMatching(reaperError, reaperOutput,
+						fmt.Sprintf(errorMessage, testExpiredFreePlanServiceInstanceName1, testExpiredFreePlanServiceInstanceGuid1, testError),
+						fmt.Sprintf(errorMessage, testExpiredFreePlanServiceInstanceName2, testExpiredFreePlanServiceInstanceGuid2, testError),
+					)
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+				})
+			})
+
+			Context("when the 'reap' flag is false", func() {
+				BeforeEach(func() { reap = false })
+
+				It("does not take snapshots", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeArchive.SaveCallCount()).To(Equal(0), "Unexpected call to Save!")
+				})
+			})
+		})
+
+		Context("when orphaned service instances are to be purged", func() {
+			BeforeEach(func() {
+				purgeOrphans = true
+				fakeCfClient.DeleteServiceInstanceReturnsOnCall(0, false, testError)
+				fakeCfClient.DeleteServiceInstanceReturnsOnCall(1, false, &cloudfoundry.BrokerUnreachableError{})
+			})
+
+			It("purges only the service instances whose broker is unreachable", func() {
+				Expect(fakeCfClient.PurgeServiceInstanceCallCount()).To(Equal(1), "Unexpected number of PurgeServiceInstance invocations")
+				Expect(fakeCfClient.PurgeServiceInstanceArgsForCall(0)).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
+				Expect(reaperOutput).To(gbytes.Say("%s %s purged\n", testExpiredFreePlanServiceInstanceName2, testExpiredFreePlanServiceInstanceGuid2))
+				expectErrors(reaperError, reaperOutput, testError)
+			})
+		})
+
+		Context("when an audit trail is provided", func() {
+			var fakeAuditTrail *auditfakes.FakeTrail
+
+			BeforeEach(func() {
+				fakeAuditTrail = &auditfakes.FakeTrail{}
+				auditTrail = fakeAuditTrail
+				purgeOrphans = true
+				fakeCfClient.DeleteServiceInstanceReturnsOnCall(1, false, &cloudfoundry.BrokerUnreachableError{})
+			})
+
+			It("records each deletion and purge", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(fakeAuditTrail.RecordCallCount()).To(Equal(2), "Unexpected number of audit entries")
+
+				entry := fakeAuditTrail.RecordArgsForCall(0)
+				Expect(entry.Action).To(Equal(audit.Deleted))
+				Expect(entry.ServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
+				Expect(entry.ServiceInstanceName).To(Equal(testExpiredFreePlanServiceInstanceName1))
+				Expect(entry.Time).To(Equal(frozenTime().Format(time.RFC3339)))
+
+				entry = fakeAuditTrail.RecordArgsForCall(1)
+				Expect(entry.Action).To(Equal(audit.Purged))
+				Expect(entry.ServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
+			})
+
+			Context("when recording fails", func() {
+				BeforeEach(func() {
+					fakeAuditTrail.RecordReturns(testError)
+				})
+
+				It("logs the error and fails", func() {
+					expectErrorsMatching(reaperError, reaperOutput, "service instance was deleted and could not be audited: test error")
+				})
+			})
+
+			Context("when a deletion fails and cannot be recorded", func() {
+				BeforeEach(func() {
+					purgeOrphans = false
+					fakeCfClient.DeleteServiceInstanceReturnsOnCall(1, false, testError)
+					fakeAuditTrail.RecordReturnsOnCall(1, testError)
+				})
+
+				It("reports that the failure could not be audited", func() {
+					Expect(fakeAuditTrail.RecordArgsForCall(1).Action).To(Equal(audit.Failed))
+					errs := reaperError.(reaperpkg.Errors)
+					Expect(errs.OfKind(reaperpkg.AuditError)).To(HaveLen(1))
+					Expect(reaperOutput).To(gbytes.Say("service instance could not be deleted and could not be audited: test error"))
+				})
+			})
+		})
+
+		Context("when empty spaces are to be deleted", func() {
+			BeforeEach(func() {
+				emptySpaceNamePattern = regexp.MustCompile("^ci-")
+				emptySpaceExpiryInterval = 12 * time.Hour
+				fakeCfClient.GetSpacesReturns(spaceChannels([]cloudfoundry.Space{
+					{
+						Metadata: cloudfoundry.Metadata{Guid: "empty-space-guid", CreatedAt: fifteenHoursAgo().Format(time.RFC3339)},
+						Entity:   cloudfoundry.SpaceEntity{Name: "ci-empty"},
+					},
+					{
+						Metadata: cloudfoundry.Metadata{Guid: "used-space-guid", CreatedAt: fifteenHoursAgo().Format(time.RFC3339)},
+						Entity:   cloudfoundry.SpaceEntity{Name: "ci-used"},
+					},
+					{
+						Metadata: cloudfoundry.Metadata{Guid: "recent-space-guid", CreatedAt: tenHoursAgo().Format(time.RFC3339)},
+						Entity:   cloudfoundry.SpaceEntity{Name: "ci-recent"},
+					},
+					{
+						Metadata: cloudfoundry.Metadata{Guid: "other-space-guid", CreatedAt: fifteenHoursAgo().Format(time.RFC3339)},
+						Entity:   cloudfoundry.SpaceEntity{Name: "production"},
+					},
+				}, nil))
+				fakeCfClient.GetSpaceUsageStub = func(spaceGuid string) (cloudfoundry.SpaceUsage, error) {
+					if spaceGuid == "used-space-guid" {
+						return cloudfoundry.SpaceUsage{Routes: 1}, nil
+					}
+					return cloudfoundry.SpaceUsage{}, nil
+				}
+			})
+
+			It("deletes the old, empty spaces with matching names once reaping is complete", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+				Expect(fakeCfClient.GetSpaceUsageCallCount()).To(Equal(2))
+				Expect(fakeCfClient.DeleteSpaceCallCount()).To(Equal(1), "Unexpected number of DeleteSpace invocations")
+				Expect(fakeCfClient.DeleteSpaceArgsForCall(0)).To(Equal("empty-space-guid"))
+				Expect(reaperOutput).To(gbytes.Say("%s %s\n", testExpiredFreePlanServiceInstanceName2, testExpiredFreePlanServiceInstanceGuid2))
+				Expect(reaperOutput).To(gbytes.Say("ci-empty empty-space-guid \\(empty space\\)\n"))
+			})
+
+			Context("when the 'reap' flag is false", func() {
+				BeforeEach(func() { reap = false })
+
+				It("logs the empty spaces without deleting them", func() {
+					Expect(reaperOutput).To(gbytes.Say("ci-empty empty-space-guid"))
+					Expect(fakeCfClient.DeleteSpaceCallCount()).To(Equal(0), "Unexpected call to DeleteSpace!")
+				})
+
+				Context("when a space holds only service instances which would have been deleted", func() {
+					BeforeEach(func() {
+						serviceInstances := successfulGetServicePlanInstancesResponse()
+						for i := range serviceInstances.serviceInstances {
+							serviceInstances.serviceInstances[i].Entity.SpaceGuid = "used-space-guid"
+						}
+						fakeCfClient.GetServicePlanInstancesReturns(serviceInstanceChannels(serviceInstances.serviceInstances, nil))
+						fakeCfClient.GetSpaceUsageStub = func(spaceGuid string) (cloudfoundry.SpaceUsage, error) {
+							if spaceGuid == "used-space-guid" {
+								return cloudfoundry.SpaceUsage{ServiceInstances: 2}, nil
+							}
+							return cloudfoundry.SpaceUsage{}, nil
+						}
+					})
+
+					It("reports the space as one which would have been deleted", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(reaperOutput).To(gbytes.Say("ci-empty empty-space-guid \\(empty space\\)\n"))
+						Expect(reaperOutput).To(gbytes.Say("ci-used used-space-guid \\(empty space\\)\n"))
+					})
+				})
+			})
+
+			Context("when a space holds service instances whose deletion is in progress", func() {
+				var usageCalls int
+
+				BeforeEach(func() {
+					serviceInstances := successfulGetServicePlanInstancesResponse()
+					for i := range serviceInstances.serviceInstances {
+						serviceInstances.serviceInstances[i].Entity.SpaceGuid = "used-space-guid"
+					}
+					fakeCfClient.GetServicePlanInstancesReturns(serviceInstanceChannels(serviceInstances.serviceInstances, nil))
+					fakeCfClient.DeleteServiceInstanceReturns(true, nil)
+					usageCalls = 0
+					fakeCfClient.GetSpaceUsageStub = func(spaceGuid string) (cloudfoundry.SpaceUsage, error) {
+						if spaceGuid == "used-space-guid" {
+							usageCalls++
+							if usageCalls == 1 {
+								return cloudfoundry.SpaceUsage{ServiceInstances: 2}, nil
+							}
+						}
+						return cloudfoundry.SpaceUsage{}, nil
+					}
+					cascadeAttempts = 2
+				})
+
+				It("waits for the deletions to finish and then deletes the space", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeCfClient.GetServiceInstanceCallCount()).To(Equal(2))
+					Expect(fakeCfClient.DeleteSpaceCallCount()).To(Equal(2), "Unexpected number of DeleteSpace invocations")
+					Expect(fakeCfClient.DeleteSpaceArgsForCall(1)).To(Equal("used-space-guid"))
+				})
+
+				Context("when a deletion does not finish", func() {
+					BeforeEach(func() {
+						fakeCfClient.GetServiceInstanceReturns(cloudfoundry.ServiceInstance{}, true, nil)
+					})
+
+					It("leaves the space for a later run", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(fakeCfClient.GetServiceInstanceCallCount()).To(Equal(2))
+						Expect(fakeCfClient.DeleteSpaceCallCount()).To(Equal(1), "Unexpected number of DeleteSpace invocations")
+						Expect(fakeCfClient.DeleteSpaceArgsForCall(0)).To(Equal("empty-space-guid"))
+						Expect(reaperOutput).To(gbytes.Say("ci-used used-space-guid not empty: deletion of service instance .* still in progress"))
+					})
+				})
+
+				Context("when a deletion cannot be checked", func() {
+					BeforeEach(func() {
+						fakeCfClient.GetServiceInstanceReturns(cloudfoundry.ServiceInstance{}, false, testError)
+					})
+
+					It("logs the error and fails", func() {
+						Expect(fakeCfClient.DeleteSpaceCallCount()).To(Equal(1), "Unexpected number of DeleteSpace invocations")
+						expectErrorsMatching(reaperError, reaperOutput, "unable to determine whether space is empty: ci-used used-space-guid \\(test error\\)")
+					})
+				})
+			})
+
+			Context("when more spaces would be deleted than the safety cap", func() {
+				BeforeEach(func() {
+					maxDeletions = 2
+					emptySpaceExpiryInterval = 5 * time.Hour
+					fakeCfClient.GetSpaceUsageStub = nil
+					fakeCfClient.GetSpaceUsageReturns(cloudfoundry.SpaceUsage{}, nil)
+				})
+
+				It("deletes none of them and fails", func() {
+					Expect(fakeCfClient.DeleteSpaceCallCount()).To(Equal(0), "Unexpected call to DeleteSpace!")
+					expectErrorsMatching(reaperError, reaperOutput, "refusing to delete 3 spaces, more than the maximum of 2")
+					Expect(summary.SafetyCapExceeded).To(BeTrue())
+				})
+			})
+
+			Context("when deletions must be confirmed", func() {
+				var fakeConfirmer *confirmfakes.FakeConfirmer
+
+				BeforeEach(func() {
+					fakeConfirmer = &confirmfakes.FakeConfirmer{}
+					fakeConfirmer.ConfirmStub = func(candidate confirm.Candidate) (confirm.Answer, error) {
+						if candidate.Type == confirm.SpaceType {
+							return confirm.No, nil
+						}
+						return confirm.Yes, nil
+					}
+					confirmer = fakeConfirmer
+				})
+
+				It("asks about each space and deletes only those confirmed", func() {
+					Expect(fakeConfirmer.ConfirmCallCount()).To(Equal(3))
+					candidate := fakeConfirmer.ConfirmArgsForCall(2)
+					Expect(candidate.Type).To(Equal(confirm.SpaceType))
+					Expect(candidate.Name).To(Equal("ci-empty"))
+					Expect(candidate.Guid).To(Equal("empty-space-guid"))
+					Expect(fakeCfClient.DeleteSpaceCallCount()).To(Equal(0), "Unexpected call to DeleteSpace!")
+				})
+			})
+
+			Context("when checkpointing", func() {
+				var fakeCheckpoint *checkpointfakes.FakeCheckpoint
+
+				BeforeEach(func() {
+					fakeCheckpoint = &checkpointfakes.FakeCheckpoint{}
+					checkpointer = fakeCheckpoint
+					fakeCfClient.DeleteSpaceReturns(testError)
+				})
+
+				It("records the progress of deleting each space", func() {
+					Expect(fakeCheckpoint.AttemptedArgsForCall(fakeCheckpoint.AttemptedCallCount() - 1)).To(Equal("empty-space-guid"))
+					Expect(fakeCheckpoint.FailedCallCount()).To(Equal(1))
+					Expect(fakeCheckpoint.FailedArgsForCall(0)).To(Equal("empty-space-guid"))
+				})
+			})
+
+			Context("when recording history", func() {
+				var fakeRecorder *historyfakes.FakeRecorder
+
+				BeforeEach(func() {
+					fakeRecorder = &historyfakes.FakeRecorder{}
+					historyRecorder = fakeRecorder
+				})
+
+				It("records the outcome of deleting each space", func() {
+					Expect(fakeRecorder.RecordArgsForCall(fakeRecorder.RecordCallCount() - 1)).To(Equal(history.Deletion{
+						SpaceGuid: "empty-space-guid",
+						SpaceName: "ci-empty",
+					}))
+				})
+			})
+
+			Context("when a space cannot be deleted", func() {
+				BeforeEach(func() {
+					fakeCfClient.DeleteSpaceReturns(testError)
+				})
+
+				It("logs the error and fails", func() {
+					expectErrorsMatching(reaperError, reaperOutput, "unable to delete space: ci-empty empty-space-guid \\(test error\\)")
+				})
+			})
+
+			Context("when the usage of a space cannot be determined", func() {
+				BeforeEach(func() {
+					fakeCfClient.GetSpaceUsageStub = nil
+					fakeCfClient.GetSpaceUsageReturns(cloudfoundry.SpaceUsage{}, testError)
+				})
+
+				It("logs the error, fails, and deletes nothing", func() {
+					Expect(fakeCfClient.DeleteSpaceCallCount()).To(Equal(0), "Unexpected call to DeleteSpace!")
+					expectErrorsMatching(reaperError, reaperOutput, "unable to determine whether space is empty: ci-empty empty-space-guid \\(test error\\)")
+				})
+			})
+
+			Context("when listing spaces fails", func() {
+				BeforeEach(func() {
+					fakeCfClient.GetSpacesReturns(spaceChannels(nil, testError))
+				})
+
+				It("logs the error and fails", func() {
+					expectErrors(reaperError, reaperOutput, testError)
+				})
+			})
+		})
+
+		Context("when apps are to be reaped", func() {
+			var route cloudfoundry.Route
+
+			BeforeEach(func() {
+				apps = true
+				fakeCfClient.GetAppsReturns(appChannels([]cloudfoundry.App{
+					{
+						Metadata: cloudfoundry.Metadata{Guid: "expired-stopped-app-guid", CreatedAt: fifteenHoursAgo().Format(time.RFC3339)},
+						Entity:   cloudfoundry.AppEntity{Name: "expired-stopped-app", SpaceGuid: testSpaceGuid, State: cloudfoundry.AppStopped},
+					},
+					{
+						Metadata: cloudfoundry.Metadata{Guid: "expired-started-app-guid", CreatedAt: fifteenHoursAgo().Format(time.RFC3339)},
+						Entity:   cloudfoundry.AppEntity{Name: "expired-started-app", SpaceGuid: testSpaceGuid, State: cloudfoundry.AppStarted},
+					},
+					{
+						Metadata: cloudfoundry.Metadata{Guid: "recent-app-guid", CreatedAt: tenHoursAgo().Format(time.RFC3339)},
+						Entity:   cloudfoundry.AppEntity{Name: "recent-app", SpaceGuid: testSpaceGuid, State: cloudfoundry.AppStopped},
+					},
+				}, nil))
+				route = cloudfoundry.Route{
+					Metadata: cloudfoundry.Metadata{Guid: "route-guid"},
+					Entity:   cloudfoundry.RouteEntity{Host: "route-host"},
+				}
+				fakeCfClient.GetAppRoutesReturns([]cloudfoundry.Route{route}, nil)
+				fakeCfClient.GetRouteAppsReturns([]cloudfoundry.App{}, nil)
+			})
+
+			It("deletes the expired apps but no service instances", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(fakeCfClient.DeleteAppCallCount()).To(Equal(2), "Unexpected number of DeleteApp invocations")
+				Expect(fakeCfClient.DeleteAppArgsForCall(0)).To(Equal("expired-stopped-app-guid"))
+				Expect(fakeCfClient.DeleteAppArgsForCall(1)).To(Equal("expired-started-app-guid"))
+				Expect(reaperOutput).To(gbytes.Say("expired-stopped-app expired-stopped-app-guid\n"))
+				Expect(reaperOutput).To(gbytes.Say("expired-started-app expired-started-app-guid\n"))
+				Expect(fakeCfClient.GetServicesCallCount()).To(Equal(0), "Unexpected call to GetServices!")
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+			})
+
+			It("leaves the routes alone", func() {
+				Expect(fakeCfClient.GetAppRoutesCallCount()).To(Equal(0), "Unexpected call to GetAppRoutes!")
+				Expect(fakeCfClient.DeleteRouteCallCount()).To(Equal(0), "Unexpected call to DeleteRoute!")
+			})
+
+			Context("when more apps would be deleted than the safety cap", func() {
+				BeforeEach(func() {
+					maxDeletions = 1
+				})
+
+				It("deletes none of them and fails", func() {
+					Expect(fakeCfClient.DeleteAppCallCount()).To(Equal(0), "Unexpected call to DeleteApp!")
+					expectErrorsMatching(reaperError, reaperOutput, "refusing to delete 2 apps, more than the maximum of 1")
+					Expect(summary.SafetyCapExceeded).To(BeTrue())
+				})
+			})
+
+			Context("when no more apps would be deleted than the safety cap", func() {
+				BeforeEach(func() {
+					maxDeletions = 2
+				})
+
+				It("deletes them", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeCfClient.DeleteAppCallCount()).To(Equal(2), "Unexpected number of DeleteApp invocations")
+				})
+			})
+
+			Context("when deletions must be confirmed", func() {
+				var fakeConfirmer *confirmfakes.FakeConfirmer
+
+				BeforeEach(func() {
+					fakeConfirmer = &confirmfakes.FakeConfirmer{}
+					fakeConfirmer.ConfirmReturnsOnCall(0, confirm.No, nil)
+					fakeConfirmer.ConfirmReturnsOnCall(1, confirm.Yes, nil)
+					confirmer = fakeConfirmer
+					fakeCfClient.GetSpaceReturns(cloudfoundry.Space{Entity: cloudfoundry.SpaceEntity{Name: "test-space-name"}}, nil)
+				})
+
+				It("asks about each app and deletes only those confirmed", func() {
+					Expect(fakeConfirmer.ConfirmCallCount()).To(Equal(2))
+					candidate := fakeConfirmer.ConfirmArgsForCall(0)
+					Expect(candidate.Type).To(Equal(confirm.AppType))
+					Expect(candidate.Name).To(Equal("expired-stopped-app"))
+					Expect(candidate.SpaceName).To(Equal("test-space-name"))
+					Expect(fakeCfClient.DeleteAppCallCount()).To(Equal(1), "Unexpected number of DeleteApp invocations")
+					Expect(fakeCfClient.DeleteAppArgsForCall(0)).To(Equal("expired-started-app-guid"))
+				})
+
+				Context("when the apps are confirmed as a batch", func() {
+					BeforeEach(func() {
+						confirmBatch = true
+						fakeConfirmer.ConfirmBatchReturns(confirm.Quit, nil)
+					})
+
+					It("asks once and deletes nothing if declined", func() {
+						Expect(fakeConfirmer.ConfirmBatchCallCount()).To(Equal(1))
+						Expect(fakeConfirmer.ConfirmBatchArgsForCall(0)).To(HaveLen(2))
+						Expect(fakeCfClient.DeleteAppCallCount()).To(Equal(0), "Unexpected call to DeleteApp!")
+					})
+				})
+			})
+
+			Context("when checkpointing", func() {
+				var fakeCheckpoint *checkpointfakes.FakeCheckpoint
+
+				BeforeEach(func() {
+					fakeCheckpoint = &checkpointfakes.FakeCheckpoint{}
+					checkpointer = fakeCheckpoint
+					fakeCfClient.DeleteAppReturnsOnCall(1, testError)
+				})
+
+				It("records the progress of deleting each app", func() {
+					Expect(fakeCheckpoint.AttemptedCallCount()).To(Equal(2))
+					Expect(fakeCheckpoint.CompletedCallCount()).To(Equal(1))
+					Expect(fakeCheckpoint.CompletedArgsForCall(0)).To(Equal("expired-stopped-app-guid"))
+					Expect(fakeCheckpoint.FailedCallCount()).To(Equal(1))
+					Expect(fakeCheckpoint.FailedArgsForCall(0)).To(Equal("expired-started-app-guid"))
+				})
+
+				Context("when resuming a run which deleted an app", func() {
+					BeforeEach(func() {
+						resume = true
+						fakeCheckpoint.StateReturns(checkpoint.State{Completed: []string{"expired-stopped-app-guid"}})
+					})
+
+					It("lists the apps again but does not delete it again", func() {
+						Expect(fakeCfClient.GetAppsCallCount()).To(Equal(1))
+						Expect(fakeCfClient.DeleteAppCallCount()).To(Equal(1), "Unexpected number of DeleteApp invocations")
+						Expect(fakeCfClient.DeleteAppArgsForCall(0)).To(Equal("expired-started-app-guid"))
+					})
+				})
+			})
+
+			Context("when recording history", func() {
+				var fakeRecorder *historyfakes.FakeRecorder
+
+				BeforeEach(func() {
+					fakeRecorder = &historyfakes.FakeRecorder{}
+					historyRecorder = fakeRecorder
+					fakeCfClient.DeleteAppReturnsOnCall(1, testError)
+				})
+
+				It("records the outcome of deleting each app", func() {
+					Expect(fakeRecorder.RecordCallCount()).To(Equal(2))
+					Expect(fakeRecorder.RecordArgsForCall(0)).To(Equal(history.Deletion{
+						AppGuid:   "expired-stopped-app-guid",
+						AppName:   "expired-stopped-app",
+						SpaceGuid: testSpaceGuid,
+					}))
+					Expect(fakeRecorder.RecordArgsForCall(1)).To(Equal(history.Deletion{
+						AppGuid:   "expired-started-app-guid",
+						AppName:   "expired-started-app",
+						SpaceGuid: testSpaceGuid,
+						Error:     testError.Error(),
+					}))
+				})
+			})
+
+			Context("when app states are given", func() {
+				BeforeEach(func() {
+					appStates = []string{cloudfoundry.AppStopped}
+				})
+
+				It("deletes only the expired apps in those states", func() {
+					Expect(fakeCfClient.DeleteAppCallCount()).To(Equal(1), "Unexpected number of DeleteApp invocations")
+					Expect(fakeCfClient.DeleteAppArgsForCall(0)).To(Equal("expired-stopped-app-guid"))
+				})
+			})
+
+			Context("when a name pattern is given", func() {
+				BeforeEach(func() {
+					namePattern = regexp.MustCompile("-started-")
+				})
+
+				It("deletes only the expired apps with matching names", func() {
+					Expect(fakeCfClient.DeleteAppCallCount()).To(Equal(1), "Unexpected number of DeleteApp invocations")
+					Expect(fakeCfClient.DeleteAppArgsForCall(0)).To(Equal("expired-started-app-guid"))
+				})
+			})
+
+			Context("when routes are to be deleted", func() {
+				BeforeEach(func() {
+					deleteRoutes = true
+					namePattern = regexp.MustCompile("-stopped-")
+				})
+
+				It("deletes the routes which are no longer mapped to any app", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeCfClient.GetAppRoutesArgsForCall(0)).To(Equal("expired-stopped-app-guid"))
+					Expect(fakeCfClient.DeleteRouteCallCount()).To(Equal(1), "Unexpected number of DeleteRoute invocations")
+					Expect(fakeCfClient.DeleteRouteArgsForCall(0)).To(Equal("route-guid"))
+					Expect(reaperOutput).To(gbytes.Say("route-host route-guid \\(route of expired-stopped-app\\)\n"))
+				})
+
+				Context("when a route is still mapped to another app", func() {
+					BeforeEach(func() {
+						fakeCfClient.GetRouteAppsReturns([]cloudfoundry.App{{Metadata: cloudfoundry.Metadata{Guid: "other-app-guid"}}}, nil)
+					})
+
+					It("keeps the route", func() {
+						Expect(fakeCfClient.DeleteRouteCallCount()).To(Equal(0), "Unexpected call to DeleteRoute!")
+					})
+				})
+
+				Context("when the 'reap' flag is false", func() {
+					BeforeEach(func() {
+						reap = false
+						fakeCfClient.GetRouteAppsReturns([]cloudfoundry.App{{Metadata: cloudfoundry.Metadata{Guid: "expired-stopped-app-guid"}}}, nil)
+					})
+
+					It("logs the routes which would be deleted without deleting anything", func() {
+						Expect(reaperOutput).To(gbytes.Say("expired-stopped-app expired-stopped-app-guid\n"))
+						Expect(reaperOutput).To(gbytes.Say("route-host route-guid"))
+						Expect(fakeCfClient.DeleteAppCallCount()).To(Equal(0), "Unexpected call to DeleteApp!")
+						Expect(fakeCfClient.DeleteRouteCallCount()).To(Equal(0), "Unexpected call to DeleteRoute!")
+					})
+				})
+
+				Context("when a route cannot be deleted", func() {
+					BeforeEach(func() {
+						fakeCfClient.DeleteRouteReturns(testError)
+					})
+
+					It("logs the error and fails", func() {
+						expectErrorsMatching(reaperError, reaperOutput, "unable to delete route: route-host route-guid \\(test error\\)")
+					})
+				})
+			})
+
+			Context("when an app cannot be deleted", func() {
+				BeforeEach(func() {
+					fakeCfClient.DeleteAppReturnsOnCall(0, testError)
+				})
+
+				It("logs the error, fails, and carries on", func() {
+					Expect(fakeCfClient.DeleteAppCallCount()).To(Equal(2), "Unexpected number of DeleteApp invocations")
+					expectErrorsMatching(reaperError, reaperOutput, "unable to delete app: expired-stopped-app expired-stopped-app-guid \\(test error\\)")
+				})
+			})
+
+			Context("when listing apps fails", func() {
+				BeforeEach(func() {
+					fakeCfClient.GetAppsReturns(appChannels(nil, testError))
+				})
+
+				It("logs the error and fails", func() {
+					expectErrors(reaperError, reaperOutput, testError)
+				})
+			})
+
+			Context("when an audit trail is provided", func() {
+				var fakeAuditTrail *auditfakes.FakeTrail
 
-	JustBeforeEach(func() {
-		reaper = reaperpkg.NewReaper(fakeCfClient, frozenTime, reaperOutput)
-		reaperError = reaper.Reap(testServiceName, testFreeServicePlanName, expireAfter10Hours, reap, recursive)
+				BeforeEach(func() {
+					fakeAuditTrail = &auditfakes.FakeTrail{}
+					auditTrail = fakeAuditTrail
+					deleteRoutes = true
+					namePattern = regexp.MustCompile("-stopped-")
+				})
+
+				It("records the deletion of each app and route", func() {
+					Expect(fakeAuditTrail.RecordCallCount()).To(Equal(2), "Unexpected number of audit entries")
+
+					entry := fakeAuditTrail.RecordArgsForCall(0)
+					Expect(entry.Action).To(Equal(audit.Deleted))
+					Expect(entry.AppGuid).To(Equal("expired-stopped-app-guid"))
+					Expect(entry.AppName).To(Equal("expired-stopped-app"))
+					Expect(entry.ServiceInstanceGuid).To(BeEmpty())
+
+					entry = fakeAuditTrail.RecordArgsForCall(1)
+					Expect(entry.Action).To(Equal(audit.Deleted))
+					Expect(entry.RouteGuid).To(Equal("route-guid"))
+					Expect(entry.RouteHost).To(Equal("route-host"))
+				})
+			})
+		})
+
+		Context("when the 'reap' flag is false", func() {
+			BeforeEach(func() { reap = false })
+
+			It("logs only the expired service instance names and guids", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(reaperOutput).To(gbytes.Say("%s %s\n", testExpiredFreePlanServiceInstanceName1, testExpiredFreePlanServiceInstanceGuid1))
+				Expect(reaperOutput).To(gbytes.Say("%s %s\n", testExpiredFreePlanServiceInstanceName2, testExpiredFreePlanServiceInstanceGuid2))
+				Expect(reaperOutput).NotTo(gbytes.Say("%s %s\n", testNotExpiredFreePlanServiceInstanceName, testNotExpiredFreePlanServiceInstanceGuid))
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+			})
+		})
 	})
 
-	Describe("fetching services", func() {
-		It("fetches a list of services with the given name", func() {
+	Describe("summarising", func() {
+		It("counts the service instances reaped", func() {
 			Expect(reaperError).NotTo(HaveOccurred())
-			Expect(fakeCfClient.GetServicesCallCount()).To(Equal(1), "Unexpected number of calls to GetServices")
-			serviceName := fakeCfClient.GetServicesArgsForCall(0)
-			Expect(serviceName).To(Equal(testServiceName))
+			Expect(summary).To(Equal(reaperpkg.Summary{Candidates: 2, Reaped: 2}))
+			Expect(reaperOutput).To(gbytes.Say("Summary: 2 candidates, 2 reaped, 0 skipped, 0 failed, 0 listing errors in 0s"))
 		})
 
-		Context("when fetching the list of services fails", func() {
+		Context("when performing a dry run", func() {
+			BeforeEach(func() { reap = false })
+
+			It("counts the candidates as skipped", func() {
+				Expect(summary).To(Equal(reaperpkg.Summary{Candidates: 2, Skipped: 2}))
+			})
+		})
+
+		Context("when a deletion fails", func() {
 			BeforeEach(func() {
-				fakeCfClient.GetServicesReturns([]cloudfoundry.Service{}, testError)
+				fakeCfClient.DeleteServiceInstanceReturnsOnCall(0, false, testError)
 			})
 
-			It("logs the error and fails", func() {
-				expectErrors(reaperError, reaperOutput, testError)
+			It("counts the failure", func() {
+				Expect(reaperError).To(HaveOccurred())
+				Expect(summary).To(Equal(reaperpkg.Summary{Candidates: 2, Reaped: 1, Failed: 1}))
 			})
 		})
 
-		Context("when the list of services is empty", func() {
+		Context("when a service instance has an invalid creation time", func() {
 			BeforeEach(func() {
-				fakeCfClient.GetServicesReturns([]cloudfoundry.Service{}, nil)
+				serviceInstances := successfulGetServicePlanInstancesResponse()
+				serviceInstances.serviceInstances[0].Metadata.CreatedAt = "yesterday"
+				fakeCfClient.GetServicePlanInstancesReturns(serviceInstanceChannels(serviceInstances.serviceInstances, nil))
 			})
 
-			It("reports that it has no work to do", func() {
-				Expect(reaperError).NotTo(HaveOccurred())
-				Expect(reaperOutput).To(gbytes.Say("No services of type '%s' found", testServiceName))
+			It("counts a listing error", func() {
+				Expect(reaperError).To(HaveOccurred())
+				Expect(summary).To(Equal(reaperpkg.Summary{Candidates: 1, Reaped: 1, ListingErrors: 1}))
 			})
 		})
-	})
 
-	Describe("fetching service plans", func() {
-		It("fetches a list of service plans for the service with the given guid", func() {
-			Expect(reaperError).NotTo(HaveOccurred())
-			Expect(fakeCfClient.GetServicePlansCallCount()).To(Equal(1), "Unexpected number of calls to GetServicePlans")
-			serviceGuid := fakeCfClient.GetServicePlansArgsForCall(0)
-			Expect(serviceGuid).To(Equal(testServiceGuid))
+		Context("when the service plans cannot be listed", func() {
+			BeforeEach(func() {
+				fakeCfClient.GetServicePlansReturns(nil, testError)
+			})
+
+			It("counts a listing error", func() {
+				Expect(reaperError).To(HaveOccurred())
+				Expect(summary).To(Equal(reaperpkg.Summary{ListingErrors: 1}))
+			})
 		})
 
-		Context("when fetching the list of service plans fails", func() {
+		Context("when a safety cap is given", func() {
 			BeforeEach(func() {
-				fakeCfClient = fakeCfClientFactory(
-					successfulGetServicesResponse(),
-					servicePlanResult{nil, testError},
-					successfulGetServicePlanInstancesResponse(),
-				)
+				maxDeletions = 2
 			})
 
-			It("logs the error and fails", func() {
-				expectErrors(reaperError, reaperOutput, testError)
+			It("deletes the service instances if there are no more than the cap", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+			})
+
+			Context("when more service instances would be deleted", func() {
+				BeforeEach(func() {
+					maxDeletions = 1
+				})
+
+				It("deletes none of them and fails", func() {
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+					expectErrorsMatching(reaperError, reaperOutput, "refusing to delete 2 service instances, more than the maximum of 1")
+					Expect(summary).To(Equal(reaperpkg.Summary{Candidates: 2, Skipped: 2, SafetyCapExceeded: true}))
+				})
+			})
+		})
+
+		Context("when reaping apps", func() {
+			BeforeEach(func() {
+				apps = true
+				fakeCfClient.GetAppsReturns(appChannels([]cloudfoundry.App{
+					{Metadata: cloudfoundry.Metadata{Guid: "app-guid-1", CreatedAt: fifteenHoursAgo().Format(time.RFC3339)}, Entity: cloudfoundry.AppEntity{Name: "app-1"}},
+					{Metadata: cloudfoundry.Metadata{Guid: "app-guid-2", CreatedAt: fifteenHoursAgo().Format(time.RFC3339)}, Entity: cloudfoundry.AppEntity{Name: "app-2"}},
+				}, nil))
+				fakeCfClient.DeleteAppReturnsOnCall(1, testError)
+			})
+
+			It("counts the apps", func() {
+				Expect(summary).To(Equal(reaperpkg.Summary{Candidates: 2, Reaped: 1, Failed: 1}))
 			})
 		})
 	})
 
-	Describe("fetching service plan instances", func() {
-		It("fetches a list of instances of the given plan", func() {
+	Describe("checkpointing", func() {
+		var fakeCheckpoint *checkpointfakes.FakeCheckpoint
+
+		BeforeEach(func() {
+			fakeCheckpoint = &checkpointfakes.FakeCheckpoint{}
+			checkpointer = fakeCheckpoint
+		})
+
+		It("records the candidates and the progress of deleting them", func() {
 			Expect(reaperError).NotTo(HaveOccurred())
-			Expect(fakeCfClient.GetServicePlanInstancesCallCount()).To(Equal(1), "Unexpected number of calls to GetServicePlanInstances")
-			servicePlanGuid := fakeCfClient.GetServicePlanInstancesArgsForCall(0)
-			Expect(servicePlanGuid).To(Equal(testFreeServicePlanGuid))
+			Expect(fakeCheckpoint.CandidateCallCount()).To(Equal(2))
+			Expect(fakeCheckpoint.CandidateArgsForCall(0).Metadata.Guid).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
+			Expect(fakeCheckpoint.CandidateArgsForCall(1).Metadata.Guid).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
+			Expect(fakeCheckpoint.ListedCallCount()).To(Equal(1))
+			Expect(fakeCheckpoint.AttemptedCallCount()).To(Equal(2))
+			Expect(fakeCheckpoint.AttemptedArgsForCall(0)).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
+			Expect(fakeCheckpoint.CompletedCallCount()).To(Equal(2))
+			Expect(fakeCheckpoint.CompletedArgsForCall(1)).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
+			Expect(fakeCheckpoint.FailedCallCount()).To(Equal(0))
 		})
 
-		Context("when fetching the list of service plan instances fails", func() {
+		Context("when a deletion fails", func() {
 			BeforeEach(func() {
-				fakeCfClient = fakeCfClientFactory(
-					successfulGetServicesResponse(),
-					successfulGetServicePlansResponse(),
-					serviceInstanceResult{nil, testError},
-				)
+				fakeCfClient.DeleteServiceInstanceReturnsOnCall(0, false, testError)
 			})
 
-			It("logs the error and fails", func() {
-				expectErrors(reaperError, reaperOutput, testError)
+			It("records the failure", func() {
+				Expect(fakeCheckpoint.FailedCallCount()).To(Equal(1))
+				Expect(fakeCheckpoint.FailedArgsForCall(0)).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
+				Expect(fakeCheckpoint.CompletedCallCount()).To(Equal(1))
 			})
 		})
-	})
 
-	Describe("reaping", func() {
-		Context("if the service plan instance response contains a malformed time string", func() {
-			BeforeEach(func() {
-				servicePlanInstancesResponseWithInvalidTime := successfulGetServicePlanInstancesResponse()
-				servicePlanInstancesResponseWithInvalidTime.serviceInstances[0].Metadata.CreatedAt = "rubbish"
+		Context("when performing a dry run", func() {
+			BeforeEach(func() { reap = false })
 
-				fakeCfClient = fakeCfClientFactory(
-					successfulGetServicesResponse(),
-					successfulGetServicePlansResponse(),
-					servicePlanInstancesResponseWithInvalidTime,
-				)
+			It("records the candidates but attempts no deletions", func() {
+				Expect(fakeCheckpoint.CandidateCallCount()).To(Equal(2))
+				Expect(fakeCheckpoint.AttemptedCallCount()).To(Equal(0))
 			})
+		})
 
-			It("logs the error and fails", func() {
-				expectErrorsMatching(reaperError, reaperOutput, "invalid service instance creation time")
+		Context("when the service plans cannot be listed", func() {
+			BeforeEach(func() {
+				fakeCfClient.GetServicePlansReturns(nil, testError)
+			})
+
+			It("does not record that every candidate was found", func() {
+				Expect(fakeCheckpoint.ListedCallCount()).To(Equal(0))
 			})
 		})
 
-		Context("when the 'reap' flag is true", func() {
-			BeforeEach(func() { reap = true })
+		Context("when progress cannot be checkpointed", func() {
+			BeforeEach(func() {
+				fakeCheckpoint.CompletedReturns(testError)
+			})
 
-			Context("when the 'recursive' flag is false", func() {
-				BeforeEach(func() { recursive = false })
+			It("still reaps and returns checkpoint errors", func() {
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+				errs := reaperError.(reaperpkg.Errors)
+				Expect(errs.OfKind(reaperpkg.CheckpointError)).To(HaveLen(2))
+				Expect(reaperOutput).To(gbytes.Say("unable to checkpoint progress: %s %s \\(test error\\)",
+					testExpiredFreePlanServiceInstanceName1, testExpiredFreePlanServiceInstanceGuid1))
+			})
+		})
 
-				It("deletes only the expired service instances", func() {
-					Expect(reaperError).NotTo(HaveOccurred())
+		Context("when resuming a run which found every candidate", func() {
+			var pendingServiceInstance cloudfoundry.ServiceInstance
 
-					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+			BeforeEach(func() {
+				resume = true
+				serviceInstances := successfulGetServicePlanInstancesResponse().serviceInstances
+				pendingServiceInstance = serviceInstances[1]
+				fakeCheckpoint.StateReturns(checkpoint.State{
+					Listed:     true,
+					Candidates: serviceInstances[:2],
+					Completed:  []string{testExpiredFreePlanServiceInstanceGuid1},
+				})
+				fakeCfClient.GetServiceInstanceReturns(pendingServiceInstance, true, nil)
+			})
 
-					deletedServiceInstanceGuid, deletedRecursively := fakeCfClient.DeleteServiceInstanceArgsForCall(0)
-					Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
-					Expect(deletedRecursively).To(BeFalse())
+			It("deletes the remaining candidates without listing service instances again", func() {
+				Expect(reaperError).NotTo(HaveOccurred())
+				Expect(fakeCfClient.GetServicesCallCount()).To(Equal(0))
+				Expect(fakeCfClient.GetServicePlanInstancesCallCount()).To(Equal(0))
+				Expect(fakeCfClient.GetServiceInstanceCallCount()).To(Equal(1))
+				Expect(fakeCfClient.GetServiceInstanceArgsForCall(0)).To(Equal(pendingServiceInstance.Metadata.Guid))
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+				deletedServiceInstanceGuid, _ := fakeCfClient.DeleteServiceInstanceArgsForCall(0)
+				Expect(deletedServiceInstanceGuid).To(Equal(pendingServiceInstance.Metadata.Guid))
+			})
 
-					deletedServiceInstanceGuid, deletedRecursively = fakeCfClient.DeleteServiceInstanceArgsForCall(1)
-					Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
-					Expect(deletedRecursively).To(BeFalse())
+			Context("when a remaining candidate has since become ineligible", func() {
+				BeforeEach(func() {
+					protectionTag = "do-not-reap"
+					protectedServiceInstance := pendingServiceInstance
+					protectedServiceInstance.Entity.Tags = []string{protectionTag}
+					fakeCfClient.GetServiceInstanceReturns(protectedServiceInstance, true, nil)
+				})
+
+				It("does not delete it", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected number of DeleteServiceInstance invocations")
 				})
 			})
 
-			Context("when the 'recursive' flag is true", func() {
-				BeforeEach(func() { recursive = true })
+			Context("when a remaining candidate has since changed plan", func() {
+				BeforeEach(func() {
+					changedServiceInstance := pendingServiceInstance
+					changedServiceInstance.Entity.ServicePlanGuid = "other-plan-guid"
+					fakeCfClient.GetServiceInstanceReturns(changedServiceInstance, true, nil)
+				})
 
-				It("deletes only the expired service instances, recursively", func() {
+				It("does not delete it", func() {
 					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected number of DeleteServiceInstance invocations")
+				})
+			})
 
-					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(2), "Unexpected number of DeleteServiceInstance invocations")
+			Context("when a remaining candidate no longer exists", func() {
+				BeforeEach(func() {
+					fakeCfClient.GetServiceInstanceReturns(cloudfoundry.ServiceInstance{}, false, nil)
+				})
 
-					deletedServiceInstanceGuid, deletedRecursively := fakeCfClient.DeleteServiceInstanceArgsForCall(0)
-					Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid1))
-					Expect(deletedRecursively).To(BeTrue())
+				It("neither deletes it nor records it as deleted", func() {
+					Expect(reaperError).NotTo(HaveOccurred())
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected number of DeleteServiceInstance invocations")
+					Expect(fakeCheckpoint.CompletedCallCount()).To(Equal(0))
+				})
 
-					deletedServiceInstanceGuid, _ = fakeCfClient.DeleteServiceInstanceArgsForCall(1)
-					Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
-					Expect(deletedRecursively).To(BeTrue())
+				Context("because the interrupted run deleted it", func() {
+					BeforeEach(func() {
+						fakeCheckpoint.StateReturns(checkpoint.State{
+							Listed:     true,
+							Candidates: successfulGetServicePlanInstancesResponse().serviceInstances[:2],
+							Attempted:  []string{testExpiredFreePlanServiceInstanceGuid1, pendingServiceInstance.Metadata.Guid},
+							Completed:  []string{testExpiredFreePlanServiceInstanceGuid1},
+						})
+					})
+
+					It("records it as deleted", func() {
+						Expect(reaperError).NotTo(HaveOccurred())
+						Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected number of DeleteServiceInstance invocations")
+						Expect(fakeCheckpoint.CompletedCallCount()).To(Equal(1))
+						Expect(fakeCheckpoint.CompletedArgsForCall(0)).To(Equal(pendingServiceInstance.Metadata.Guid))
+						Expect(reaperOutput).To(gbytes.Say("was deleted by the interrupted run"))
+					})
 				})
 			})
 
-			Context("when service instance deletion fails", func() {
+			Context("when a remaining candidate cannot be fetched", func() {
 				BeforeEach(func() {
-					fakeCfClient.DeleteServiceInstanceReturns(testError)
+					fakeCfClient.GetServiceInstanceReturns(cloudfoundry.ServiceInstance{}, false, testError)
 				})
 
-				It("logs the error and fails", func() {
-					const errorMessage = "unable to delete service instance: %s %s \\(%s\\)\n"
-					expectErrorsMatching(reaperError, reaperOutput,
-						fmt.Sprintf(errorMessage, testExpiredFreePlanServiceInstanceName1, testExpiredFreePlanServiceInstanceGuid1, testError),
-						fmt.Sprintf(errorMessage, testExpiredFreePlanServiceInstanceName2, testExpiredFreePlanServiceInstanceGuid2, testError),
-					)
+				It("does not delete it and returns a listing error", func() {
+					Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected number of DeleteServiceInstance invocations")
+					errs := reaperError.(reaperpkg.Errors)
+					Expect(errs.OfKind(reaperpkg.ListingError)).To(HaveLen(1))
+					Expect(reaperOutput).To(gbytes.Say("unable to re-check service instance"))
 				})
 			})
 		})
 
-		Context("when the 'reap' flag is false", func() {
+		Context("when resuming a run which was interrupted whilst finding candidates", func() {
+			BeforeEach(func() {
+				resume = true
+				fakeCheckpoint.StateReturns(checkpoint.State{
+					Completed: []string{testExpiredFreePlanServiceInstanceGuid1},
+				})
+			})
+
+			It("lists service instances again but skips those already deleted", func() {
+				Expect(fakeCfClient.GetServicePlanInstancesCallCount()).To(Equal(1))
+				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(1), "Unexpected number of DeleteServiceInstance invocations")
+				deletedServiceInstanceGuid, _ := fakeCfClient.DeleteServiceInstanceArgsForCall(0)
+				Expect(deletedServiceInstanceGuid).To(Equal(testExpiredFreePlanServiceInstanceGuid2))
+			})
+		})
+	})
+
+	Describe("recording history", func() {
+		var fakeRecorder *historyfakes.FakeRecorder
+
+		BeforeEach(func() {
+			fakeRecorder = &historyfakes.FakeRecorder{}
+			historyRecorder = fakeRecorder
+			fakeCfClient.DeleteServiceInstanceReturnsOnCall(1, false, testError)
+		})
+
+		It("records the outcome of each deletion", func() {
+			Expect(fakeRecorder.RecordCallCount()).To(Equal(2))
+			Expect(fakeRecorder.RecordArgsForCall(0)).To(Equal(history.Deletion{
+				ServiceInstanceGuid: testExpiredFreePlanServiceInstanceGuid1,
+				ServiceInstanceName: testExpiredFreePlanServiceInstanceName1,
+			}))
+			Expect(fakeRecorder.RecordArgsForCall(1)).To(Equal(history.Deletion{
+				ServiceInstanceGuid: testExpiredFreePlanServiceInstanceGuid2,
+				ServiceInstanceName: testExpiredFreePlanServiceInstanceName2,
+				Error:               testError.Error(),
+			}))
+		})
+
+		Context("when performing a dry run", func() {
 			BeforeEach(func() { reap = false })
 
-			It("logs only the expired service instance names and guids", func() {
-				Expect(reaperError).NotTo(HaveOccurred())
-				Expect(reaperOutput).To(gbytes.Say("%s %s\n", testExpiredFreePlanServiceInstanceName1, testExpiredFreePlanServiceInstanceGuid1))
-				Expect(reaperOutput).To(gbytes.Say("%s %s\n", testExpiredFreePlanServiceInstanceName2, testExpiredFreePlanServiceInstanceGuid2))
-				Expect(reaperOutput).NotTo(gbytes.Say("%s %s\n", testNotExpiredFreePlanServiceInstanceName, testNotExpiredFreePlanServiceInstanceGuid))
-				Expect(fakeCfClient.DeleteServiceInstanceCallCount()).To(Equal(0), "Unexpected call to DeleteServiceInstance!")
+			It("records nothing", func() {
+				Expect(fakeRecorder.RecordCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("costing", func() {
+		BeforeEach(func() {
+			prices = pricing.NewTable(pricing.Price{Service: testServiceName, Plan: testFreeServicePlanName, HourlyCost: 1})
+		})
+
+		It("reports the cost of each candidate since its creation", func() {
+			Expect(reaperOutput).To(gbytes.Say(testExpiredFreePlanServiceInstanceName1 + " " + testExpiredFreePlanServiceInstanceGuid1 + " \\(cost 15.00 since creation, 730.00 per month\\)"))
+			Expect(reaperOutput).To(gbytes.Say(testExpiredFreePlanServiceInstanceName2 + " " + testExpiredFreePlanServiceInstanceGuid2 + " \\(cost 10.00 since creation, 730.00 per month\\)"))
+		})
+
+		It("summarises the accumulated cost and the monthly savings", func() {
+			Expect(summary.AccumulatedCost).To(BeNumerically("~", 25, 0.01))
+			Expect(summary.MonthlySavings).To(Equal(1460.0))
+			Expect(summary.RealisedMonthlySavings).To(Equal(1460.0))
+			Expect(reaperOutput).To(gbytes.Say("Cost: 25.00 accumulated by candidates since creation, 1460.00 projected monthly savings, 1460.00 realised monthly savings"))
+		})
+
+		Context("when a deletion fails", func() {
+			BeforeEach(func() {
+				fakeCfClient.DeleteServiceInstanceReturnsOnCall(0, false, testError)
+			})
+
+			It("projects the savings from every candidate but realises only those from the service instances reaped", func() {
+				Expect(summary.AccumulatedCost).To(BeNumerically("~", 25, 0.01))
+				Expect(summary.MonthlySavings).To(Equal(1460.0))
+				Expect(summary.RealisedMonthlySavings).To(Equal(730.0))
+			})
+		})
+
+		Context("when performing a dry run", func() {
+			BeforeEach(func() { reap = false })
+
+			It("projects the savings from reaping the candidates but realises none", func() {
+				Expect(summary.MonthlySavings).To(Equal(1460.0))
+				Expect(summary.RealisedMonthlySavings).To(BeZero())
+			})
+		})
+
+		Context("when a candidate's creation time is in the future", func() {
+			BeforeEach(func() {
+				ageBasis = reaperpkg.UpdatedAt
+				serviceInstances := successfulGetServicePlanInstancesResponse()
+				serviceInstances.serviceInstances[0].Metadata.CreatedAt = frozenTime().Add(time.Hour).Format(time.RFC3339)
+				serviceInstances.serviceInstances[0].Metadata.UpdatedAt = fifteenHoursAgo().Format(time.RFC3339)
+				fakeCfClient.GetServicePlanInstancesReturns(serviceInstanceChannels(serviceInstances.serviceInstances, nil))
+			})
+
+			It("counts no cost for it", func() {
+				Expect(reaperOutput).To(gbytes.Say(testExpiredFreePlanServiceInstanceName1 + " " + testExpiredFreePlanServiceInstanceGuid1 + " \\(cost 0.00 since creation, 730.00 per month\\)"))
+			})
+		})
+
+		Context("when the plan is not priced", func() {
+			BeforeEach(func() {
+				prices = pricing.NewTable()
+			})
+
+			It("warns once and reports no cost", func() {
+				Expect(reaperOutput).To(gbytes.Say("No price for plan '%s' of service '%s': costs will not be reported", testFreeServicePlanName, testServiceName))
+				Expect(reaperOutput).NotTo(gbytes.Say("No price for plan"))
+				Expect(summary).To(Equal(reaperpkg.Summary{Candidates: 2, Reaped: 2}))
+				Expect(reaperOutput.Contents()).NotTo(ContainSubstring("(cost"))
+				Expect(reaperOutput).To(gbytes.Say("Cost: 0.00 accumulated by candidates since creation, 0.00 projected monthly savings"))
+			})
+		})
+	})
+
+	Describe("collecting errors", func() {
+		It("returns no error when reaping succeeds", func() {
+			Expect(reaperError).To(BeNil())
+		})
+
+		Context("when a deletion fails", func() {
+			BeforeEach(func() {
+				fakeCfClient.DeleteServiceInstanceReturnsOnCall(0, false, testError)
+			})
+
+			It("returns a deletion error identifying the service instance", func() {
+				errs, ok := reaperError.(reaperpkg.Errors)
+				Expect(ok).To(BeTrue())
+				Expect(errs).To(HaveLen(1))
+				Expect(errs[0].Kind).To(Equal(reaperpkg.DeletionError))
+				Expect(errs[0].Resource).To(Equal(reaperpkg.Resource{
+					Type: "service instance",
+					Name: testExpiredFreePlanServiceInstanceName1,
+					Guid: testExpiredFreePlanServiceInstanceGuid1,
+				}))
+				Expect(errors.Is(errs[0], testError)).To(BeTrue())
+				Expect(errs[0].Error()).To(Equal(fmt.Sprintf("unable to delete service instance: %s %s (test error)",
+					testExpiredFreePlanServiceInstanceName1, testExpiredFreePlanServiceInstanceGuid1)))
+			})
+		})
+
+		Context("when errors of several kinds occur", func() {
+			BeforeEach(func() {
+				serviceInstances := successfulGetServicePlanInstancesResponse()
+				serviceInstances.serviceInstances[1].Metadata.CreatedAt = "yesterday"
+				fakeCfClient.GetServicePlanInstancesReturns(serviceInstanceChannels(serviceInstances.serviceInstances, nil))
+				fakeCfClient.DeleteServiceInstanceReturns(false, testError)
+			})
+
+			It("distinguishes them by kind", func() {
+				errs := reaperError.(reaperpkg.Errors)
+				Expect(errs.OfKind(reaperpkg.ParseError)).To(HaveLen(1))
+				Expect(errs.OfKind(reaperpkg.DeletionError)).To(HaveLen(1))
+				Expect(errs.OfKind(reaperpkg.ParseError, reaperpkg.DeletionError)).To(HaveLen(2))
+				Expect(errs.OfKind(reaperpkg.ListingError)).To(BeEmpty())
+			})
+
+			It("counts and describes them all", func() {
+				Expect(reaperError.Error()).To(MatchRegexp(`^2 errors occurred whilst reaping: unable to determine age of service instance: .*; unable to delete service instance: .* \(test error\)$`))
+			})
+
+			It("exposes each of them", func() {
+				var err *reaperpkg.Error
+				Expect(errors.As(reaperError, &err)).To(BeTrue())
+				Expect(err.Kind).To(Equal(reaperpkg.ParseError))
+				Expect(errors.Is(reaperError, testError)).To(BeTrue())
+			})
+		})
+
+		Context("when many deletions fail", func() {
+			const failures = 150
+
+			BeforeEach(func() {
+				var serviceInstances []cloudfoundry.ServiceInstance
+				for i := 0; i < failures; i++ {
+					serviceInstances = append(serviceInstances, cloudfoundry.ServiceInstance{
+						Metadata: cloudfoundry.Metadata{
+							Guid:      fmt.Sprintf("service-instance-guid-%d", i),
+							CreatedAt: fifteenHoursAgo().Format(time.RFC3339),
+						},
+						Entity: cloudfoundry.ServiceInstanceEntity{Name: fmt.Sprintf("service-instance-%d", i)},
+					})
+				}
+				fakeCfClient.GetServicePlanInstancesReturns(serviceInstanceChannels(serviceInstances, nil))
+				fakeCfClient.DeleteServiceInstanceReturns(false, testError)
+			})
+
+			It("returns every error", func() {
+				Expect(reaperError.(reaperpkg.Errors)).To(HaveLen(failures))
+				Expect(summary.Failed).To(Equal(failures))
 			})
 		})
 	})
@@ -267,24 +2550,56 @@ func fakeCfClientFactory(services []cloudfoundry.Service, servicePlans servicePl
 
 	cf.GetServicePlansReturns(servicePlans.servicePlans, servicePlans.err)
 
-	serviceInstancesChannel := make(chan cloudfoundry.ServiceInstance, len(serviceInstances.serviceInstances))
+	cf.GetServicePlanInstancesReturns(serviceInstanceChannels(serviceInstances.serviceInstances, serviceInstances.err))
+
+	return cf
+}
+
+func serviceInstanceChannels(serviceInstances []cloudfoundry.ServiceInstance, err error) (chan cloudfoundry.ServiceInstance, chan error) {
+	serviceInstancesChannel := make(chan cloudfoundry.ServiceInstance, len(serviceInstances))
 	serviceInstanceErrorsChannel := make(chan error, 1)
 	defer func() { close(serviceInstancesChannel) }()
 	defer func() { close(serviceInstanceErrorsChannel) }()
-	for _, serviceInstance := range serviceInstances.serviceInstances {
+	for _, serviceInstance := range serviceInstances {
 		serviceInstancesChannel <- serviceInstance
 	}
-	if serviceInstances.err != nil {
-		serviceInstanceErrorsChannel <- serviceInstances.err
+	if err != nil {
+		serviceInstanceErrorsChannel <- err
 	}
-	cf.GetServicePlanInstancesReturns(serviceInstancesChannel, serviceInstanceErrorsChannel)
+	return serviceInstancesChannel, serviceInstanceErrorsChannel
+}
 
-	return cf
+func appChannels(apps []cloudfoundry.App, err error) (chan cloudfoundry.App, chan error) {
+	appsChannel := make(chan cloudfoundry.App, len(apps))
+	appErrorsChannel := make(chan error, 1)
+	defer close(appsChannel)
+	defer close(appErrorsChannel)
+	for _, app := range apps {
+		appsChannel <- app
+	}
+	if err != nil {
+		appErrorsChannel <- err
+	}
+	return appsChannel, appErrorsChannel
+}
+
+func spaceChannels(spaces []cloudfoundry.Space, err error) (chan cloudfoundry.Space, chan error) {
+	spacesChannel := make(chan cloudfoundry.Space, len(spaces))
+	spaceErrorsChannel := make(chan error, 1)
+	defer close(spacesChannel)
+	defer close(spaceErrorsChannel)
+	for _, space := range spaces {
+		spacesChannel <- space
+	}
+	if err != nil {
+		spaceErrorsChannel <- err
+	}
+	return spacesChannel, spaceErrorsChannel
 }
 
 func successfulGetServicesResponse() []cloudfoundry.Service {
 	return []cloudfoundry.Service{
-		{cloudfoundry.Metadata{Guid: testServiceGuid}},
+		{Metadata: cloudfoundry.Metadata{Guid: testServiceGuid}},
 	}
 }
 
@@ -292,25 +2607,16 @@ func successfulGetServicePlansResponse() servicePlanResult {
 	return servicePlanResult{
 		servicePlans: []cloudfoundry.ServicePlan{
 			{
-				cloudfoundry.Metadata{Guid: testPaidServicePlanGuid},
-				struct {
-					Name string
-					Free bool
-				}{testPaidServicePlanName, false},
+				Metadata: cloudfoundry.Metadata{Guid: testPaidServicePlanGuid},
+				Entity:   cloudfoundry.ServicePlanEntity{Name: testPaidServicePlanName, Free: false},
 			},
 			{
-				cloudfoundry.Metadata{Guid: testFreeServicePlanGuid},
-				struct {
-					Name string
-					Free bool
-				}{testFreeServicePlanName, true},
+				Metadata: cloudfoundry.Metadata{Guid: testFreeServicePlanGuid},
+				Entity:   cloudfoundry.ServicePlanEntity{Name: testFreeServicePlanName, Free: true},
 			},
 			{
-				cloudfoundry.Metadata{Guid: testSponsoredFreeServicePlanGuid},
-				struct {
-					Name string
-					Free bool
-				}{testSponsoredFreeServicePlanName, true},
+				Metadata: cloudfoundry.Metadata{Guid: testSponsoredFreeServicePlanGuid},
+				Entity:   cloudfoundry.ServicePlanEntity{Name: testSponsoredFreeServicePlanName, Free: true},
 			},
 		},
 		err: nil,
@@ -321,37 +2627,39 @@ func successfulGetServicePlanInstancesResponse() serviceInstanceResult {
 	return serviceInstanceResult{
 		serviceInstances: []cloudfoundry.ServiceInstance{
 			{
-				cloudfoundry.Metadata{
+				Metadata: cloudfoundry.Metadata{
 					Guid:      testExpiredFreePlanServiceInstanceGuid1,
 					CreatedAt: fifteenHoursAgo().Format(time.RFC3339),
 				},
-				struct {
-					Name string
-				}{testExpiredFreePlanServiceInstanceName1},
+				Entity: cloudfoundry.ServiceInstanceEntity{Name: testExpiredFreePlanServiceInstanceName1},
 			},
 			{
-				cloudfoundry.Metadata{
+				Metadata: cloudfoundry.Metadata{
 					Guid:      testExpiredFreePlanServiceInstanceGuid2,
 					CreatedAt: tenHoursOneSecondAgo().Format(time.RFC3339),
 				},
-				struct {
-					Name string
-				}{testExpiredFreePlanServiceInstanceName2},
+				Entity: cloudfoundry.ServiceInstanceEntity{Name: testExpiredFreePlanServiceInstanceName2},
 			},
 			{
-				cloudfoundry.Metadata{
+				Metadata: cloudfoundry.Metadata{
 					Guid:      testNotExpiredFreePlanServiceInstanceGuid,
 					CreatedAt: tenHoursAgo().Format(time.RFC3339),
 				},
-				struct {
-					Name string
-				}{testNotExpiredFreePlanServiceInstanceName},
+				Entity: cloudfoundry.ServiceInstanceEntity{Name: testNotExpiredFreePlanServiceInstanceName},
 			},
 		},
 		err: nil,
 	}
 }
 
+func serviceBindingDeleteEvent(serviceInstanceGuid string, timestamp time.Time) cloudfoundry.Event {
+	event := cloudfoundry.Event{}
+	event.Entity.Type = "audit.service_binding.delete"
+	event.Entity.Timestamp = timestamp.Format(time.RFC3339)
+	event.Entity.Metadata.Request.ServiceInstanceGuid = serviceInstanceGuid
+	return event
+}
+
 func frozenTime() time.Time {
 	return time.Date(2018, 1, 24, 20, 00, 0, 0, time.UTC)
 }
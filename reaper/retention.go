@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reaper
+
+import (
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Grouping determines which service instances are counted together when retaining the newest of them.
+type Grouping string
+
+const (
+	// BySpace groups the service instances of each space.
+	BySpace Grouping = "space"
+	// ByNamePrefix groups service instances whose names are the same up to the last hyphen, so perf-db-1 and
+	// perf-db-2 share the prefix perf-db. A name without a hyphen after its first character is its own prefix.
+	ByNamePrefix Grouping = "name_prefix"
+	// ByTags groups service instances with the same set of service tags, in any order.
+	ByTags Grouping = "tags"
+)
+
+// labelGroupingPrefix introduces a grouping by the value of the metadata label with the key which follows it.
+const labelGroupingPrefix = "label:"
+
+var Groupings = []Grouping{BySpace, ByNamePrefix, ByTags}
+
+// ByLabel groups service instances with the same value of the metadata label with the given key. Service instances
+// without the label form a group of their own.
+func ByLabel(key string) Grouping {
+	return Grouping(labelGroupingPrefix + key)
+}
+
+func ParseGrouping(grouping string) (Grouping, error) {
+	for _, g := range Groupings {
+		if string(g) == grouping {
+			return g, nil
+		}
+	}
+
+	if strings.HasPrefix(grouping, labelGroupingPrefix) && len(grouping) > len(labelGroupingPrefix) {
+		return Grouping(grouping), nil
+	}
+
+	names := make([]string, len(Groupings))
+	for i, g := range Groupings {
+		names[i] = string(g)
+	}
+	return "", fmt.Errorf("invalid grouping: %s (must be one of %s, or %sKEY)", grouping, strings.Join(names, ", "), labelGroupingPrefix)
+}
+
+// label returns the key of the metadata label by which the grouping groups, if it does.
+func (g Grouping) label() (string, bool) {
+	if !strings.HasPrefix(string(g), labelGroupingPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(string(g), labelGroupingPrefix), true
+}
+
+// key identifies the group of the given service instance for any grouping other than by label. Every grouping is
+// also by space, so that service instances in one space never cause those in another to be reaped.
+func (g Grouping) key(serviceInstance cloudfoundry.ServiceInstance) string {
+	switch g {
+	case ByNamePrefix:
+		name := serviceInstance.Entity.Name
+		if i := strings.LastIndex(name, "-"); i > 0 {
+			name = name[:i]
+		}
+		return serviceInstance.Entity.SpaceGuid + "/" + name
+	case ByTags:
+		tags := append([]string{}, serviceInstance.Entity.Tags...)
+		sort.Strings(tags)
+		return serviceInstance.Entity.SpaceGuid + "/" + strings.Join(tags, ",")
+	default:
+		return serviceInstance.Entity.SpaceGuid
+	}
+}
+
+type retentionCandidate struct {
+	serviceInstance cloudfoundry.ServiceInstance
+	referenceTime   time.Time
+}
+
+// groupKey identifies the group of the given service instance, looking up its labels if grouping by one.
+func (r *Reaper) groupKey(serviceInstance cloudfoundry.ServiceInstance) (string, error) {
+	labelKey, ok := r.options.KeepGrouping.label()
+	if !ok {
+		return r.options.KeepGrouping.key(serviceInstance), nil
+	}
+
+	labels, err := r.cf.GetServiceInstanceLabels(serviceInstance.Metadata.Guid)
+	if err != nil {
+		return "", err
+	}
+	return serviceInstance.Entity.SpaceGuid + "/" + labels[labelKey], nil
+}
+
+// unretainedInstancesOf passes on all but the newest KeepNewest service instances of each group, measuring age in
+// the same way as expiry. It must see every service instance before it can pass any on.
+func (r *Reaper) unretainedInstancesOf(serviceInstances <-chan cloudfoundry.ServiceInstance) <-chan cloudfoundry.ServiceInstance {
+	if r.options.KeepNewest == 0 {
+		return serviceInstances
+	}
+
+	output := make(chan cloudfoundry.ServiceInstance, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		groups := make(map[string][]retentionCandidate)
+		keys := make([]string, 0)
+		for serviceInstance := range serviceInstances {
+			referenceTime, err := r.referenceTime(serviceInstance)
+			if err != nil {
+				r.fail(newError(ParseError, serviceInstanceResource(serviceInstance), "unable to determine age of service instance", err))
+				continue
+			}
+
+			key, err := r.groupKey(serviceInstance)
+			if err != nil {
+				r.fail(newError(ListingError, serviceInstanceResource(serviceInstance), "unable to list labels of service instance", err))
+				continue
+			}
+
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], retentionCandidate{serviceInstance: serviceInstance, referenceTime: referenceTime})
+		}
+
+		for _, key := range keys {
+			candidates := groups[key]
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return candidates[i].referenceTime.After(candidates[j].referenceTime)
+			})
+
+			for i := r.options.KeepNewest; i < len(candidates); i++ {
+				output <- candidates[i].serviceInstance
+			}
+		}
+	}()
+
+	return output
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reaper
+
+import (
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/audit"
+	"github.com/pivotal-cf/service-instance-reaper/checkpoint"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"github.com/pivotal-cf/service-instance-reaper/confirm"
+	"github.com/pivotal-cf/service-instance-reaper/history"
+	"time"
+)
+
+// serviceKeyCandidate is an expired service key together with the service instance it belongs to.
+type serviceKeyCandidate struct {
+	serviceKey      cloudfoundry.ServiceKey
+	serviceInstance cloudfoundry.ServiceInstance
+	age             time.Duration
+}
+
+// reapServiceKeysOf reaps the expired service keys of each service instance and passes the service instances on
+// unchanged. The service keys are candidates in their own right: they are subject to MaxDeletions, which caps the
+// number of service keys deleted, to Confirmer, and to the checkpoint, audit trail, and history. Every service key
+// must be found before the cap can be applied, so no service instance is passed on until the service keys have been
+// reaped. This also ensures that the keys of a service instance are gone before it is deleted.
+func (r *Reaper) reapServiceKeysOf(serviceInstances <-chan cloudfoundry.ServiceInstance) <-chan cloudfoundry.ServiceInstance {
+	if r.options.ServiceKeyExpiryInterval == 0 {
+		return serviceInstances
+	}
+
+	output := make(chan cloudfoundry.ServiceInstance, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		var listed []cloudfoundry.ServiceInstance
+		var candidates []serviceKeyCandidate
+		for serviceInstance := range serviceInstances {
+			if !serviceInstance.UserProvided() {
+				candidates = append(candidates, r.expiredServiceKeysOf(serviceInstance)...)
+			}
+			listed = append(listed, serviceInstance)
+		}
+
+		r.deleteServiceKeys(r.confirmedServiceKeysOf(r.cappedServiceKeysOf(candidates)))
+
+		for _, serviceInstance := range listed {
+			output <- serviceInstance
+		}
+	}()
+
+	return output
+}
+
+func (r *Reaper) expiredServiceKeysOf(serviceInstance cloudfoundry.ServiceInstance) (candidates []serviceKeyCandidate) {
+	serviceKeys, err := r.cf.GetServiceKeys(serviceInstance.Metadata.Guid)
+	if err != nil {
+		r.fail(newError(ListingError, serviceInstanceResource(serviceInstance), "unable to list service keys of service instance", err))
+		return nil
+	}
+
+	for _, serviceKey := range serviceKeys {
+		if r.options.ServiceKeyNamePattern != nil && !r.options.ServiceKeyNamePattern.MatchString(serviceKey.Entity.Name) {
+			continue
+		}
+
+		creationTime, err := time.Parse(time.RFC3339, serviceKey.Metadata.CreatedAt)
+		if err != nil {
+			r.fail(newError(ParseError, serviceKeyResource(serviceKey), "invalid service key creation time", err))
+			continue
+		}
+
+		if expired(creationTime, r.options.ServiceKeyExpiryInterval, r.currentTime) {
+			candidates = append(candidates, serviceKeyCandidate{
+				serviceKey:      serviceKey,
+				serviceInstance: serviceInstance,
+				age:             r.currentTime().Sub(creationTime),
+			})
+		}
+	}
+
+	return candidates
+}
+
+// cappedServiceKeysOf drops every service key if MaxDeletions is set and more than that many would be deleted.
+func (r *Reaper) cappedServiceKeysOf(candidates []serviceKeyCandidate) []serviceKeyCandidate {
+	if r.options.MaxDeletions == 0 || !r.options.Reap || len(candidates) <= r.options.MaxDeletions {
+		return candidates
+	}
+
+	r.tally.add(func(summary *Summary) { summary.SafetyCapExceeded = true })
+	r.fail(newError(SafetyCapError, Resource{}, "", fmt.Errorf("refusing to delete %d service keys, more than the maximum of %d",
+		len(candidates), r.options.MaxDeletions)))
+	return nil
+}
+
+// confirmedServiceKeysOf keeps only the service keys whose deletion Confirmer confirms, asking about each in turn or,
+// if ConfirmBatch is set, once about them all. Nothing is asked during a dry run.
+func (r *Reaper) confirmedServiceKeysOf(candidates []serviceKeyCandidate) []serviceKeyCandidate {
+	if r.options.Confirmer == nil || !r.options.Reap || len(candidates) == 0 {
+		return candidates
+	}
+
+	if r.options.ConfirmBatch {
+		descriptions := make([]confirm.Candidate, len(candidates))
+		for i, candidate := range candidates {
+			descriptions[i] = candidate.describe()
+		}
+		answer, err := r.askBatch(descriptions)
+		if err != nil {
+			r.fail(newError(ConfirmationError, Resource{}, "unable to confirm deletion of service keys", err))
+		}
+		if answer != confirm.All {
+			return nil
+		}
+		return candidates
+	}
+
+	var confirmed []serviceKeyCandidate
+	answer := confirm.No
+	for _, candidate := range candidates {
+		if answer != confirm.All {
+			var err error
+			answer, err = r.ask(candidate.describe())
+			if err != nil {
+				r.fail(newError(ConfirmationError, serviceKeyResource(candidate.serviceKey), "unable to confirm deletion of service key", err))
+			}
+		}
+		if answer == confirm.Quit {
+			break
+		}
+		if answer == confirm.Yes || answer == confirm.All {
+			confirmed = append(confirmed, candidate)
+		}
+	}
+	return confirmed
+}
+
+func (c serviceKeyCandidate) describe() confirm.Candidate {
+	return confirm.Candidate{
+		Type:                confirm.ServiceKeyType,
+		Name:                c.serviceKey.Entity.Name,
+		Guid:                c.serviceKey.Metadata.Guid,
+		Age:                 c.age,
+		ServiceInstanceName: c.serviceInstance.Entity.Name,
+	}
+}
+
+func (r *Reaper) deleteServiceKeys(candidates []serviceKeyCandidate) {
+	for _, candidate := range candidates {
+		if r.failingFast() || (r.options.Reap && !r.scheduled()) {
+			return
+		}
+
+		serviceKey := candidate.serviceKey
+		if r.options.Reap {
+			r.checkpointServiceKey(serviceKey, checkpoint.Checkpoint.Attempted)
+
+			if err := r.cf.DeleteServiceKey(serviceKey.Metadata.Guid); err != nil {
+				failure := newError(DeletionError, serviceKeyResource(serviceKey), "unable to delete service key", err)
+				r.checkpointServiceKey(serviceKey, checkpoint.Checkpoint.Failed)
+				r.reportAuditFailure(r.auditServiceKey(candidate, audit.Failed, err.Error()))
+				r.recordServiceKeyHistory(candidate, failure)
+				r.fail(failure)
+				continue
+			}
+
+			r.checkpointServiceKey(serviceKey, checkpoint.Checkpoint.Completed)
+			r.reportAuditFailure(r.auditServiceKey(candidate, audit.Deleted, ""))
+			r.recordServiceKeyHistory(candidate, nil)
+		}
+
+		r.logger.Info(fmt.Sprintf("%s %s (service key of %s)", serviceKey.Entity.Name, serviceKey.Metadata.Guid, candidate.serviceInstance.Entity.Name),
+			"service_key_name", serviceKey.Entity.Name, "service_key_guid", serviceKey.Metadata.Guid,
+			"service_instance_guid", candidate.serviceInstance.Metadata.Guid, "reaped", r.options.Reap)
+	}
+}
+
+// checkpointServiceKey records the progress of deleting a service key.
+func (r *Reaper) checkpointServiceKey(serviceKey cloudfoundry.ServiceKey, record func(checkpoint.Checkpoint, string) error) {
+	r.checkpoint(serviceKeyResource(serviceKey), func(c checkpoint.Checkpoint) error {
+		return record(c, serviceKey.Metadata.Guid)
+	})
+}
+
+func (r *Reaper) auditServiceKey(candidate serviceKeyCandidate, action string, detail string) *Error {
+	return r.record(serviceKeyResource(candidate.serviceKey), audit.Entry{
+		Action:              action,
+		ServiceInstanceGuid: candidate.serviceInstance.Metadata.Guid,
+		ServiceInstanceName: candidate.serviceInstance.Entity.Name,
+		ServiceKeyGuid:      candidate.serviceKey.Metadata.Guid,
+		ServiceKeyName:      candidate.serviceKey.Entity.Name,
+		Detail:              detail,
+	})
+}
+
+// recordServiceKeyHistory adds the outcome of attempting to delete a service key to the history of the run, if any.
+func (r *Reaper) recordServiceKeyHistory(candidate serviceKeyCandidate, failure *Error) {
+	if r.options.History == nil {
+		return
+	}
+
+	deletion := history.Deletion{
+		ServiceInstanceGuid: candidate.serviceInstance.Metadata.Guid,
+		ServiceInstanceName: candidate.serviceInstance.Entity.Name,
+		ServiceKeyGuid:      candidate.serviceKey.Metadata.Guid,
+		ServiceKeyName:      candidate.serviceKey.Entity.Name,
+		SpaceGuid:           candidate.serviceInstance.Entity.SpaceGuid,
+	}
+	if failure != nil {
+		deletion.Error = failure.Err.Error()
+	}
+	r.options.History.Record(deletion)
+}
@@ -0,0 +1,292 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reaper
+
+import (
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/audit"
+	"github.com/pivotal-cf/service-instance-reaper/checkpoint"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"github.com/pivotal-cf/service-instance-reaper/confirm"
+	"github.com/pivotal-cf/service-instance-reaper/history"
+	"sync"
+	"time"
+)
+
+// spaceLedger records, space by space, what this run has deleted but the Cloud Controller may still count: service
+// instances whose deletion is in progress and, in a dry run, the resources which would have been deleted.
+type spaceLedger struct {
+	dryRun     map[string]cloudfoundry.SpaceUsage
+	inProgress map[string][]string
+	mutex      sync.Mutex
+}
+
+// wouldDelete records a resource in the given space which a dry run would have deleted.
+func (l *spaceLedger) wouldDelete(spaceGuid string, update func(usage *cloudfoundry.SpaceUsage)) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	usage := l.dryRun[spaceGuid]
+	update(&usage)
+	l.dryRun[spaceGuid] = usage
+}
+
+// deleting records a service instance in the given space whose deletion is in progress.
+func (l *spaceLedger) deleting(spaceGuid string, serviceInstanceGuid string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.inProgress[spaceGuid] = append(l.inProgress[spaceGuid], serviceInstanceGuid)
+}
+
+// remaining returns the usage of the given space less what a dry run would have deleted from it, together with the
+// service instances in it whose deletion is in progress.
+func (l *spaceLedger) remaining(spaceGuid string, usage cloudfoundry.SpaceUsage) (cloudfoundry.SpaceUsage, []string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	deleted := l.dryRun[spaceGuid]
+	usage.Apps -= deleted.Apps
+	usage.ServiceInstances -= deleted.ServiceInstances
+	usage.Routes -= deleted.Routes
+	return usage, l.inProgress[spaceGuid]
+}
+
+// spaceCandidate is an expired empty space.
+type spaceCandidate struct {
+	space cloudfoundry.Space
+	age   time.Duration
+}
+
+// deleteEmptySpaces deletes the spaces which reaping has left empty. It runs only once reaping is complete, so that a
+// space emptied by this run is deleted by the same run. A dry run reports the spaces which it would have left empty.
+// The spaces are candidates in their own right: they are subject to MaxDeletions, which caps the number of spaces
+// deleted, to Confirmer, and to the checkpoint, audit trail, and history.
+func (r *Reaper) deleteEmptySpaces() {
+	var candidates []spaceCandidate
+
+	spaces, spaceErrors := r.cf.GetSpaces()
+	for space := range spaces {
+		if !r.spaceMatches(space) {
+			continue
+		}
+
+		creationTime, err := time.Parse(time.RFC3339, space.Metadata.CreatedAt)
+		if err != nil {
+			r.fail(newError(ParseError, spaceResource(space), "invalid space creation time", err))
+			continue
+		}
+
+		if !expired(creationTime, r.options.EmptySpaceExpiryInterval, r.currentTime) {
+			continue
+		}
+
+		empty, err := r.empty(space)
+		if err != nil {
+			r.fail(newError(ListingError, spaceResource(space), "unable to determine whether space is empty", err))
+			continue
+		}
+
+		if empty {
+			candidates = append(candidates, spaceCandidate{space: space, age: r.currentTime().Sub(creationTime)})
+		}
+	}
+	r.mergeListingErrors(spaceErrors)
+
+	r.deleteSpaces(r.confirmedSpacesOf(r.cappedSpacesOf(candidates)))
+}
+
+// empty reports whether the given space is empty once this run's deletions are complete. Service instances whose
+// deletion is in progress are waited for, checking up to CascadeAttempts times, CascadeRetryInterval apart; a space
+// still holding one afterwards is left for a later run.
+func (r *Reaper) empty(space cloudfoundry.Space) (bool, error) {
+	usage, err := r.cf.GetSpaceUsage(space.Metadata.Guid)
+	if err != nil {
+		return false, err
+	}
+
+	remaining, inProgress := r.emptying.remaining(space.Metadata.Guid, usage)
+	if remaining.Empty() || remaining.Apps > 0 || remaining.Routes > 0 || remaining.ServiceInstances > len(inProgress) {
+		return remaining.Empty(), nil
+	}
+
+	for _, serviceInstanceGuid := range inProgress {
+		deleted, err := r.awaitDeletion(serviceInstanceGuid)
+		if err != nil {
+			return false, err
+		}
+		if !deleted {
+			r.logger.Info(fmt.Sprintf("%s %s not empty: deletion of service instance %s still in progress", space.Entity.Name, space.Metadata.Guid, serviceInstanceGuid),
+				"space_name", space.Entity.Name, "space_guid", space.Metadata.Guid, "service_instance_guid", serviceInstanceGuid)
+			return false, nil
+		}
+	}
+
+	usage, err = r.cf.GetSpaceUsage(space.Metadata.Guid)
+	return usage.Empty(), err
+}
+
+// awaitDeletion waits for the given service instance, whose deletion is in progress, to disappear. It reports
+// whether it did so.
+func (r *Reaper) awaitDeletion(serviceInstanceGuid string) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		serviceInstance, found, err := r.cf.GetServiceInstance(serviceInstanceGuid)
+		if err != nil {
+			return false, err
+		}
+		if !found {
+			return true, nil
+		}
+
+		if serviceInstance.Entity.LastOperation.State == cloudfoundry.LastOperationFailed || attempt >= r.options.CascadeAttempts {
+			return false, nil
+		}
+		time.Sleep(r.options.CascadeRetryInterval)
+	}
+}
+
+func (r *Reaper) spaceMatches(space cloudfoundry.Space) bool {
+	if r.options.SpaceGuid != "" && space.Metadata.Guid != r.options.SpaceGuid {
+		return false
+	}
+
+	return r.options.EmptySpaceNamePattern.MatchString(space.Entity.Name)
+}
+
+// cappedSpacesOf drops every space if MaxDeletions is set and more than that many would be deleted.
+func (r *Reaper) cappedSpacesOf(candidates []spaceCandidate) []spaceCandidate {
+	if r.options.MaxDeletions == 0 || !r.options.Reap || len(candidates) <= r.options.MaxDeletions {
+		return candidates
+	}
+
+	r.tally.add(func(summary *Summary) { summary.SafetyCapExceeded = true })
+	r.fail(newError(SafetyCapError, Resource{}, "", fmt.Errorf("refusing to delete %d spaces, more than the maximum of %d",
+		len(candidates), r.options.MaxDeletions)))
+	return nil
+}
+
+// confirmedSpacesOf keeps only the spaces whose deletion Confirmer confirms, asking about each in turn or, if
+// ConfirmBatch is set, once about them all. Nothing is asked during a dry run.
+func (r *Reaper) confirmedSpacesOf(candidates []spaceCandidate) []spaceCandidate {
+	if r.options.Confirmer == nil || !r.options.Reap || len(candidates) == 0 {
+		return candidates
+	}
+
+	if r.options.ConfirmBatch {
+		descriptions := make([]confirm.Candidate, len(candidates))
+		for i, candidate := range candidates {
+			descriptions[i] = candidate.describe()
+		}
+		answer, err := r.askBatch(descriptions)
+		if err != nil {
+			r.fail(newError(ConfirmationError, Resource{}, "unable to confirm deletion of spaces", err))
+		}
+		if answer != confirm.All {
+			return nil
+		}
+		return candidates
+	}
+
+	var confirmed []spaceCandidate
+	answer := confirm.No
+	for _, candidate := range candidates {
+		if answer != confirm.All {
+			var err error
+			answer, err = r.ask(candidate.describe())
+			if err != nil {
+				r.fail(newError(ConfirmationError, spaceResource(candidate.space), "unable to confirm deletion of space", err))
+			}
+		}
+		if answer == confirm.Quit {
+			break
+		}
+		if answer == confirm.Yes || answer == confirm.All {
+			confirmed = append(confirmed, candidate)
+		}
+	}
+	return confirmed
+}
+
+func (c spaceCandidate) describe() confirm.Candidate {
+	return confirm.Candidate{
+		Type: confirm.SpaceType,
+		Name: c.space.Entity.Name,
+		Guid: c.space.Metadata.Guid,
+		Age:  c.age,
+	}
+}
+
+func (r *Reaper) deleteSpaces(candidates []spaceCandidate) {
+	for _, candidate := range candidates {
+		if r.failingFast() || (r.options.Reap && !r.scheduled()) {
+			return
+		}
+
+		space := candidate.space
+		if r.options.Reap {
+			r.checkpointSpace(space, checkpoint.Checkpoint.Attempted)
+
+			if err := r.cf.DeleteSpace(space.Metadata.Guid); err != nil {
+				failure := newError(DeletionError, spaceResource(space), "unable to delete space", err)
+				r.checkpointSpace(space, checkpoint.Checkpoint.Failed)
+				r.reportAuditFailure(r.auditSpace(space, audit.Failed, err.Error()))
+				r.recordSpaceHistory(space, failure)
+				r.fail(failure)
+				continue
+			}
+
+			r.checkpointSpace(space, checkpoint.Checkpoint.Completed)
+			r.reportAuditFailure(r.auditSpace(space, audit.Deleted, ""))
+			r.recordSpaceHistory(space, nil)
+		}
+
+		r.logger.Info(fmt.Sprintf("%s %s (empty space)", space.Entity.Name, space.Metadata.Guid),
+			"space_name", space.Entity.Name, "space_guid", space.Metadata.Guid, "reaped", r.options.Reap)
+	}
+}
+
+// checkpointSpace records the progress of deleting a space.
+func (r *Reaper) checkpointSpace(space cloudfoundry.Space, record func(checkpoint.Checkpoint, string) error) {
+	r.checkpoint(spaceResource(space), func(c checkpoint.Checkpoint) error {
+		return record(c, space.Metadata.Guid)
+	})
+}
+
+func (r *Reaper) auditSpace(space cloudfoundry.Space, action string, detail string) *Error {
+	return r.record(spaceResource(space), audit.Entry{
+		Action:    action,
+		SpaceGuid: space.Metadata.Guid,
+		SpaceName: space.Entity.Name,
+		Detail:    detail,
+	})
+}
+
+// recordSpaceHistory adds the outcome of attempting to delete a space to the history of the run, if any.
+func (r *Reaper) recordSpaceHistory(space cloudfoundry.Space, failure *Error) {
+	if r.options.History == nil {
+		return
+	}
+
+	deletion := history.Deletion{
+		SpaceGuid: space.Metadata.Guid,
+		SpaceName: space.Entity.Name,
+	}
+	if failure != nil {
+		deletion.Error = failure.Err.Error()
+	}
+	r.options.History.Record(deletion)
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package reaper
+
+import (
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"sync"
+	"time"
+)
+
+// Summary totals the outcome of reaping. Candidates are the service instances, or apps, which are eligible for
+// reaping. Those which are neither reaped nor failed, such as in a dry run or when deletion is declined, are skipped.
+// Dependent resources, such as service keys and routes, are not counted. If service instances are priced,
+// AccumulatedCost is the cost the candidates have accumulated since their creation, MonthlySavings is the projected
+// monthly cost of every candidate, and RealisedMonthlySavings is the monthly cost of those actually reaped, which is
+// none in a dry run.
+type Summary struct {
+	Candidates             int
+	Reaped                 int
+	Skipped                int
+	Failed                 int
+	ListingErrors          int
+	SafetyCapExceeded      bool
+	AccumulatedCost        float64
+	MonthlySavings         float64
+	RealisedMonthlySavings float64
+	Duration               time.Duration
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("%d candidates, %d reaped, %d skipped, %d failed, %d listing errors in %s",
+		s.Candidates, s.Reaped, s.Skipped, s.Failed, s.ListingErrors, s.Duration)
+}
+
+// tally accumulates a summary from the concurrent stages of the pipeline.
+type tally struct {
+	summary Summary
+	mutex   sync.Mutex
+}
+
+func (t *tally) add(update func(summary *Summary)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	update(&t.summary)
+}
+
+func (r *Reaper) countCandidate() {
+	r.tally.add(func(summary *Summary) { summary.Candidates++ })
+}
+
+func (r *Reaper) countReaped() {
+	r.tally.add(func(summary *Summary) { summary.Reaped++ })
+}
+
+// deletionFailed reports an error which prevented a candidate from being deleted.
+func (r *Reaper) deletionFailed(err *Error) {
+	r.tally.add(func(summary *Summary) { summary.Failed++ })
+	r.fail(err)
+}
+
+// summarise completes the summary once reaping is complete.
+func (r *Reaper) summarise(started time.Time) Summary {
+	r.tally.add(func(summary *Summary) {
+		summary.Skipped = summary.Candidates - summary.Reaped - summary.Failed
+		summary.ListingErrors = len(r.errors.collected().OfKind(ListingError, ParseError))
+		summary.Duration = r.currentTime().Sub(started)
+	})
+	summary := r.tally.summary
+	r.logger.Info(fmt.Sprintf("Summary: %s", summary),
+		"candidates", summary.Candidates, "reaped", summary.Reaped, "skipped", summary.Skipped, "failed", summary.Failed,
+		"listing_errors", summary.ListingErrors, "safety_cap_exceeded", summary.SafetyCapExceeded, "duration", summary.Duration.String())
+	if r.options.Prices != nil {
+		r.logger.Info(fmt.Sprintf("Cost: %.2f accumulated by candidates since creation, %.2f projected monthly savings, %.2f realised monthly savings",
+			summary.AccumulatedCost, summary.MonthlySavings, summary.RealisedMonthlySavings),
+			"accumulated_cost", summary.AccumulatedCost, "monthly_savings", summary.MonthlySavings,
+			"realised_monthly_savings", summary.RealisedMonthlySavings)
+	}
+	return summary
+}
+
+// candidatesOf counts the service instances which are eligible for reaping. If MaxDeletions is set and more than
+// that many would be deleted, none are passed on. It must then see every service instance before it can pass any on.
+func (r *Reaper) candidatesOf(serviceInstances <-chan cloudfoundry.ServiceInstance) <-chan cloudfoundry.ServiceInstance {
+	output := make(chan cloudfoundry.ServiceInstance, cloudfoundry.MaximumResultsPerPage)
+
+	go func() {
+		defer close(output)
+
+		if r.options.MaxDeletions == 0 || !r.options.Reap {
+			for serviceInstance := range serviceInstances {
+				r.countCandidate()
+				r.costCandidate(serviceInstance)
+				r.checkpointCandidate(serviceInstance)
+				output <- serviceInstance
+			}
+			r.checkpointListed()
+			return
+		}
+
+		var candidates []cloudfoundry.ServiceInstance
+		for serviceInstance := range serviceInstances {
+			r.countCandidate()
+			r.costCandidate(serviceInstance)
+			r.checkpointCandidate(serviceInstance)
+			candidates = append(candidates, serviceInstance)
+		}
+		r.checkpointListed()
+
+		if len(candidates) > r.options.MaxDeletions {
+			r.tally.add(func(summary *Summary) { summary.SafetyCapExceeded = true })
+			r.fail(newError(SafetyCapError, Resource{}, "", fmt.Errorf("refusing to delete %d service instances, more than the maximum of %d",
+				len(candidates), r.options.MaxDeletions)))
+			return
+		}
+
+		for _, serviceInstance := range candidates {
+			output <- serviceInstance
+		}
+	}()
+
+	return output
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type Snapshot struct {
+	TakenAt         string                        `json:"taken_at"`
+	ServiceInstance cloudfoundry.ServiceInstance  `json:"service_instance"`
+	Parameters      map[string]interface{}        `json:"parameters,omitempty"`
+	ParametersError string                        `json:"parameters_error,omitempty"`
+	ServiceBindings []cloudfoundry.ServiceBinding `json:"service_bindings"`
+	ServiceKeys     []cloudfoundry.ServiceKey     `json:"service_keys"`
+}
+
+//go:generate counterfeiter . Archive
+type Archive interface {
+	Save(serviceInstanceSnapshot Snapshot) (string, error)
+}
+
+type directoryArchive struct {
+	directory string
+}
+
+func NewArchive(directory string) Archive {
+	return &directoryArchive{directory: directory}
+}
+
+// Take captures everything needed to re-create the given service instance. Brokers are not obliged to support
+// fetching parameters, so failure to do so is recorded in the snapshot rather than treated as an error.
+func Take(cf cloudfoundry.Client, serviceInstance cloudfoundry.ServiceInstance, takenAt time.Time) (snapshot Snapshot, err error) {
+	snapshot.TakenAt = takenAt.UTC().Format(time.RFC3339)
+	snapshot.ServiceInstance = serviceInstance
+
+	if !serviceInstance.UserProvided() {
+		snapshot.Parameters, err = cf.GetServiceInstanceParameters(serviceInstance.Metadata.Guid)
+		if err != nil {
+			snapshot.Parameters = nil
+			snapshot.ParametersError = err.Error()
+		}
+	}
+
+	snapshot.ServiceBindings, err = cf.GetServiceBindings(serviceInstance.Metadata.Guid)
+	if err != nil {
+		return
+	}
+
+	snapshot.ServiceKeys, err = cf.GetServiceKeys(serviceInstance.Metadata.Guid)
+	return
+}
+
+func Load(path string) (snapshot Snapshot, err error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return snapshot, fmt.Errorf("cannot read snapshot %s: %s", path, err)
+	}
+
+	err = json.Unmarshal(contents, &snapshot)
+	if err != nil {
+		return snapshot, fmt.Errorf("invalid snapshot %s: %s", path, err)
+	}
+
+	return snapshot, nil
+}
+
+// Restore provisions a new service instance with the name, plan, space, parameters and tags recorded in the snapshot.
+// Bindings and service keys are not re-created. User-provided service instances cannot be restored as their
+// credentials are not recorded. If the snapshot could not record the parameters, the service instance is restored
+// only if forced, since it would be provisioned with the broker's defaults instead.
+func Restore(cf cloudfoundry.Client, snapshot Snapshot, force bool) (cloudfoundry.ServiceInstance, error) {
+	if snapshot.ServiceInstance.UserProvided() {
+		return cloudfoundry.ServiceInstance{}, errors.New("restoring user-provided service instances is not supported")
+	}
+	if snapshot.ParametersError != "" && !force {
+		return cloudfoundry.ServiceInstance{}, fmt.Errorf("the snapshot did not record the parameters of the service instance (%s), so it would be restored without them", snapshot.ParametersError)
+	}
+
+	entity := snapshot.ServiceInstance.Entity
+	return cf.CreateServiceInstance(cloudfoundry.CreateServiceInstanceRequest{
+		Name:            entity.Name,
+		SpaceGuid:       entity.SpaceGuid,
+		ServicePlanGuid: entity.ServicePlanGuid,
+		Parameters:      snapshot.Parameters,
+		Tags:            entity.Tags,
+	})
+}
+
+func (a *directoryArchive) Save(snapshot Snapshot) (string, error) {
+	err := os.MkdirAll(a.directory, 0700)
+	if err != nil {
+		return "", fmt.Errorf("cannot create snapshot directory %s: %s", a.directory, err)
+	}
+
+	contents, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("cannot encode snapshot: %s", err)
+	}
+
+	takenAt, err := time.Parse(time.RFC3339, snapshot.TakenAt)
+	if err != nil {
+		return "", fmt.Errorf("invalid snapshot time: %s", err)
+	}
+
+	path := filepath.Join(a.directory, fmt.Sprintf("%s-%s.json", snapshot.ServiceInstance.Metadata.Guid, takenAt.Format("20060102T150405Z")))
+	err = ioutil.WriteFile(path, contents, 0600)
+	if err != nil {
+		return "", fmt.Errorf("cannot write snapshot %s: %s", path, err)
+	}
+
+	return path, nil
+}
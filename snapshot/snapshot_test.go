@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+ *
+ * This program and the accompanying materials are made available under
+ * the terms of the under the Apache License, Version 2.0 (the "License”);
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package snapshot_test
+
+import (
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry"
+	"github.com/pivotal-cf/service-instance-reaper/cloudfoundry/cloudfoundryfakes"
+	"github.com/pivotal-cf/service-instance-reaper/snapshot"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	testServiceInstanceGuid = "test-service-instance-guid"
+	testServiceInstanceName = "test-service-instance-name"
+	testServicePlanGuid     = "test-service-plan-guid"
+	testSpaceGuid           = "test-space-guid"
+)
+
+var _ = Describe("Snapshot", func() {
+	var (
+		fakeCfClient    *cloudfoundryfakes.FakeClient
+		serviceInstance cloudfoundry.ServiceInstance
+		takenAt         = time.Date(2018, 1, 24, 20, 0, 0, 0, time.UTC)
+		testError       = errors.New("test error")
+	)
+
+	BeforeEach(func() {
+		fakeCfClient = &cloudfoundryfakes.FakeClient{}
+		serviceInstance = cloudfoundry.ServiceInstance{
+			Metadata: cloudfoundry.Metadata{Guid: testServiceInstanceGuid},
+			Entity: cloudfoundry.ServiceInstanceEntity{
+				Name:            testServiceInstanceName,
+				ServicePlanGuid: testServicePlanGuid,
+				SpaceGuid:       testSpaceGuid,
+				Tags:            []string{"tag"},
+			},
+		}
+		fakeCfClient.GetServiceInstanceParametersReturns(map[string]interface{}{"key": "value"}, nil)
+		fakeCfClient.GetServiceBindingsReturns([]cloudfoundry.ServiceBinding{{Metadata: cloudfoundry.Metadata{Guid: "binding-guid"}}}, nil)
+		fakeCfClient.GetServiceKeysReturns([]cloudfoundry.ServiceKey{{Metadata: cloudfoundry.Metadata{Guid: "key-guid"}}}, nil)
+	})
+
+	Describe("Take", func() {
+		var (
+			serviceInstanceSnapshot snapshot.Snapshot
+			err                     error
+		)
+
+		JustBeforeEach(func() {
+			serviceInstanceSnapshot, err = snapshot.Take(fakeCfClient, serviceInstance, takenAt)
+		})
+
+		It("captures the service instance, its parameters, bindings and keys", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceInstanceSnapshot.TakenAt).To(Equal("2018-01-24T20:00:00Z"))
+			Expect(serviceInstanceSnapshot.ServiceInstance).To(Equal(serviceInstance))
+			Expect(serviceInstanceSnapshot.Parameters).To(Equal(map[string]interface{}{"key": "value"}))
+			Expect(serviceInstanceSnapshot.ServiceBindings).To(HaveLen(1))
+			Expect(serviceInstanceSnapshot.ServiceKeys).To(HaveLen(1))
+
+			Expect(fakeCfClient.GetServiceInstanceParametersArgsForCall(0)).To(Equal(testServiceInstanceGuid))
+			Expect(fakeCfClient.GetServiceBindingsArgsForCall(0)).To(Equal(testServiceInstanceGuid))
+			Expect(fakeCfClient.GetServiceKeysArgsForCall(0)).To(Equal(testServiceInstanceGuid))
+		})
+
+		Context("when the parameters cannot be fetched", func() {
+			BeforeEach(func() {
+				fakeCfClient.GetServiceInstanceParametersReturns(nil, testError)
+			})
+
+			It("records the failure in the snapshot", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(serviceInstanceSnapshot.Parameters).To(BeNil())
+				Expect(serviceInstanceSnapshot.ParametersError).To(Equal("test error"))
+			})
+		})
+
+		Context("when the service bindings cannot be fetched", func() {
+			BeforeEach(func() {
+				fakeCfClient.GetServiceBindingsReturns(nil, testError)
+			})
+
+			It("fails", func() {
+				Expect(err).To(MatchError(testError))
+			})
+		})
+
+		Context("when the service keys cannot be fetched", func() {
+			BeforeEach(func() {
+				fakeCfClient.GetServiceKeysReturns(nil, testError)
+			})
+
+			It("fails", func() {
+				Expect(err).To(MatchError(testError))
+			})
+		})
+	})
+
+	Describe("Archive", func() {
+		var directory string
+
+		BeforeEach(func() {
+			var err error
+			directory, err = ioutil.TempDir("", "snapshots")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(directory)
+		})
+
+		It("saves snapshots which can be loaded again", func() {
+			serviceInstanceSnapshot, err := snapshot.Take(fakeCfClient, serviceInstance, takenAt)
+			Expect(err).NotTo(HaveOccurred())
+
+			path, err := snapshot.NewArchive(filepath.Join(directory, "nested")).Save(serviceInstanceSnapshot)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(path).To(Equal(filepath.Join(directory, "nested", testServiceInstanceGuid+"-20180124T200000Z.json")))
+
+			loadedSnapshot, err := snapshot.Load(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loadedSnapshot).To(Equal(serviceInstanceSnapshot))
+		})
+
+		It("fails to load a missing snapshot", func() {
+			_, err := snapshot.Load(filepath.Join(directory, "missing.json"))
+			Expect(err).To(MatchError(ContainSubstring("cannot read snapshot")))
+		})
+	})
+
+	Describe("Restore", func() {
+		It("re-creates the service instance with the same name, plan, space, parameters and tags", func() {
+			fakeCfClient.CreateServiceInstanceReturns(cloudfoundry.ServiceInstance{Metadata: cloudfoundry.Metadata{Guid: "new-guid"}}, nil)
+
+			restoredServiceInstance, err := snapshot.Restore(fakeCfClient, snapshot.Snapshot{
+				ServiceInstance: serviceInstance,
+				Parameters:      map[string]interface{}{"key": "value"},
+			}, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restoredServiceInstance.Metadata.Guid).To(Equal("new-guid"))
+
+			Expect(fakeCfClient.CreateServiceInstanceArgsForCall(0)).To(Equal(cloudfoundry.CreateServiceInstanceRequest{
+				Name:            testServiceInstanceName,
+				SpaceGuid:       testSpaceGuid,
+				ServicePlanGuid: testServicePlanGuid,
+				Parameters:      map[string]interface{}{"key": "value"},
+				Tags:            []string{"tag"},
+			}))
+		})
+
+		Context("when the snapshot did not record the parameters", func() {
+			var serviceInstanceSnapshot snapshot.Snapshot
+
+			BeforeEach(func() {
+				serviceInstanceSnapshot = snapshot.Snapshot{ServiceInstance: serviceInstance, ParametersError: "not supported"}
+			})
+
+			It("refuses to restore the service instance", func() {
+				_, err := snapshot.Restore(fakeCfClient, serviceInstanceSnapshot, false)
+				Expect(err).To(MatchError(ContainSubstring("the snapshot did not record the parameters of the service instance (not supported)")))
+				Expect(fakeCfClient.CreateServiceInstanceCallCount()).To(Equal(0))
+			})
+
+			It("restores the service instance without parameters if forced", func() {
+				_, err := snapshot.Restore(fakeCfClient, serviceInstanceSnapshot, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeCfClient.CreateServiceInstanceCallCount()).To(Equal(1))
+				Expect(fakeCfClient.CreateServiceInstanceArgsForCall(0).Parameters).To(BeNil())
+			})
+		})
+	})
+})